@@ -0,0 +1,368 @@
+// Copyright 2022 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package artifact serializes gnark objects (constraint systems, keys, proofs, witnesses and KZG SRS)
+// with a self-describing header.
+//
+// The WriteTo encodings of these objects do not record what they are: reading a BLS12-381 proving key
+// into groth16.NewProvingKey(ecc.BN254) fails obscurely, or worse, succeeds. Write prefixes the
+// encoding with a Header, so that Read can instantiate the right object, and ReadInto can reject an
+// artifact of another kind, curve or backend.
+//
+// Header binary protocol
+//
+//	[magic "gnrk" | format uint16 | kind uint16 | curve uint16 | backend uint16 | circuit hash [32]byte | len(version) uint8 | version]
+//
+// where the integers are big-endian, format is FormatVersion, version is the gnark version which wrote
+// the artifact, and the circuit hash identifies the constraint system the artifact belongs to (zero
+// if unknown, see CircuitHash).
+package artifact
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/consensys/gnark"
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/kzg"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/frontend"
+)
+
+// FormatVersion is the version of the header format written by this package
+const FormatVersion uint16 = 1
+
+var magic = [4]byte{'g', 'n', 'r', 'k'}
+
+var (
+	// ErrNotAnArtifact is returned when the input does not start with the artifact magic bytes
+	ErrNotAnArtifact = errors.New("not a gnark artifact")
+	// ErrUnsupportedFormat is returned for a header format more recent than FormatVersion
+	ErrUnsupportedFormat = errors.New("unsupported artifact format")
+	// ErrMismatch is returned by ReadInto when the artifact is not of the expected kind, curve or backend
+	ErrMismatch = errors.New("artifact mismatch")
+)
+
+// Kind of a serialized object
+type Kind uint16
+
+const (
+	UnknownKind Kind = iota
+	ConstraintSystem
+	ProvingKey
+	VerifyingKey
+	Proof
+	Witness
+	SRS
+)
+
+// String returns the string representation of a kind
+func (k Kind) String() string {
+	switch k {
+	case ConstraintSystem:
+		return "constraint system"
+	case ProvingKey:
+		return "proving key"
+	case VerifyingKey:
+		return "verifying key"
+	case Proof:
+		return "proof"
+	case Witness:
+		return "witness"
+	case SRS:
+		return "KZG SRS"
+	default:
+		return "unknown"
+	}
+}
+
+// Header describes a serialized object
+type Header struct {
+	Format      uint16
+	Kind        Kind
+	Curve       ecc.ID
+	Backend     backend.ID // backend.UNKNOWN for witnesses and SRS
+	CircuitHash [32]byte
+	Version     string // gnark version
+}
+
+// String returns a description of the artifact, for example "BN254 groth16 proving key"
+func (h Header) String() string {
+	if h.Backend == backend.UNKNOWN {
+		return fmt.Sprintf("%s %s", h.Curve, h.Kind)
+	}
+	return fmt.Sprintf("%s %s %s", h.Curve, h.Backend, h.Kind)
+}
+
+// WriteTo writes the binary encoding of the header to w
+func (h *Header) WriteTo(w io.Writer) (int64, error) {
+	if len(h.Version) > 255 {
+		return 0, errors.New("version string too long")
+	}
+	var buf bytes.Buffer
+	buf.Write(magic[:])
+	for _, v := range []uint16{h.Format, uint16(h.Kind), uint16(h.Curve), uint16(h.Backend)} {
+		_ = binary.Write(&buf, binary.BigEndian, v)
+	}
+	buf.Write(h.CircuitHash[:])
+	buf.WriteByte(uint8(len(h.Version)))
+	buf.WriteString(h.Version)
+
+	n, err := w.Write(buf.Bytes())
+	return int64(n), err
+}
+
+// ReadFrom reads a header from r
+func (h *Header) ReadFrom(r io.Reader) (int64, error) {
+	var fixed [4 + 4*2 + 32 + 1]byte
+	n, err := io.ReadFull(r, fixed[:])
+	if err != nil {
+		if err == io.ErrUnexpectedEOF || err == io.EOF {
+			err = ErrNotAnArtifact
+		}
+		return int64(n), err
+	}
+	if !bytes.Equal(fixed[:4], magic[:]) {
+		return int64(n), ErrNotAnArtifact
+	}
+	h.Format = binary.BigEndian.Uint16(fixed[4:6])
+	if h.Format == 0 || h.Format > FormatVersion {
+		return int64(n), fmt.Errorf("%w %d", ErrUnsupportedFormat, h.Format)
+	}
+	h.Kind = Kind(binary.BigEndian.Uint16(fixed[6:8]))
+	h.Curve = ecc.ID(binary.BigEndian.Uint16(fixed[8:10]))
+	h.Backend = backend.ID(binary.BigEndian.Uint16(fixed[10:12]))
+	copy(h.CircuitHash[:], fixed[12:44])
+
+	version := make([]byte, fixed[44])
+	m, err := io.ReadFull(r, version)
+	h.Version = string(version)
+	return int64(n + m), err
+}
+
+// descriptor is the kind, curve and backend of a concrete type
+type descriptor struct {
+	kind    Kind
+	curve   ecc.ID
+	backend backend.ID
+}
+
+var descriptors = make(map[reflect.Type]descriptor)
+
+func init() {
+	register := func(o interface{}, kind Kind, curve ecc.ID, b backend.ID) {
+		descriptors[reflect.TypeOf(o)] = descriptor{kind, curve, b}
+	}
+	for _, curve := range gnark.Curves() {
+		register(groth16.NewCS(curve), ConstraintSystem, curve, backend.GROTH16)
+		register(groth16.NewProvingKey(curve), ProvingKey, curve, backend.GROTH16)
+		register(groth16.NewVerifyingKey(curve), VerifyingKey, curve, backend.GROTH16)
+		register(groth16.NewProof(curve), Proof, curve, backend.GROTH16)
+		register(plonk.NewCS(curve), ConstraintSystem, curve, backend.PLONK)
+		register(plonk.NewProvingKey(curve), ProvingKey, curve, backend.PLONK)
+		register(plonk.NewVerifyingKey(curve), VerifyingKey, curve, backend.PLONK)
+		register(plonk.NewProof(curve), Proof, curve, backend.PLONK)
+		register(kzg.NewSRS(curve), SRS, curve, backend.UNKNOWN)
+	}
+}
+
+// describe returns the header of o, without the circuit hash
+func describe(o interface{}) (Header, error) {
+	if w, ok := o.(*witness.Witness); ok {
+		return Header{Format: FormatVersion, Kind: Witness, Curve: w.CurveID, Version: gnark.Version}, nil
+	}
+	d, ok := descriptors[reflect.TypeOf(o)]
+	if !ok {
+		return Header{}, fmt.Errorf("unsupported artifact type %T", o)
+	}
+	return Header{Format: FormatVersion, Kind: d.kind, Curve: d.curve, Backend: d.backend, Version: gnark.Version}, nil
+}
+
+// New returns an empty object of the kind, curve and backend of the header, in which the artifact
+// can be decoded with ReadFrom (or UnmarshalBinary for a witness)
+func New(h Header) (interface{}, error) {
+	switch h.Kind {
+	case Witness:
+		return witness.New(h.Curve, nil)
+	case SRS:
+		if !isSupported(h.Curve) {
+			return nil, fmt.Errorf("unsupported curve %s", h.Curve)
+		}
+		return kzg.NewSRS(h.Curve), nil
+	}
+	if !isSupported(h.Curve) {
+		return nil, fmt.Errorf("unsupported curve %s", h.Curve)
+	}
+
+	switch h.Backend {
+	case backend.GROTH16:
+		switch h.Kind {
+		case ConstraintSystem:
+			return groth16.NewCS(h.Curve), nil
+		case ProvingKey:
+			return groth16.NewProvingKey(h.Curve), nil
+		case VerifyingKey:
+			return groth16.NewVerifyingKey(h.Curve), nil
+		case Proof:
+			return groth16.NewProof(h.Curve), nil
+		}
+	case backend.PLONK:
+		switch h.Kind {
+		case ConstraintSystem:
+			return plonk.NewCS(h.Curve), nil
+		case ProvingKey:
+			return plonk.NewProvingKey(h.Curve), nil
+		case VerifyingKey:
+			return plonk.NewVerifyingKey(h.Curve), nil
+		case Proof:
+			return plonk.NewProof(h.Curve), nil
+		}
+	}
+	return nil, fmt.Errorf("unsupported artifact %s", h)
+}
+
+func isSupported(curve ecc.ID) bool {
+	for _, c := range gnark.Curves() {
+		if c == curve {
+			return true
+		}
+	}
+	return false
+}
+
+// CircuitHash returns the SHA-256 hash of the binary encoding of a constraint system, which identifies
+// the circuit of an artifact
+func CircuitHash(ccs frontend.CompiledConstraintSystem) ([32]byte, error) {
+	h := sha256.New()
+	if _, err := ccs.WriteTo(h); err != nil {
+		return [32]byte{}, err
+	}
+	var r [32]byte
+	copy(r[:], h.Sum(nil))
+	return r, nil
+}
+
+// Write writes the header of o followed by its binary encoding to w.
+//
+// o is a constraint system, a groth16 or plonk key or proof, a *witness.Witness or a kzg.SRS.
+// circuitHash is recorded in the header; it is computed for constraint systems, and may be nil for the
+// other artifacts when unknown.
+func Write(w io.Writer, o interface{}, circuitHash []byte) (int64, error) {
+	h, err := describe(o)
+	if err != nil {
+		return 0, err
+	}
+
+	var body bytes.Buffer
+	switch t := o.(type) {
+	case *witness.Witness:
+		if t.Vector == nil {
+			return 0, fmt.Errorf("%w: empty witness", witness.ErrInvalidWitness)
+		}
+		_, err = t.Vector.WriteTo(&body)
+	case io.WriterTo:
+		_, err = t.WriteTo(&body)
+	default:
+		err = fmt.Errorf("unsupported artifact type %T", o)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if h.Kind == ConstraintSystem {
+		h.CircuitHash = sha256.Sum256(body.Bytes())
+	} else if circuitHash != nil {
+		if len(circuitHash) != len(h.CircuitHash) {
+			return 0, fmt.Errorf("circuit hash must be %d bytes", len(h.CircuitHash))
+		}
+		copy(h.CircuitHash[:], circuitHash)
+	}
+
+	n, err := h.WriteTo(w)
+	if err != nil {
+		return n, err
+	}
+	m, err := w.Write(body.Bytes())
+	return n + int64(m), err
+}
+
+// ReadHeader reads the header of an artifact
+func ReadHeader(r io.Reader) (Header, error) {
+	var h Header
+	_, err := h.ReadFrom(r)
+	return h, err
+}
+
+// Read reads an artifact written by Write, and returns its header and the decoded object.
+//
+// The plonk keys are decoded without their KZG SRS, which must be set with InitKZG.
+func Read(r io.Reader) (interface{}, Header, error) {
+	h, err := ReadHeader(r)
+	if err != nil {
+		return nil, h, err
+	}
+	o, err := New(h)
+	if err != nil {
+		return nil, h, err
+	}
+	if err := decode(r, o); err != nil {
+		return nil, h, err
+	}
+	return o, h, nil
+}
+
+// ReadInto reads an artifact written by Write into o, and returns its header. It returns ErrMismatch
+// if the artifact is not of the kind, curve and backend of o.
+func ReadInto(r io.Reader, o interface{}) (Header, error) {
+	expected, err := describe(o)
+	if err != nil {
+		return Header{}, err
+	}
+	h, err := ReadHeader(r)
+	if err != nil {
+		return h, err
+	}
+	if h.Kind != expected.Kind || h.Curve != expected.Curve || h.Backend != expected.Backend {
+		return h, fmt.Errorf("%w: got %s, expected %s", ErrMismatch, h, expected)
+	}
+	return h, decode(r, o)
+}
+
+func decode(r io.Reader, o interface{}) error {
+	switch t := o.(type) {
+	case *witness.Witness:
+		if t.Vector == nil {
+			w, err := witness.New(t.CurveID, nil)
+			if err != nil {
+				return err
+			}
+			t.Vector = w.Vector
+		}
+		_, err := t.Vector.ReadFrom(r)
+		return err
+	case io.ReaderFrom:
+		_, err := t.ReadFrom(r)
+		return err
+	default:
+		return fmt.Errorf("unsupported artifact type %T", o)
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright 2022 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package artifact
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/consensys/gnark"
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/kzg"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/test"
+	"github.com/stretchr/testify/require"
+)
+
+type cubicCircuit struct {
+	X frontend.Variable `gnark:"x"`
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (circuit *cubicCircuit) Define(api frontend.API) error {
+	x3 := api.Mul(circuit.X, circuit.X, circuit.X)
+	api.AssertIsEqual(circuit.Y, api.Add(x3, circuit.X, 5))
+	return nil
+}
+
+// roundTrip writes o and reads it back with Read, checking the header
+func roundTrip(assert *require.Assertions, o interface{}, expected Header) interface{} {
+	var buf bytes.Buffer
+	_, err := Write(&buf, o, expected.CircuitHash[:])
+	assert.NoError(err)
+
+	r, h, err := Read(bytes.NewReader(buf.Bytes()))
+	assert.NoError(err)
+	assert.Equal(expected, h)
+
+	// ReadInto a fresh object of the same type
+	fresh, err := New(h)
+	assert.NoError(err)
+	_, err = ReadInto(bytes.NewReader(buf.Bytes()), fresh)
+	assert.NoError(err)
+	return r
+}
+
+func TestGroth16(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254, r1cs.NewBuilder, &cubicCircuit{})
+	assert.NoError(err)
+	pk, vk, err := groth16.Setup(ccs)
+	assert.NoError(err)
+	full, err := frontend.NewWitness(&cubicCircuit{X: 3, Y: 35}, ecc.BN254)
+	assert.NoError(err)
+	public, err := full.Public()
+	assert.NoError(err)
+	proof, err := groth16.Prove(ccs, pk, full)
+	assert.NoError(err)
+
+	hash, err := CircuitHash(ccs)
+	assert.NoError(err)
+	header := func(kind Kind, b backend.ID) Header {
+		return Header{Format: FormatVersion, Kind: kind, Curve: ecc.BN254, Backend: b, CircuitHash: hash, Version: gnark.Version}
+	}
+
+	rccs := roundTrip(assert, ccs, header(ConstraintSystem, backend.GROTH16)).(frontend.CompiledConstraintSystem)
+	assert.Equal(ccs.GetNbConstraints(), rccs.GetNbConstraints())
+	rpk := roundTrip(assert, pk, header(ProvingKey, backend.GROTH16)).(groth16.ProvingKey)
+	assert.False(pk.IsDifferent(rpk))
+	rvk := roundTrip(assert, vk, header(VerifyingKey, backend.GROTH16)).(groth16.VerifyingKey)
+	rproof := roundTrip(assert, proof, header(Proof, backend.GROTH16)).(groth16.Proof)
+	rpublic := roundTrip(assert, public, header(Witness, backend.UNKNOWN)).(*witness.Witness)
+	assert.NoError(groth16.Verify(rproof, rvk, rpublic))
+}
+
+func TestPlonk(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BLS12_381, scs.NewBuilder, &cubicCircuit{})
+	assert.NoError(err)
+	srs, err := test.NewKZGSRS(ccs)
+	assert.NoError(err)
+	pk, vk, err := plonk.Setup(ccs, srs)
+	assert.NoError(err)
+	full, err := frontend.NewWitness(&cubicCircuit{X: 3, Y: 35}, ecc.BLS12_381)
+	assert.NoError(err)
+	public, err := full.Public()
+	assert.NoError(err)
+
+	header := func(kind Kind, b backend.ID) Header {
+		return Header{Format: FormatVersion, Kind: kind, Curve: ecc.BLS12_381, Backend: b, Version: gnark.Version}
+	}
+
+	rsrs := roundTrip(assert, srs, header(SRS, backend.UNKNOWN)).(kzg.SRS)
+	rpk := roundTrip(assert, pk, header(ProvingKey, backend.PLONK)).(plonk.ProvingKey)
+	assert.NoError(rpk.InitKZG(rsrs))
+	rvk := roundTrip(assert, vk, header(VerifyingKey, backend.PLONK)).(plonk.VerifyingKey)
+	assert.NoError(rvk.InitKZG(rsrs))
+
+	proof, err := plonk.Prove(ccs, rpk, full)
+	assert.NoError(err)
+	rproof := roundTrip(assert, proof, header(Proof, backend.PLONK)).(plonk.Proof)
+	assert.NoError(plonk.Verify(rproof, rvk, public))
+}
+
+func TestErrors(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BLS12_381, r1cs.NewBuilder, &cubicCircuit{})
+	assert.NoError(err)
+	pk, err := groth16.DummySetup(ccs)
+	assert.NoError(err)
+
+	var buf bytes.Buffer
+	_, err = Write(&buf, pk, nil)
+	assert.NoError(err)
+
+	// reading a BLS12-381 key into a BN254 key, or into a verifying key, is rejected
+	_, err = ReadInto(bytes.NewReader(buf.Bytes()), groth16.NewProvingKey(ecc.BN254))
+	assert.True(errors.Is(err, ErrMismatch), err)
+	assert.Contains(err.Error(), "got BLS12_381 groth16 proving key, expected BN254 groth16 proving key")
+	_, err = ReadInto(bytes.NewReader(buf.Bytes()), groth16.NewVerifyingKey(ecc.BLS12_381))
+	assert.True(errors.Is(err, ErrMismatch), err)
+	_, err = ReadInto(bytes.NewReader(buf.Bytes()), plonk.NewProvingKey(ecc.BLS12_381))
+	assert.True(errors.Is(err, ErrMismatch), err)
+
+	// raw encodings have no header
+	var raw bytes.Buffer
+	_, err = pk.WriteTo(&raw)
+	assert.NoError(err)
+	_, _, err = Read(&raw)
+	assert.True(errors.Is(err, ErrNotAnArtifact), err)
+	_, _, err = Read(bytes.NewReader(nil))
+	assert.True(errors.Is(err, ErrNotAnArtifact), err)
+
+	// more recent format
+	data := buf.Bytes()
+	data[5] = byte(FormatVersion + 1)
+	_, _, err = Read(bytes.NewReader(data))
+	assert.True(errors.Is(err, ErrUnsupportedFormat), err)
+
+	_, err = Write(&buf, &cubicCircuit{}, nil)
+	assert.Error(err)
+	_, err = Write(&buf, pk, []byte{1, 2, 3})
+	assert.Error(err)
+}
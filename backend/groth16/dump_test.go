@@ -0,0 +1,58 @@
+package groth16
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/stretchr/testify/require"
+)
+
+type cubicCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (circuit *cubicCircuit) Define(api frontend.API) error {
+	x3 := api.Mul(circuit.X, circuit.X, circuit.X)
+	api.AssertIsEqual(circuit.Y, api.Add(x3, circuit.X, 5))
+	return nil
+}
+
+func TestReadMappedProvingKey(t *testing.T) {
+	assert := require.New(t)
+
+	for _, curve := range []ecc.ID{ecc.BN254, ecc.BW6_761} {
+		ccs, err := frontend.Compile(curve, r1cs.NewBuilder, &cubicCircuit{})
+		assert.NoError(err)
+		pk, vk, err := Setup(ccs)
+		assert.NoError(err)
+
+		path := filepath.Join(t.TempDir(), "pk.dump")
+		f, err := os.Create(path)
+		assert.NoError(err)
+		_, err = pk.WriteDump(f)
+		assert.NoError(err)
+		assert.NoError(f.Close())
+
+		mapped, closer, err := ReadMappedProvingKey(curve, path)
+		assert.NoError(err)
+		assert.False(pk.IsDifferent(mapped))
+
+		witness, err := frontend.NewWitness(&cubicCircuit{X: 3, Y: 35}, curve)
+		assert.NoError(err)
+		publicWitness, err := witness.Public()
+		assert.NoError(err)
+		proof, err := Prove(ccs, mapped, witness)
+		assert.NoError(err)
+		assert.NoError(Verify(proof, vk, publicWitness))
+		assert.NoError(closer.Close())
+
+		// the dump of a proving key on another curve is rejected
+		_, _, err = ReadMappedProvingKey(ecc.BLS12_381, path)
+		assert.Error(err)
+	}
+}
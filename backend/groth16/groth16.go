@@ -20,6 +20,7 @@
 package groth16
 
 import (
+	"encoding/json"
 	"io"
 
 	"github.com/consensys/gnark-crypto/ecc"
@@ -40,6 +41,7 @@ import (
 	witness_bw6633 "github.com/consensys/gnark/internal/backend/bw6-633/witness"
 	witness_bw6761 "github.com/consensys/gnark/internal/backend/bw6-761/witness"
 
+	"github.com/consensys/gnark/internal/backend/ioutils"
 	gnarkio "github.com/consensys/gnark/io"
 
 	groth16_bls12377 "github.com/consensys/gnark/internal/backend/bls12-377/groth16"
@@ -62,6 +64,10 @@ type groth16Object interface {
 // it's underlying implementation is curve specific (see gnark/internal/backend)
 type Proof interface {
 	groth16Object
+
+	// MarshalJSON and UnmarshalJSON encode the Proof in JSON, with hex-encoded points
+	json.Marshaler
+	json.Unmarshaler
 }
 
 // ProvingKey represents a Groth16 ProvingKey
@@ -77,6 +83,13 @@ type ProvingKey interface {
 	// NbG2 returns the number of G2 elements in the ProvingKey
 	NbG2() int
 
+	// WriteDump writes the ProvingKey in a raw layout, the memory representation of its points,
+	// which ReadDump maps back without copying or decoding (see ReadMappedProvingKey)
+	WriteDump(w io.Writer) (int64, error)
+
+	// ReadDump reads a ProvingKey written by WriteDump; its point slices are backed by data
+	ReadDump(data []byte) error
+
 	IsDifferent(interface{}) bool
 }
 
@@ -89,6 +102,10 @@ type VerifyingKey interface {
 	groth16Object
 	gnarkio.UnsafeReaderFrom
 
+	// MarshalJSON and UnmarshalJSON encode the VerifyingKey in JSON, with hex-encoded points
+	json.Marshaler
+	json.Unmarshaler
+
 	// NbPublicWitness returns number of elements expected in the public witness
 	NbPublicWitness() int
 
@@ -309,6 +326,24 @@ func DummySetup(r1cs frontend.CompiledConstraintSystem) (ProvingKey, error) {
 	}
 }
 
+// ReadMappedProvingKey maps the file at path, written by ProvingKey.WriteDump, in memory and returns
+// the ProvingKey it holds. The large slices of the ProvingKey are backed by the mapped file, so that
+// loading is fast and the processes mapping the same file share its page cache.
+//
+// The returned io.Closer unmaps the file; the ProvingKey must not be used after it is closed.
+func ReadMappedProvingKey(curveID ecc.ID, path string) (ProvingKey, io.Closer, error) {
+	m, err := ioutils.Mmap(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	pk := NewProvingKey(curveID)
+	if err := pk.ReadDump(m.Data); err != nil {
+		m.Close()
+		return nil, nil, err
+	}
+	return pk, m, nil
+}
+
 // NewProvingKey instantiates a curve-typed ProvingKey and returns an interface object
 // This function exists for serialization purposes
 func NewProvingKey(curveID ecc.ID) ProvingKey {
@@ -0,0 +1,192 @@
+// Copyright 2020 ConsenSys AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package groth16
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/consensys/gnark-crypto/ecc"
+
+	groth16_bls12381 "github.com/consensys/gnark/internal/backend/bls12-381/groth16"
+	groth16_bn254 "github.com/consensys/gnark/internal/backend/bn254/groth16"
+)
+
+// Format is the serialization format of a Rust implementation of Groth16,
+// see Export and Import functions
+type Format uint8
+
+const (
+	// Bellman is the format of zkcrypto/bellman, on BLS12-381 only: proofs are compressed and keys
+	// uncompressed, with the zcash encoding of points
+	Bellman Format = iota
+
+	// Arkworks is the CanonicalSerialize format of arkworks-rs/groth16, with compressed points,
+	// on BN254 and BLS12-381
+	Arkworks
+
+	// ArkworksUncompressed is the CanonicalSerialize format of arkworks-rs/groth16, with
+	// uncompressed points, on BN254 and BLS12-381
+	ArkworksUncompressed
+)
+
+func (f Format) String() string {
+	switch f {
+	case Bellman:
+		return "bellman"
+	case Arkworks:
+		return "arkworks"
+	case ArkworksUncompressed:
+		return "arkworks (uncompressed)"
+	default:
+		return fmt.Sprintf("Format(%d)", uint8(f))
+	}
+}
+
+// ErrUnsupportedFormat is returned when a format is not supported on a curve or for an object
+var ErrUnsupportedFormat = errors.New("unsupported serialization format")
+
+// arkworksObject is implemented by the proofs and verifying keys of the curves supported by arkworks
+type arkworksObject interface {
+	WriteArkworksTo(w io.Writer, compressed bool) (int64, error)
+	ReadArkworksFrom(r io.Reader, compressed bool) (int64, error)
+}
+
+// ExportProof writes proof in the format f, to be read by a Rust verifier
+func ExportProof(w io.Writer, proof Proof, f Format) (int64, error) {
+	return export(w, proof, f)
+}
+
+// ImportProof reads a proof on curveID written by a Rust prover in the format f
+func ImportProof(r io.Reader, curveID ecc.ID, f Format) (Proof, error) {
+	proof := NewProof(curveID)
+	if err := _import(r, proof, f); err != nil {
+		return nil, err
+	}
+	return proof, nil
+}
+
+// ExportVerifyingKey writes vk in the format f, to be read by a Rust verifier
+//
+// The public inputs are in the same order in gnark, bellman and arkworks; the constant wire ONE
+// comes first.
+func ExportVerifyingKey(w io.Writer, vk VerifyingKey, f Format) (int64, error) {
+	return export(w, vk, f)
+}
+
+// ImportVerifyingKey reads a verifying key on curveID written by a Rust implementation in the format f
+func ImportVerifyingKey(r io.Reader, curveID ecc.ID, f Format) (VerifyingKey, error) {
+	vk := NewVerifyingKey(curveID)
+	if err := _import(r, vk, f); err != nil {
+		return nil, err
+	}
+	return vk, nil
+}
+
+// ExportProvingKey writes pk and its verifying key vk in the format f. Rust proving keys embed
+// their verifying key.
+//
+// bellman and arkworks reduce the R1CS to a QAP with additional constraints for the public inputs:
+// the keys have the same layout but are not interchangeable, a Rust prover can't use a key
+// generated by gnark to prove the statements of a gnark circuit, and vice versa.
+func ExportProvingKey(w io.Writer, pk ProvingKey, vk VerifyingKey, f Format) (int64, error) {
+	if pk.CurveID() != vk.CurveID() {
+		return 0, fmt.Errorf("proving key on %s and verifying key on %s", pk.CurveID(), vk.CurveID())
+	}
+	compressed := f == Arkworks
+	switch _pk := pk.(type) {
+	case *groth16_bn254.ProvingKey:
+		if f == Arkworks || f == ArkworksUncompressed {
+			return _pk.WriteArkworksTo(w, vk.(*groth16_bn254.VerifyingKey), compressed)
+		}
+	case *groth16_bls12381.ProvingKey:
+		switch f {
+		case Bellman:
+			return _pk.WriteBellmanTo(w, vk.(*groth16_bls12381.VerifyingKey))
+		case Arkworks, ArkworksUncompressed:
+			return _pk.WriteArkworksTo(w, vk.(*groth16_bls12381.VerifyingKey), compressed)
+		}
+	}
+	return 0, unsupportedFormat(f, pk.CurveID())
+}
+
+// ImportProvingKey reads a proving key on curveID and its verifying key written by a Rust
+// implementation in the format f. See ExportProvingKey for the limits of the interoperability.
+//
+// bellman parameters are not supported: they don't record the indexes of the points at infinity
+// that bellman removes from the A and B queries.
+func ImportProvingKey(r io.Reader, curveID ecc.ID, f Format) (ProvingKey, VerifyingKey, error) {
+	pk, vk := NewProvingKey(curveID), NewVerifyingKey(curveID)
+	compressed := f == Arkworks
+	if f != Arkworks && f != ArkworksUncompressed {
+		return nil, nil, unsupportedFormat(f, curveID)
+	}
+	var err error
+	switch _pk := pk.(type) {
+	case *groth16_bn254.ProvingKey:
+		_, err = _pk.ReadArkworksFrom(r, vk.(*groth16_bn254.VerifyingKey), compressed)
+	case *groth16_bls12381.ProvingKey:
+		_, err = _pk.ReadArkworksFrom(r, vk.(*groth16_bls12381.VerifyingKey), compressed)
+	default:
+		return nil, nil, unsupportedFormat(f, curveID)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return pk, vk, nil
+}
+
+func export(w io.Writer, v groth16Object, f Format) (int64, error) {
+	switch f {
+	case Bellman:
+		if v.CurveID() != ecc.BLS12_381 {
+			break
+		}
+		if _, ok := v.(*groth16_bls12381.Proof); ok {
+			return v.WriteTo(w)
+		}
+		return v.WriteRawTo(w)
+	case Arkworks, ArkworksUncompressed:
+		if o, ok := v.(arkworksObject); ok {
+			return o.WriteArkworksTo(w, f == Arkworks)
+		}
+	}
+	return 0, unsupportedFormat(f, v.CurveID())
+}
+
+func _import(r io.Reader, v groth16Object, f Format) error {
+	var err error
+	switch f {
+	case Bellman:
+		if v.CurveID() != ecc.BLS12_381 {
+			return unsupportedFormat(f, v.CurveID())
+		}
+		_, err = v.ReadFrom(r)
+	case Arkworks, ArkworksUncompressed:
+		o, ok := v.(arkworksObject)
+		if !ok {
+			return unsupportedFormat(f, v.CurveID())
+		}
+		_, err = o.ReadArkworksFrom(r, f == Arkworks)
+	default:
+		return unsupportedFormat(f, v.CurveID())
+	}
+	return err
+}
+
+func unsupportedFormat(f Format, curveID ecc.ID) error {
+	return fmt.Errorf("%w: %s on %s", ErrUnsupportedFormat, f, curveID)
+}
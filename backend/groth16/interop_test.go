@@ -0,0 +1,228 @@
+package groth16
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	curve "github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	groth16_bn254 "github.com/consensys/gnark/internal/backend/bn254/groth16"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArkworksRoundTrip(t *testing.T) {
+	assert := require.New(t)
+
+	for _, curveID := range []ecc.ID{ecc.BN254, ecc.BLS12_381} {
+		ccs, err := frontend.Compile(curveID, r1cs.NewBuilder, &cubicCircuit{})
+		assert.NoError(err)
+		pk, vk, err := Setup(ccs)
+		assert.NoError(err)
+
+		witness, err := frontend.NewWitness(&cubicCircuit{X: 3, Y: 35}, curveID)
+		assert.NoError(err)
+		publicWitness, err := witness.Public()
+		assert.NoError(err)
+		proof, err := Prove(ccs, pk, witness)
+		assert.NoError(err)
+
+		for _, f := range []Format{Arkworks, ArkworksUncompressed} {
+			var buf bytes.Buffer
+			_, err = ExportProof(&buf, proof, f)
+			assert.NoError(err)
+			_proof, err := ImportProof(&buf, curveID, f)
+			assert.NoError(err)
+			assert.Equal(proof, _proof, "%s %s", curveID, f)
+
+			_, err = ExportVerifyingKey(&buf, vk, f)
+			assert.NoError(err)
+			_vk, err := ImportVerifyingKey(&buf, curveID, f)
+			assert.NoError(err)
+			assert.NoError(Verify(_proof, _vk, publicWitness))
+
+			// the imported proving key drops the last point of [Z(t)]1, which multiplies a zero
+			// coefficient of the quotient
+			_, err = ExportProvingKey(&buf, pk, vk, f)
+			assert.NoError(err)
+			_pk, _vk, err := ImportProvingKey(&buf, curveID, f)
+			assert.NoError(err)
+			assert.Equal(0, buf.Len())
+			assert.Equal(pk.NbG2(), _pk.NbG2())
+			proof, err := Prove(ccs, _pk, witness)
+			assert.NoError(err)
+			assert.NoError(Verify(proof, _vk, publicWitness))
+			assert.NoError(Verify(proof, vk, publicWitness))
+		}
+	}
+}
+
+func TestArkworksEncoding(t *testing.T) {
+	assert := require.New(t)
+
+	// [1]1 = (1, 2), with 2 < -2; -[1]1 = (1, p-2) and the point at infinity in [Bs]2
+	_, _, g1, _ := curve.Generators()
+	proof := &groth16_bn254.Proof{Ar: g1}
+	proof.Krs.Neg(&g1)
+
+	one := "01" + strings.Repeat("00", 31)
+	two := "02" + strings.Repeat("00", 31)
+	minusTwo := "45fd7cd8168c203c8dca7168916a81975d588181b64550b829a031e1724e6430" // p-2 in little-endian
+	infinityG2 := func(size int) string {
+		return strings.Repeat("00", size-1) + "40"
+	}
+	withNegativeY := func(s string) string {
+		b, _ := hex.DecodeString(s)
+		b[len(b)-1] |= 0x80
+		return hex.EncodeToString(b)
+	}
+
+	for _, test := range []struct {
+		f        Format
+		expected string
+	}{
+		{Arkworks, one + infinityG2(64) + withNegativeY(one)},
+		{ArkworksUncompressed, one + two + infinityG2(128) + one + minusTwo},
+	} {
+		var buf bytes.Buffer
+		_, err := ExportProof(&buf, proof, test.f)
+		assert.NoError(err)
+		assert.Equal(test.expected, hex.EncodeToString(buf.Bytes()), test.f)
+
+		_proof, err := ImportProof(&buf, ecc.BN254, test.f)
+		assert.NoError(err)
+		assert.Equal(proof, _proof)
+	}
+
+	// a point which is not on the curve is rejected
+	b, _ := hex.DecodeString(one + one + infinityG2(128) + one + two)
+	_, err := ImportProof(bytes.NewReader(b), ecc.BN254, ArkworksUncompressed)
+	assert.Error(err)
+
+	// x = p is not reduced
+	b, _ = hex.DecodeString("47" + minusTwo[2:] + infinityG2(64) + one)
+	_, err = ImportProof(bytes.NewReader(b), ecc.BN254, Arkworks)
+	assert.Error(err)
+}
+
+// TestArkworksVerifyingKey checks a verifying key encoded as arkworks-rs/groth16's CanonicalSerialize
+// does, derived independently of the encoder from the coordinates of the BN254 generators (EIP-197):
+// α = [1]1, β = γ = [1]2, δ = -[1]2 and K = [[1]1, -[1]1]. The y coordinate of [1]2 is the smallest
+// of ±y, comparing c1 first then c0, so the negative flag is set on δ when compressed (uncompressed
+// points only carry the infinity flag).
+func TestArkworksVerifyingKey(t *testing.T) {
+	assert := require.New(t)
+
+	const (
+		compressed   = "0100000000000000000000000000000000000000000000000000000000000000edf692d95cbdde46ddda5ef7d422436779445c5e66006a42761e1f12efde0018c212f3aeb785e49712e7a9353349aaf1255dfb31b7bf60723a480d9293938e19edf692d95cbdde46ddda5ef7d422436779445c5e66006a42761e1f12efde0018c212f3aeb785e49712e7a9353349aaf1255dfb31b7bf60723a480d9293938e19edf692d95cbdde46ddda5ef7d422436779445c5e66006a42761e1f12efde0018c212f3aeb785e49712e7a9353349aaf1255dfb31b7bf60723a480d9293938e99020000000000000001000000000000000000000000000000000000000000000000000000000000000100000000000000000000000000000000000000000000000000000000000080"
+		uncompressed = "01000000000000000000000000000000000000000000000000000000000000000200000000000000000000000000000000000000000000000000000000000000edf692d95cbdde46ddda5ef7d422436779445c5e66006a42761e1f12efde0018c212f3aeb785e49712e7a9353349aaf1255dfb31b7bf60723a480d9293938e19aa7dfa6601cce64c7bd3430c69e7d1e38f40cb8d8071ab4aeb6d8cdba55ec8125b9722d1dcdaac55f38eb37033314bbc95330c69ad999eec75f05f58d0890609edf692d95cbdde46ddda5ef7d422436779445c5e66006a42761e1f12efde0018c212f3aeb785e49712e7a9353349aaf1255dfb31b7bf60723a480d9293938e19aa7dfa6601cce64c7bd3430c69e7d1e38f40cb8d8071ab4aeb6d8cdba55ec8125b9722d1dcdaac55f38eb37033314bbc95330c69ad999eec75f05f58d0890609edf692d95cbdde46ddda5ef7d422436779445c5e66006a42761e1f12efde0018c212f3aeb785e49712e7a9353349aaf1255dfb31b7bf60723a480d9293938e199d7f827115c039ef11f72d5c2883afb3cd17b6f335d4a46d3e32a505cdef9b1dec655a073ab173e6993bbef75d3936dbc724751809acb1cbb3afd188a2c45d27020000000000000001000000000000000000000000000000000000000000000000000000000000000200000000000000000000000000000000000000000000000000000000000000010000000000000000000000000000000000000000000000000000000000000045fd7cd8168c203c8dca7168916a81975d588181b64550b829a031e1724e6430"
+	)
+
+	_, _, g1, g2 := curve.Generators()
+	var g1Neg curve.G1Affine
+	var g2Neg curve.G2Affine
+	g1Neg.Neg(&g1)
+	g2Neg.Neg(&g2)
+
+	for _, test := range []struct {
+		f       Format
+		fixture string
+	}{
+		{Arkworks, compressed},
+		{ArkworksUncompressed, uncompressed},
+	} {
+		b, err := hex.DecodeString(test.fixture)
+		assert.NoError(err)
+		_vk, err := ImportVerifyingKey(bytes.NewReader(b), ecc.BN254, test.f)
+		assert.NoError(err, test.f)
+
+		vk := _vk.(*groth16_bn254.VerifyingKey)
+		assert.True(vk.G1.Alpha.Equal(&g1), test.f)
+		assert.True(vk.G2.Beta.Equal(&g2), test.f)
+		assert.True(vk.G2.Gamma.Equal(&g2), test.f)
+		assert.True(vk.G2.Delta.Equal(&g2Neg), test.f)
+		assert.Equal(2, len(vk.G1.K), test.f)
+		assert.True(vk.G1.K[0].Equal(&g1), test.f)
+		assert.True(vk.G1.K[1].Equal(&g1Neg), test.f)
+
+		var buf bytes.Buffer
+		_, err = ExportVerifyingKey(&buf, vk, test.f)
+		assert.NoError(err)
+		assert.Equal(test.fixture, hex.EncodeToString(buf.Bytes()), test.f)
+	}
+}
+
+func TestBellmanRoundTrip(t *testing.T) {
+	assert := require.New(t)
+
+	// first test vector of TestVerifyBellmanProof, with a compressed verifying key
+	vkBytes, err := base64.StdEncoding.DecodeString("hwk883gUlTKCyXYA6XWZa8H9/xKIYZaJ0xEs0M5hQOMxiGpxocuX/8maSDmeCk3bhwk883gUlTKCyXYA6XWZa8H9/xKIYZaJ0xEs0M5hQOMxiGpxocuX/8maSDmeCk3bo5ViaDBdO7ZBxAhLSe5k/5TFQyF5Lv7KN2tLKnwgoWMqB16OL8WdbePIwTCuPtJNAFKoTZylLDbSf02kckMcZQDPF9iGh+JC99Pio74vDpwTEjUx5tQ99gNQwxULtztsqDRsPnEvKvLmsxHt8LQVBkEBm2PBJFY+OXf1MNW021viDBpR10mX4WQ6zrsGL5L0GY4cwf4tlbh+Obit+LnN/SQTnREf8fPpdKZ1sa/ui3pGi8lMT6io4D7Ujlwx2RdChwk883gUlTKCyXYA6XWZa8H9/xKIYZaJ0xEs0M5hQOMxiGpxocuX/8maSDmeCk3bkBF+isfMf77HCEGsZANw0hSrO2FGg14Sl26xLAIohdaW8O7gEaag8JdVAZ3OVLd5Df1NkZBEr753Xb8WwaXsJjE7qxwINL1KdqA4+EiYW4edb7+a9bbBeOPtb67ZxmFqAAAAAoMkzUv+KG8WoXszZI5NNMrbMLBDYP/xHunVgSWcix/kBrGlNozv1uFr0cmYZiij3YqToYs+EZa3dl2ILHx7H1n+b+Bjky/td2QduHVtf5t/Z9sKCfr+vOn12zVvOVz/6w==")
+	assert.NoError(err)
+	proofBytes, err := base64.StdEncoding.DecodeString("lvQLU/KqgFhsLkt/5C/scqs7nWR+eYtyPdWiLVBux9GblT4AhHYMdCgwQfSJcudvsgV6fXoK+DUSRgJ++Nqt+Wvb7GlYlHpxCysQhz26TTu8Nyo7zpmVPH92+UYmbvbQCSvX2BhWtvkfHmqDVjmSIQ4RUMfeveA1KZbSf999NE4qKK8Do+8oXcmTM4LZVmh1rlyqznIdFXPN7x3pD4E0gb6/y69xtWMChv9654FMg05bAdueKt9uA4BEcAbpkdHF")
+	assert.NoError(err)
+
+	vk, err := ImportVerifyingKey(bytes.NewReader(vkBytes), ecc.BLS12_381, Bellman)
+	assert.NoError(err)
+	proof, err := ImportProof(bytes.NewReader(proofBytes), ecc.BLS12_381, Bellman)
+	assert.NoError(err)
+
+	var buf bytes.Buffer
+	_, err = ExportProof(&buf, proof, Bellman)
+	assert.NoError(err)
+	assert.Equal(proofBytes, buf.Bytes())
+
+	// bellman writes its verifying keys uncompressed
+	buf.Reset()
+	_, err = ExportVerifyingKey(&buf, vk, Bellman)
+	assert.NoError(err)
+	assert.Equal(2*(len(vkBytes)-4)+4, buf.Len())
+	_vk, err := ImportVerifyingKey(&buf, ecc.BLS12_381, Bellman)
+	assert.NoError(err)
+	assert.False(vk.IsDifferent(_vk))
+}
+
+func TestBellmanProvingKey(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BLS12_381, r1cs.NewBuilder, &cubicCircuit{})
+	assert.NoError(err)
+	pk, vk, err := Setup(ccs)
+	assert.NoError(err)
+
+	var buf bytes.Buffer
+	_, err = ExportProvingKey(&buf, pk, vk, Bellman)
+	assert.NoError(err)
+
+	// vk, then h, l, a, b_g1 and b_g2 with uncompressed points
+	var vkBuf bytes.Buffer
+	_, err = vk.WriteRawTo(&vkBuf)
+	assert.NoError(err)
+	assert.True(bytes.HasPrefix(buf.Bytes(), vkBuf.Bytes()))
+	nbPoints := 0
+	offset := vkBuf.Len()
+	for _, pointSize := range []int{96, 96, 96, 96, 192} {
+		n := int(buf.Bytes()[offset])<<24 | int(buf.Bytes()[offset+1])<<16 | int(buf.Bytes()[offset+2])<<8 | int(buf.Bytes()[offset+3])
+		offset += 4 + n*pointSize
+		nbPoints += n
+	}
+	assert.Equal(buf.Len(), offset)
+	assert.Equal(pk.NbG1()-3-1+pk.NbG2()-2, nbPoints, "[H(t)]1 has one point less than [Z(t)]1")
+
+	_, _, err = ImportProvingKey(&buf, ecc.BLS12_381, Bellman)
+	assert.True(errors.Is(err, ErrUnsupportedFormat))
+
+	// bellman is not supported on BN254
+	ccs, err = frontend.Compile(ecc.BN254, r1cs.NewBuilder, &cubicCircuit{})
+	assert.NoError(err)
+	pk, vk, err = Setup(ccs)
+	assert.NoError(err)
+	_, err = ExportVerifyingKey(&buf, vk, Bellman)
+	assert.True(errors.Is(err, ErrUnsupportedFormat))
+	_, err = ExportProvingKey(&buf, pk, vk, Bellman)
+	assert.True(errors.Is(err, ErrUnsupportedFormat))
+}
@@ -0,0 +1,56 @@
+package plonk_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/test"
+	"github.com/stretchr/testify/require"
+)
+
+type cubicCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (circuit *cubicCircuit) Define(api frontend.API) error {
+	x3 := api.Mul(circuit.X, circuit.X, circuit.X)
+	api.AssertIsEqual(circuit.Y, api.Add(x3, circuit.X, 5))
+	return nil
+}
+
+func TestReadMappedProvingKey(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BLS12_377, scs.NewBuilder, &cubicCircuit{})
+	assert.NoError(err)
+	srs, err := test.NewKZGSRS(ccs)
+	assert.NoError(err)
+	pk, vk, err := plonk.Setup(ccs, srs)
+	assert.NoError(err)
+
+	path := filepath.Join(t.TempDir(), "pk.dump")
+	f, err := os.Create(path)
+	assert.NoError(err)
+	_, err = pk.WriteDump(f)
+	assert.NoError(err)
+	assert.NoError(f.Close())
+
+	mapped, closer, err := plonk.ReadMappedProvingKey(ecc.BLS12_377, path)
+	assert.NoError(err)
+	defer closer.Close()
+	assert.NoError(mapped.InitKZG(srs))
+
+	witness, err := frontend.NewWitness(&cubicCircuit{X: 3, Y: 35}, ecc.BLS12_377)
+	assert.NoError(err)
+	publicWitness, err := witness.Public()
+	assert.NoError(err)
+	proof, err := plonk.Prove(ccs, mapped, witness)
+	assert.NoError(err)
+	assert.NoError(plonk.Verify(proof, vk, publicWitness))
+}
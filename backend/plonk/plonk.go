@@ -20,12 +20,14 @@
 package plonk
 
 import (
+	"encoding/json"
 	"io"
 
 	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark-crypto/kzg"
 	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/frontend"
+	gnarkio "github.com/consensys/gnark/io"
 
 	"github.com/consensys/gnark/backend/witness"
 	cs_bls12377 "github.com/consensys/gnark/internal/backend/bls12-377/cs"
@@ -34,6 +36,7 @@ import (
 	cs_bn254 "github.com/consensys/gnark/internal/backend/bn254/cs"
 	cs_bw6633 "github.com/consensys/gnark/internal/backend/bw6-633/cs"
 	cs_bw6761 "github.com/consensys/gnark/internal/backend/bw6-761/cs"
+	"github.com/consensys/gnark/internal/backend/ioutils"
 
 	plonk_bls12377 "github.com/consensys/gnark/internal/backend/bls12-377/plonk"
 	plonk_bls12381 "github.com/consensys/gnark/internal/backend/bls12-381/plonk"
@@ -63,6 +66,12 @@ import (
 type Proof interface {
 	io.WriterTo
 	io.ReaderFrom
+	gnarkio.WriterRawTo
+	gnarkio.UnsafeReaderFrom
+
+	// MarshalJSON and UnmarshalJSON encode the Proof in JSON, with hex-encoded points
+	json.Marshaler
+	json.Unmarshaler
 }
 
 // ProvingKey represents a plonk ProvingKey
@@ -71,8 +80,17 @@ type Proof interface {
 type ProvingKey interface {
 	io.WriterTo
 	io.ReaderFrom
+	gnarkio.WriterRawTo
+	gnarkio.UnsafeReaderFrom
 	InitKZG(srs kzg.SRS) error
 	VerifyingKey() interface{}
+
+	// WriteDump writes the ProvingKey in a raw layout, the memory representation of its polynomials,
+	// which ReadDump maps back without copying or decoding (see ReadMappedProvingKey)
+	WriteDump(w io.Writer) (int64, error)
+
+	// ReadDump reads a ProvingKey written by WriteDump; its polynomials are backed by data
+	ReadDump(data []byte) error
 }
 
 // VerifyingKey represents a plonk VerifyingKey
@@ -81,6 +99,13 @@ type ProvingKey interface {
 type VerifyingKey interface {
 	io.WriterTo
 	io.ReaderFrom
+	gnarkio.WriterRawTo
+	gnarkio.UnsafeReaderFrom
+
+	// MarshalJSON and UnmarshalJSON encode the VerifyingKey in JSON, with hex-encoded points
+	// (the KZG SRS is not encoded, see InitKZG)
+	json.Marshaler
+	json.Unmarshaler
 	InitKZG(srs kzg.SRS) error
 	NbPublicWitness() int // number of elements expected in the public witness
 }
@@ -243,6 +268,25 @@ func NewCS(curveID ecc.ID) frontend.CompiledConstraintSystem {
 	return r1cs
 }
 
+// ReadMappedProvingKey maps the file at path, written by ProvingKey.WriteDump, in memory and returns
+// the ProvingKey it holds. The large slices of the ProvingKey are backed by the mapped file, so that
+// loading is fast and the processes mapping the same file share its page cache.
+//
+// The returned io.Closer unmaps the file; the ProvingKey must not be used after it is closed.
+// As with ReadFrom, the KZG SRS must then be set with InitKZG.
+func ReadMappedProvingKey(curveID ecc.ID, path string) (ProvingKey, io.Closer, error) {
+	m, err := ioutils.Mmap(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	pk := NewProvingKey(curveID)
+	if err := pk.ReadDump(m.Data); err != nil {
+		m.Close()
+		return nil, nil, err
+	}
+	return pk, m, nil
+}
+
 // NewProvingKey instantiates a curve-typed ProvingKey and returns an interface
 // This function exists for serialization purposes
 func NewProvingKey(curveID ecc.ID) ProvingKey {
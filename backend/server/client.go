@@ -0,0 +1,130 @@
+// Copyright 2022 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/consensys/gnark/backend/witness"
+)
+
+// Client calls a Server over HTTP.
+//
+// The errors returned by the server are mapped back to ErrUnknownCircuit, ErrQueueFull and
+// witness.ErrInvalidWitness, so that they can be tested with errors.Is.
+type Client struct {
+	url        string
+	httpClient *http.Client
+}
+
+// NewClient returns a client of the server at url (for example, http://localhost:9000).
+// If httpClient is nil, http.DefaultClient is used.
+func NewClient(url string, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Client{url: strings.TrimSuffix(url, "/"), httpClient: httpClient}
+}
+
+// NewTestClient serves s on an in-process HTTP server listening on localhost, and returns a client
+// of it, and a function stopping the HTTP server. s itself is not closed.
+func NewTestClient(s *Server) (*Client, func()) {
+	ts := httptest.NewServer(s)
+	return NewClient(ts.URL, ts.Client()), ts.Close
+}
+
+// Circuits returns the circuits of the server
+func (c *Client) Circuits(ctx context.Context) ([]CircuitInfo, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url+"/circuits", nil)
+	if err != nil {
+		return nil, err
+	}
+	var r []CircuitInfo
+	if err := c.do(req, &r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Prove sends the full witness, with its binary encoding, to the server and returns the proof of the
+// named circuit
+func (c *Client) Prove(ctx context.Context, circuit string, fullWitness *witness.Witness) (*Result, error) {
+	data, err := fullWitness.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return c.prove(ctx, circuit, contentTypeBinary, data)
+}
+
+// ProveJSON sends the JSON encoding of a full witness (see witness.MarshalJSON) to the server and
+// returns the proof of the named circuit
+func (c *Client) ProveJSON(ctx context.Context, circuit string, fullWitness []byte) (*Result, error) {
+	return c.prove(ctx, circuit, contentTypeJSON, fullWitness)
+}
+
+func (c *Client) prove(ctx context.Context, circuit, contentType string, data []byte) (*Result, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url+"/prove/"+circuit, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	var r Result
+	if err := c.do(req, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// do sends the request and decodes the JSON response into v
+func (c *Client) do(req *http.Request, v interface{}) error {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		return json.NewDecoder(resp.Body).Decode(v)
+	}
+
+	var e errorResponse
+	body, _ := io.ReadAll(resp.Body)
+	if err := json.Unmarshal(body, &e); err != nil || e.Error == "" {
+		e.Error = resp.Status
+	}
+	switch resp.StatusCode {
+	case http.StatusNotFound:
+		if strings.HasPrefix(e.Error, ErrUnknownCircuit.Error()) {
+			return fmt.Errorf("%w%s", ErrUnknownCircuit, strings.TrimPrefix(e.Error, ErrUnknownCircuit.Error()))
+		}
+	case http.StatusServiceUnavailable:
+		if e.Error == ErrQueueFull.Error() {
+			return ErrQueueFull
+		}
+	case http.StatusBadRequest:
+		if strings.HasPrefix(e.Error, witness.ErrInvalidWitness.Error()) {
+			return fmt.Errorf("%w%s", witness.ErrInvalidWitness, strings.TrimPrefix(e.Error, witness.ErrInvalidWitness.Error()))
+		}
+	}
+	return errors.New(e.Error)
+}
@@ -0,0 +1,129 @@
+// Copyright 2022 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/consensys/gnark/backend/witness"
+)
+
+const (
+	contentTypeJSON   = "application/json"
+	contentTypeBinary = "application/octet-stream"
+
+	// maxRequestSize bounds the size of a witness sent over HTTP
+	maxRequestSize = 1 << 26
+)
+
+// errorResponse is the body of the HTTP responses with an error status
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// ServeHTTP implements http.Handler, with the routes
+//
+//	GET  /circuits        JSON list of CircuitInfo
+//	POST /prove/{circuit} full witness in the body, JSON (Content-Type: application/json)
+//	                      or binary (Content-Type: application/octet-stream, see witness.MarshalBinary);
+//	                      JSON Result in the response
+//
+// Errors are returned as a JSON object {"error": "..."}, with status 404 for ErrUnknownCircuit,
+// 400 for an invalid witness, 422 when the proof fails (for example, unsatisfied constraints), and
+// 503 for ErrQueueFull and ErrClosed.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.URL.Path == "/circuits":
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+			return
+		}
+		writeJSON(w, http.StatusOK, s.Circuits())
+	case strings.HasPrefix(r.URL.Path, "/prove/"):
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+			return
+		}
+		s.serveProve(w, r, strings.TrimPrefix(r.URL.Path, "/prove/"))
+	default:
+		writeError(w, http.StatusNotFound, fmt.Errorf("no route for %s", r.URL.Path))
+	}
+}
+
+func (s *Server) serveProve(w http.ResponseWriter, r *http.Request, name string) {
+	c, err := s.circuit(name)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestSize))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	fullWitness, err := witness.New(c.ccs.CurveID(), c.ccs.GetSchema())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch contentType {
+	case contentTypeJSON:
+		err = fullWitness.UnmarshalJSON(data)
+	case contentTypeBinary:
+		err = fullWitness.UnmarshalBinary(data)
+	default:
+		writeError(w, http.StatusUnsupportedMediaType, fmt.Errorf("unsupported witness content type %q", contentType))
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("%w: %v", witness.ErrInvalidWitness, err))
+		return
+	}
+
+	result, err := s.Prove(r.Context(), name, fullWitness)
+	switch {
+	case err == nil:
+		writeJSON(w, http.StatusOK, result)
+	case errors.Is(err, witness.ErrInvalidWitness):
+		writeError(w, http.StatusBadRequest, err)
+	case errors.Is(err, ErrQueueFull), errors.Is(err, ErrClosed):
+		w.Header().Set("Retry-After", "1")
+		writeError(w, http.StatusServiceUnavailable, err)
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		// the client is gone
+		writeError(w, http.StatusServiceUnavailable, err)
+	default:
+		writeError(w, http.StatusUnprocessableEntity, err)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", contentTypeJSON)
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
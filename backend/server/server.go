@@ -0,0 +1,391 @@
+// Copyright 2022 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package server implements a local proving service.
+//
+// A Server holds compiled constraint systems and their proving keys in memory, so that they are read
+// once, and runs the proving jobs on a bounded pool of workers. When the job queue is full, new jobs
+// are rejected with ErrQueueFull instead of piling up, and the caller is expected to retry later.
+//
+//	s := server.New(server.Config{Workers: 2, QueueSize: 16})
+//	defer s.Close()
+//	if err := s.AddGroth16("cubic", ccs, pk); err != nil {
+//		return err
+//	}
+//	log.Fatal(http.ListenAndServe("localhost:9000", s))
+//
+// Server implements http.Handler (see ServeHTTP for the routes), and Client calls it. The service has
+// no authentication: it is meant to listen on localhost, next to the application requesting the proofs.
+// NewTestClient runs a Server in-process, for tests.
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/kzg"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/artifact"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/logger"
+	"github.com/consensys/gnark/std"
+)
+
+var (
+	// ErrUnknownCircuit is returned when a job refers to a circuit which was not added to the server
+	ErrUnknownCircuit = errors.New("unknown circuit")
+	// ErrQueueFull is returned when the job queue is full; the job may be submitted again later
+	ErrQueueFull = errors.New("job queue is full")
+	// ErrClosed is returned for jobs submitted after Close
+	ErrClosed = errors.New("server is closed")
+)
+
+// Config of a Server
+type Config struct {
+	// Workers is the number of proofs computed concurrently, runtime.NumCPU() if 0.
+	// Each proof is already parallelized, so that a few workers are enough to keep the CPUs busy.
+	Workers int
+
+	// QueueSize is the number of jobs waiting for a worker, beyond which jobs are rejected with ErrQueueFull
+	QueueSize int
+
+	// ProverOptions are passed to groth16.Prove and plonk.Prove
+	ProverOptions []backend.ProverOption
+}
+
+// Result of a proving job
+type Result struct {
+	// Proof is the binary encoding of the proof (see groth16.Proof and plonk.Proof WriteTo)
+	Proof []byte `json:"proof"`
+
+	// Wait is the time spent by the job in the queue
+	Wait time.Duration `json:"wait"`
+
+	// Prove is the time spent solving the constraint system and computing the proof
+	Prove time.Duration `json:"prove"`
+}
+
+// CircuitInfo describes a circuit added to a Server
+type CircuitInfo struct {
+	Name          string `json:"name"`
+	Curve         string `json:"curve"`
+	Backend       string `json:"backend"`
+	NbConstraints int    `json:"nbConstraints"`
+	NbPublic      int    `json:"nbPublic"` // number of public inputs of the witness
+	NbSecret      int    `json:"nbSecret"` // number of secret inputs of the witness
+}
+
+// circuit is a constraint system and its proving key
+type circuit struct {
+	ccs     frontend.CompiledConstraintSystem
+	backend backend.ID
+	prove   func(fullWitness *witness.Witness, opts ...backend.ProverOption) (io.WriterTo, error)
+}
+
+type job struct {
+	ctx       context.Context
+	circuit   *circuit
+	witness   *witness.Witness
+	submitted time.Time
+	done      chan jobResult // buffered, so that a worker never waits for a caller which gave up
+}
+
+type jobResult struct {
+	result *Result
+	err    error
+}
+
+// Server is a local proving service; see package documentation
+type Server struct {
+	opts []backend.ProverOption
+
+	circuitsLock sync.RWMutex
+	circuits     map[string]*circuit
+
+	// jobsLock protects jobs from being written to after it is closed
+	jobsLock sync.RWMutex
+	jobs     chan *job
+	closed   bool
+	wg       sync.WaitGroup
+}
+
+// New starts the workers of a Server, which must be stopped with Close.
+//
+// The hints of the standard library are registered, so that the circuits may use its gadgets.
+func New(config Config) *Server {
+	if config.Workers <= 0 {
+		config.Workers = runtime.NumCPU()
+	}
+	if config.QueueSize < 0 {
+		config.QueueSize = 0
+	}
+	std.RegisterHints()
+
+	s := &Server{
+		opts:     config.ProverOptions,
+		circuits: make(map[string]*circuit),
+		jobs:     make(chan *job, config.QueueSize),
+	}
+	s.wg.Add(config.Workers)
+	for i := 0; i < config.Workers; i++ {
+		go s.worker()
+	}
+	return s
+}
+
+// AddGroth16 adds a circuit, compiled with r1cs.NewBuilder, and its groth16 proving key to the server
+func (s *Server) AddGroth16(name string, ccs frontend.CompiledConstraintSystem, pk groth16.ProvingKey) error {
+	if ccs.CurveID() != pk.CurveID() {
+		return fmt.Errorf("constraint system on %s but proving key on %s", ccs.CurveID(), pk.CurveID())
+	}
+	return s.add(name, &circuit{
+		ccs:     ccs,
+		backend: backend.GROTH16,
+		prove: func(fullWitness *witness.Witness, opts ...backend.ProverOption) (io.WriterTo, error) {
+			return groth16.Prove(ccs, pk, fullWitness, opts...)
+		},
+	})
+}
+
+// AddPlonk adds a circuit, compiled with scs.NewBuilder, and its plonk proving key to the server.
+// The KZG SRS of the proving key must be set (see plonk.ProvingKey InitKZG).
+func (s *Server) AddPlonk(name string, ccs frontend.CompiledConstraintSystem, pk plonk.ProvingKey) error {
+	return s.add(name, &circuit{
+		ccs:     ccs,
+		backend: backend.PLONK,
+		prove: func(fullWitness *witness.Witness, opts ...backend.ProverOption) (io.WriterTo, error) {
+			return plonk.Prove(ccs, pk, fullWitness, opts...)
+		},
+	})
+}
+
+// Load reads a constraint system and its proving key from files written with artifact.Write, and adds
+// them to the server. srs is needed for plonk only.
+func (s *Server) Load(name string, b backend.ID, curveID ecc.ID, ccsPath, pkPath string, srs kzg.SRS) error {
+	switch b {
+	case backend.GROTH16:
+		ccs, pk := groth16.NewCS(curveID), groth16.NewProvingKey(curveID)
+		if err := readKeys(ccsPath, ccs, pkPath, pk); err != nil {
+			return err
+		}
+		return s.AddGroth16(name, ccs, pk)
+	case backend.PLONK:
+		if srs == nil {
+			return errors.New("plonk proving key needs a KZG SRS")
+		}
+		ccs, pk := plonk.NewCS(curveID), plonk.NewProvingKey(curveID)
+		if err := readKeys(ccsPath, ccs, pkPath, pk); err != nil {
+			return err
+		}
+		if err := pk.InitKZG(srs); err != nil {
+			return err
+		}
+		return s.AddPlonk(name, ccs, pk)
+	default:
+		return fmt.Errorf("unsupported backend %s", b)
+	}
+}
+
+func (s *Server) add(name string, c *circuit) error {
+	s.circuitsLock.Lock()
+	defer s.circuitsLock.Unlock()
+	if _, ok := s.circuits[name]; ok {
+		return fmt.Errorf("circuit %q already added", name)
+	}
+	s.circuits[name] = c
+	return nil
+}
+
+func (s *Server) circuit(name string) (*circuit, error) {
+	s.circuitsLock.RLock()
+	defer s.circuitsLock.RUnlock()
+	c, ok := s.circuits[name]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownCircuit, name)
+	}
+	return c, nil
+}
+
+// Circuits returns the description of the circuits of the server, sorted by name
+func (s *Server) Circuits() []CircuitInfo {
+	s.circuitsLock.RLock()
+	defer s.circuitsLock.RUnlock()
+
+	r := make([]CircuitInfo, 0, len(s.circuits))
+	for name, c := range s.circuits {
+		info := CircuitInfo{
+			Name:          name,
+			Curve:         c.ccs.CurveID().String(),
+			Backend:       c.backend.String(),
+			NbConstraints: c.ccs.GetNbConstraints(),
+		}
+		if s := c.ccs.GetSchema(); s != nil {
+			info.NbPublic, info.NbSecret = s.NbPublic, s.NbSecret
+		}
+		r = append(r, info)
+	}
+	sort.Slice(r, func(i, j int) bool { return r[i].Name < r[j].Name })
+	return r
+}
+
+// Prove queues a proving job for the named circuit and waits for its result.
+//
+// fullWitness must be a full witness on the curve of the circuit. Prove returns ErrQueueFull right away
+// if the queue is full, and the context error if ctx is done before the job completes; a job whose
+// context is done before it is picked by a worker is dropped.
+func (s *Server) Prove(ctx context.Context, name string, fullWitness *witness.Witness) (*Result, error) {
+	c, err := s.circuit(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkWitness(c, fullWitness); err != nil {
+		return nil, err
+	}
+
+	j := &job{
+		ctx:       ctx,
+		circuit:   c,
+		witness:   fullWitness,
+		submitted: time.Now(),
+		done:      make(chan jobResult, 1),
+	}
+	if err := s.submit(j); err != nil {
+		return nil, err
+	}
+
+	select {
+	case r := <-j.done:
+		return r.result, r.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (s *Server) submit(j *job) error {
+	s.jobsLock.RLock()
+	defer s.jobsLock.RUnlock()
+	if s.closed {
+		return ErrClosed
+	}
+	select {
+	case s.jobs <- j:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+func (s *Server) worker() {
+	defer s.wg.Done()
+	log := logger.Logger().With().Str("component", "server").Logger()
+
+	for j := range s.jobs {
+		if err := j.ctx.Err(); err != nil {
+			j.done <- jobResult{err: err}
+			continue
+		}
+
+		start := time.Now()
+		proof, err := j.circuit.prove(j.witness, s.opts...)
+		if err != nil {
+			log.Debug().Err(err).Msg("proving job failed")
+			j.done <- jobResult{err: err}
+			continue
+		}
+		r := &Result{Wait: start.Sub(j.submitted), Prove: time.Since(start)}
+		if r.Proof, err = encode(proof); err != nil {
+			j.done <- jobResult{err: err}
+			continue
+		}
+		log.Debug().Dur("wait", r.Wait).Dur("prove", r.Prove).Msg("proving job done")
+		j.done <- jobResult{result: r}
+	}
+}
+
+// Close rejects new jobs with ErrClosed, and waits for the queued jobs to complete
+func (s *Server) Close() {
+	s.jobsLock.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.jobs)
+	}
+	s.jobsLock.Unlock()
+	s.wg.Wait()
+}
+
+// checkWitness returns an error if w is not a full witness of c
+func checkWitness(c *circuit, w *witness.Witness) error {
+	if w == nil || w.Vector == nil {
+		return fmt.Errorf("%w: empty witness", witness.ErrInvalidWitness)
+	}
+	if w.CurveID != c.ccs.CurveID() {
+		return fmt.Errorf("%w: witness on %s but circuit on %s", witness.ErrInvalidWitness, w.CurveID, c.ccs.CurveID())
+	}
+	if s := c.ccs.GetSchema(); s != nil && w.Vector.Len() != s.NbPublic+s.NbSecret {
+		return fmt.Errorf("%w: expected a full witness of %d elements, got %d", witness.ErrInvalidWitness, s.NbPublic+s.NbSecret, w.Vector.Len())
+	}
+	return nil
+}
+
+func encode(o io.WriterTo) ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := o.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// readFile decodes the artifact at path into o
+func readFile(path string, o interface{}) (artifact.Header, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return artifact.Header{}, err
+	}
+	defer f.Close()
+	h, err := artifact.ReadInto(bufio.NewReader(f), o)
+	if err != nil {
+		return h, fmt.Errorf("reading %s: %w", path, err)
+	}
+	return h, nil
+}
+
+// readKeys reads the constraint system and the proving key, which must belong to the same circuit
+func readKeys(ccsPath string, ccs interface{}, pkPath string, pk interface{}) error {
+	hCCS, err := readFile(ccsPath, ccs)
+	if err != nil {
+		return err
+	}
+	hPK, err := readFile(pkPath, pk)
+	if err != nil {
+		return err
+	}
+	var zero [32]byte
+	if hPK.CircuitHash != zero && hPK.CircuitHash != hCCS.CircuitHash {
+		return fmt.Errorf("%s was written for another circuit than %s", pkPath, ccsPath)
+	}
+	return nil
+}
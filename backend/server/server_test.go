@@ -0,0 +1,280 @@
+// Copyright 2022 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/artifact"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/hint"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/test"
+	"github.com/stretchr/testify/require"
+)
+
+type cubicCircuit struct {
+	X frontend.Variable `gnark:"x"`
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (circuit *cubicCircuit) Define(api frontend.API) error {
+	x3 := api.Mul(circuit.X, circuit.X, circuit.X)
+	api.AssertIsEqual(circuit.Y, api.Add(x3, circuit.X, 5))
+	return nil
+}
+
+func cubicWitness(assert *require.Assertions, curveID ecc.ID, y int) (full, public *witness.Witness) {
+	full, err := frontend.NewWitness(&cubicCircuit{X: 3, Y: y}, curveID)
+	assert.NoError(err)
+	public, err = full.Public()
+	assert.NoError(err)
+	return
+}
+
+func addGroth16(assert *require.Assertions, s *Server, name string, curveID ecc.ID, circuit frontend.Circuit) groth16.VerifyingKey {
+	ccs, err := frontend.Compile(curveID, r1cs.NewBuilder, circuit)
+	assert.NoError(err)
+	pk, vk, err := groth16.Setup(ccs)
+	assert.NoError(err)
+	assert.NoError(s.AddGroth16(name, ccs, pk))
+	return vk
+}
+
+func TestGroth16(t *testing.T) {
+	assert := require.New(t)
+
+	s := New(Config{Workers: 2, QueueSize: 8})
+	defer s.Close()
+	vk := addGroth16(assert, s, "cubic", ecc.BN254, &cubicCircuit{})
+	client, stop := NewTestClient(s)
+	defer stop()
+
+	full, public := cubicWitness(assert, ecc.BN254, 35)
+	jsonWitness, err := full.MarshalJSON()
+	assert.NoError(err)
+
+	verify := func(r *Result) {
+		proof := groth16.NewProof(ecc.BN254)
+		_, err := proof.ReadFrom(bytes.NewReader(r.Proof))
+		assert.NoError(err)
+		assert.NoError(groth16.Verify(proof, vk, public))
+		assert.True(r.Prove > 0)
+	}
+
+	// in-process, and over HTTP with binary and JSON witnesses
+	r, err := s.Prove(context.Background(), "cubic", full)
+	assert.NoError(err)
+	verify(r)
+	r, err = client.Prove(context.Background(), "cubic", full)
+	assert.NoError(err)
+	verify(r)
+	r, err = client.ProveJSON(context.Background(), "cubic", jsonWitness)
+	assert.NoError(err)
+	verify(r)
+
+	// concurrent jobs share the proving key
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r, err := client.Prove(context.Background(), "cubic", full)
+			assert.NoError(err)
+			verify(r)
+		}()
+	}
+	wg.Wait()
+
+	circuits, err := client.Circuits(context.Background())
+	assert.NoError(err)
+	assert.Equal([]CircuitInfo{{Name: "cubic", Curve: "BN254", Backend: "groth16", NbConstraints: 3, NbPublic: 1, NbSecret: 1}}, circuits)
+}
+
+func TestPlonk(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BLS12_377, scs.NewBuilder, &cubicCircuit{})
+	assert.NoError(err)
+	srs, err := test.NewKZGSRS(ccs)
+	assert.NoError(err)
+	pk, vk, err := plonk.Setup(ccs, srs)
+	assert.NoError(err)
+
+	s := New(Config{Workers: 1, QueueSize: 1})
+	defer s.Close()
+	assert.NoError(s.AddPlonk("cubic", ccs, pk))
+	client, stop := NewTestClient(s)
+	defer stop()
+
+	full, public := cubicWitness(assert, ecc.BLS12_377, 35)
+	r, err := client.Prove(context.Background(), "cubic", full)
+	assert.NoError(err)
+
+	proof := plonk.NewProof(ecc.BLS12_377)
+	_, err = proof.ReadFrom(bytes.NewReader(r.Proof))
+	assert.NoError(err)
+	assert.NoError(plonk.Verify(proof, vk, public))
+}
+
+func TestErrors(t *testing.T) {
+	assert := require.New(t)
+
+	s := New(Config{Workers: 1})
+	addGroth16(assert, s, "cubic", ecc.BN254, &cubicCircuit{})
+	client, stop := NewTestClient(s)
+	defer stop()
+	ctx := context.Background()
+
+	full, public := cubicWitness(assert, ecc.BN254, 35)
+	_, err := client.Prove(ctx, "quartic", full)
+	assert.True(errors.Is(err, ErrUnknownCircuit), err)
+
+	// public witness, witness on another curve, malformed JSON
+	_, err = client.Prove(ctx, "cubic", public)
+	assert.True(errors.Is(err, witness.ErrInvalidWitness), err)
+	other, _ := cubicWitness(assert, ecc.BLS12_381, 35)
+	_, err = s.Prove(ctx, "cubic", other)
+	assert.True(errors.Is(err, witness.ErrInvalidWitness), err)
+	_, err = client.ProveJSON(ctx, "cubic", []byte(`{"x":3,"Z":35}`))
+	assert.True(errors.Is(err, witness.ErrInvalidWitness), err)
+
+	// unsatisfied constraint
+	wrong, _ := cubicWitness(assert, ecc.BN254, 36)
+	_, err = client.Prove(ctx, "cubic", wrong)
+	assert.Error(err)
+
+	s.Close()
+	_, err = s.Prove(ctx, "cubic", full)
+	assert.True(errors.Is(err, ErrClosed), err)
+}
+
+// blockingCircuit calls a hint which waits for the test to release it
+type blockingCircuit struct {
+	X frontend.Variable
+}
+
+var (
+	hintStarted = make(chan struct{})
+	hintRelease = make(chan struct{})
+)
+
+func blockingHint(_ ecc.ID, inputs []*big.Int, results []*big.Int) error {
+	hintStarted <- struct{}{}
+	<-hintRelease
+	results[0].Set(inputs[0])
+	return nil
+}
+
+func init() {
+	hint.Register(blockingHint)
+}
+
+func (circuit *blockingCircuit) Define(api frontend.API) error {
+	res, err := api.Compiler().NewHint(blockingHint, 1, circuit.X)
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(res[0], circuit.X)
+	return nil
+}
+
+func TestBackpressure(t *testing.T) {
+	assert := require.New(t)
+
+	s := New(Config{Workers: 1, QueueSize: 1})
+	defer s.Close()
+	addGroth16(assert, s, "blocking", ecc.BN254, &blockingCircuit{})
+	client, stop := NewTestClient(s)
+	defer stop()
+
+	full, err := frontend.NewWitness(&blockingCircuit{X: 1}, ecc.BN254)
+	assert.NoError(err)
+
+	// the first job blocks the worker, the second one waits in the queue
+	errs := make(chan error, 2)
+	prove := func() {
+		_, err := s.Prove(context.Background(), "blocking", full)
+		errs <- err
+	}
+	go prove()
+	<-hintStarted
+	go prove()
+	for len(s.jobs) == 0 {
+		runtime.Gosched()
+	}
+
+	_, err = s.Prove(context.Background(), "blocking", full)
+	assert.True(errors.Is(err, ErrQueueFull), err)
+	_, err = client.Prove(context.Background(), "blocking", full)
+	assert.True(errors.Is(err, ErrQueueFull), err)
+
+	close(hintRelease)
+	<-hintStarted
+	assert.NoError(<-errs)
+	assert.NoError(<-errs)
+}
+
+func TestLoad(t *testing.T) {
+	assert := require.New(t)
+	dir := t.TempDir()
+
+	ccs, err := frontend.Compile(ecc.BN254, r1cs.NewBuilder, &cubicCircuit{})
+	assert.NoError(err)
+	pk, vk, err := groth16.Setup(ccs)
+	assert.NoError(err)
+	circuitHash, err := artifact.CircuitHash(ccs)
+	assert.NoError(err)
+
+	write := func(name string, o interface{}) string {
+		var buf bytes.Buffer
+		_, err := artifact.Write(&buf, o, circuitHash[:])
+		assert.NoError(err)
+		path := filepath.Join(dir, name)
+		assert.NoError(os.WriteFile(path, buf.Bytes(), 0600))
+		return path
+	}
+	ccsPath, pkPath := write("cubic.ccs", ccs), write("cubic.pk", pk)
+
+	s := New(Config{Workers: 1})
+	defer s.Close()
+	assert.NoError(s.Load("cubic", backend.GROTH16, ecc.BN254, ccsPath, pkPath, nil))
+
+	// a key of another curve is rejected
+	err = s.Load("other", backend.GROTH16, ecc.BLS12_381, ccsPath, pkPath, nil)
+	assert.True(errors.Is(err, artifact.ErrMismatch), err)
+
+	full, public := cubicWitness(assert, ecc.BN254, 35)
+	r, err := s.Prove(context.Background(), "cubic", full)
+	assert.NoError(err)
+	proof := groth16.NewProof(ecc.BN254)
+	_, err = proof.ReadFrom(bytes.NewReader(r.Proof))
+	assert.NoError(err)
+	assert.NoError(groth16.Verify(proof, vk, public))
+}
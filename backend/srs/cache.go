@@ -0,0 +1,162 @@
+// Copyright 2022 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package srs
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/kzg"
+)
+
+const cacheExt = ".srs"
+
+// Cache stores SRS on disk, in files named <curve>_<size>.srs holding their kzg.SRS.WriteTo encoding
+type Cache struct {
+	// Dir is the directory of the cache; Store creates it if needed
+	Dir string
+}
+
+// Get returns the SRS on curveID with size powers of τ in G₁ from the cache. If the cache doesn't
+// hold it or a larger SRS, read is called, typically to read the files of a ceremony with
+// ReadPowersOfTau or ReadIgnition; its result is truncated, validated and stored in the cache.
+func (c Cache) Get(curveID ecc.ID, size uint64, read func() (kzg.SRS, error)) (kzg.SRS, error) {
+	srs, err := c.Load(curveID, size)
+	if !errors.Is(err, fs.ErrNotExist) {
+		return srs, err
+	}
+
+	if srs, err = read(); err != nil {
+		return nil, err
+	}
+	if srsCurveID, _, err := sizeOf(srs); err != nil {
+		return nil, err
+	} else if srsCurveID != curveID {
+		return nil, fmt.Errorf("read a %s SRS, expected %s", srsCurveID, curveID)
+	}
+	if srs, err = Truncate(srs, size); err != nil {
+		return nil, err
+	}
+	if err := Validate(srs); err != nil {
+		return nil, err
+	}
+	return srs, c.Store(srs)
+}
+
+// Load returns the SRS on curveID with size powers of τ in G₁ from the cache. If the cache only holds
+// larger SRS, the smallest one is truncated, and stored for the next calls. The returned error wraps
+// fs.ErrNotExist if the cache holds no SRS large enough.
+//
+// The points are checked to be on the curve and in the correct subgroup when they are read.
+func (c Cache) Load(curveID ecc.ID, size uint64) (kzg.SRS, error) {
+	srs, err := c.read(curveID, size)
+	if !errors.Is(err, fs.ErrNotExist) {
+		return srs, err
+	}
+
+	entries, err := os.ReadDir(c.Dir)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	prefix := c.prefix(curveID)
+	var larger uint64
+	for _, e := range entries {
+		name := e.Name()
+		if !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, cacheExt) {
+			continue
+		}
+		n, err := strconv.ParseUint(strings.TrimSuffix(strings.TrimPrefix(name, prefix), cacheExt), 10, 64)
+		if err != nil || n < size {
+			continue
+		}
+		if larger == 0 || n < larger {
+			larger = n
+		}
+	}
+	if larger == 0 {
+		return nil, fmt.Errorf("%w: no %s SRS of size %d or more in %s", fs.ErrNotExist, curveID, size, c.Dir)
+	}
+
+	if srs, err = c.read(curveID, larger); err != nil {
+		return nil, err
+	}
+	if srs, err = Truncate(srs, size); err != nil {
+		return nil, err
+	}
+	return srs, c.Store(srs)
+}
+
+// Store writes srs in the cache, replacing the SRS of the same curve and size if any
+func (c Cache) Store(srs kzg.SRS) error {
+	curveID, size, err := sizeOf(srs)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(c.Dir, 0750); err != nil {
+		return err
+	}
+
+	// write to a temporary file first, so that readers never see a partial SRS
+	f, err := os.CreateTemp(c.Dir, c.prefix(curveID)+"*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	w := bufio.NewWriter(f)
+	if _, err := srs.WriteTo(w); err != nil {
+		f.Close()
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), c.path(curveID, size))
+}
+
+func (c Cache) read(curveID ecc.ID, size uint64) (kzg.SRS, error) {
+	f, err := os.Open(c.path(curveID, size))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	srs := kzg.NewSRS(curveID)
+	if _, err := srs.ReadFrom(bufio.NewReader(f)); err != nil {
+		return nil, fmt.Errorf("%s: %w", f.Name(), err)
+	}
+	if _, n, _ := sizeOf(srs); n != size {
+		return nil, fmt.Errorf("%s: SRS of size %d", f.Name(), n)
+	}
+	return srs, nil
+}
+
+func (c Cache) prefix(curveID ecc.ID) string {
+	return strings.ToLower(curveID.String()) + "_"
+}
+
+func (c Cache) path(curveID ecc.ID, size uint64) string {
+	return filepath.Join(c.Dir, c.prefix(curveID)+strconv.FormatUint(size, 10)+cacheExt)
+}
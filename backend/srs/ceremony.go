@@ -0,0 +1,263 @@
+// Copyright 2022 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package srs
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	kzg_bn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr/kzg"
+	"github.com/consensys/gnark-crypto/kzg"
+)
+
+// metadata of the encoding of points in the powersoftau crate (pairing_ce)
+const (
+	ppotHashSize             = 64
+	ppotInfinity        byte = 1 << 6
+	ppotLargest         byte = 1 << 7 // compressed points only
+	ppotMask                 = ppotInfinity | ppotLargest
+	ppotMaxPower             = 28
+	ignitionG1Size           = 64
+	ignitionG2Size           = 128
+	ignitionLimbSize         = 8
+	ignitionElementSize      = 32
+)
+
+// metadata of the encoding of points in gnark-crypto, see bn254.G1Affine.Bytes
+const (
+	mCompressedSmallest byte = 0b10 << 6
+	mCompressedLargest  byte = 0b11 << 6
+	mCompressedInfinity byte = 0b01 << 6
+)
+
+// ReadPowersOfTau reads the size first powers of τ of a BN254 Powers of Tau ceremony with 2^power
+// powers, from one of its challenge (uncompressed points) or response (compressed points) files,
+// as written by the powersoftau crate of the perpetual powers of tau
+// (https://github.com/weijiekoh/perpetualpowersoftau) and of the phase 1 of snarkjs, Hermez, ...:
+//
+//	[hash [64]byte | [τⁱ]G₁ for i < 2^(power+1)-1 | [τⁱ]G₂ for i < 2^power | [ατⁱ]G₁ ... ]
+//
+// Coordinates are big-endian, starting with c1 in G₂. In the first byte of a point, 1<<6 flags the
+// point at infinity and, if compressed, 1<<7 flags the lexicographically largest y.
+//
+// The points are checked to be on the curve and in the correct subgroup; the returned SRS must be
+// checked with Validate.
+func ReadPowersOfTau(r io.Reader, power uint8, compressed bool, size uint64) (kzg.SRS, error) {
+	if power > ppotMaxPower {
+		return nil, fmt.Errorf("power %d is larger than %d", power, ppotMaxPower)
+	}
+	nbG1 := uint64(1)<<(power+1) - 1
+	if size < 2 || size > nbG1 {
+		return nil, fmt.Errorf("size %d out of the [2, %d] range of the ceremony", size, nbG1)
+	}
+	g1Size, g2Size := bn254.SizeOfG1AffineUncompressed, bn254.SizeOfG2AffineUncompressed
+	if compressed {
+		g1Size, g2Size = bn254.SizeOfG1AffineCompressed, bn254.SizeOfG2AffineCompressed
+	}
+
+	var srs kzg_bn254.SRS
+	if err := skip(r, ppotHashSize); err != nil {
+		return nil, err
+	}
+	if err := decodePowersOfTau(r, size, g1Size, compressed, &srs.G1); err != nil {
+		return nil, fmt.Errorf("powers of τ in G₁: %w", err)
+	}
+	if err := skip(r, int64(nbG1-size)*int64(g1Size)); err != nil {
+		return nil, err
+	}
+	var g2 []bn254.G2Affine
+	if err := decodePowersOfTau(r, 2, g2Size, compressed, &g2); err != nil {
+		return nil, fmt.Errorf("powers of τ in G₂: %w", err)
+	}
+	copy(srs.G2[:], g2)
+
+	return &srs, nil
+}
+
+// decodePowersOfTau reads n points of pointSize bytes in the encoding of the powersoftau crate,
+// and decodes them in v with a bn254.Decoder, which decompresses and checks them in parallel
+func decodePowersOfTau(r io.Reader, n uint64, pointSize int, compressed bool, v interface{}) error {
+	buf := make([]byte, 4+n*uint64(pointSize))
+	binary.BigEndian.PutUint32(buf[:4], uint32(n))
+	if _, err := io.ReadFull(r, buf[4:]); err != nil {
+		return err
+	}
+	for i := 4; i < len(buf); i += pointSize {
+		msb := buf[i] & ppotMask
+		buf[i] &^= ppotMask
+		switch {
+		case compressed && msb&ppotInfinity != 0:
+			buf[i] |= mCompressedInfinity
+		case compressed && msb&ppotLargest != 0:
+			buf[i] |= mCompressedLargest
+		case compressed:
+			buf[i] |= mCompressedSmallest
+		case msb&ppotLargest != 0:
+			return errors.New("compressed point in an uncompressed file")
+		}
+		// gnark-crypto encodes the uncompressed point at infinity as (0, 0)
+	}
+	return bn254.NewDecoder(bytes.NewReader(buf)).Decode(v)
+}
+
+// ignitionManifest is the header of a transcript of the Aztec Ignition ceremony
+type ignitionManifest struct {
+	TranscriptNumber uint32
+	TotalTranscripts uint32
+	TotalG1Points    uint32
+	TotalG2Points    uint32
+	NbG1Points       uint32
+	NbG2Points       uint32
+	StartFrom        uint32 // index of the first G₁ point of the transcript in the ceremony
+}
+
+// ReadIgnition reads the size first powers of τ of the Aztec Ignition ceremony on BN254
+// (https://github.com/AztecProtocol/ignition-verification), from its transcripts stored in dir as
+// transcript00.dat, transcript01.dat, ... Only the transcripts holding the size first powers are read.
+//
+// A transcript starts with a manifest of 7 big-endian uint32:
+//
+//	[transcript number | nb transcripts | nb G₁ points | nb G₂ points | nb G₁ points in the transcript | nb G₂ points in the transcript | index of the first G₁ point]
+//
+// followed by its uncompressed points: [τⁱ]G₁ for 1 ≤ i, and in the first transcript [τ]G₂. A
+// coordinate is made of 4 big-endian 64-bit limbs, the least significant first, and starts with c0 in G₂.
+//
+// The points are checked to be on the curve and in the correct subgroup; the returned SRS must be
+// checked with Validate.
+func ReadIgnition(dir string, size uint64) (kzg.SRS, error) {
+	if size < 2 {
+		return nil, fmt.Errorf("size %d is smaller than 2", size)
+	}
+	srs := kzg_bn254.SRS{G1: make([]bn254.G1Affine, 1, size)}
+	_, _, srs.G1[0], srs.G2[0] = bn254.Generators()
+
+	for i := 0; uint64(len(srs.G1)) < size; i++ {
+		path := filepath.Join(dir, fmt.Sprintf("transcript%02d.dat", i))
+		if err := readIgnitionTranscript(path, uint32(i), size, &srs); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	return &srs, nil
+}
+
+// readIgnitionTranscript appends the points of the transcript at path to srs, up to size
+func readIgnitionTranscript(path string, number uint32, size uint64, srs *kzg_bn254.SRS) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	r := bufio.NewReaderSize(f, 1<<20)
+
+	var m ignitionManifest
+	if err := binary.Read(r, binary.BigEndian, &m); err != nil {
+		return err
+	}
+	if m.TranscriptNumber != number || uint64(m.StartFrom) != uint64(len(srs.G1)-1) {
+		return fmt.Errorf("unexpected transcript %d starting from point %d", m.TranscriptNumber, m.StartFrom)
+	}
+	if uint64(m.TotalG1Points)+1 < size {
+		return fmt.Errorf("the ceremony has %d powers of τ, %d needed", m.TotalG1Points+1, size)
+	}
+	if m.NbG1Points == 0 {
+		return errors.New("no points in the transcript")
+	}
+
+	n := size - uint64(len(srs.G1))
+	if n > uint64(m.NbG1Points) {
+		n = uint64(m.NbG1Points)
+	}
+	var g1 []bn254.G1Affine
+	if err := decodeIgnition(r, n, ignitionG1Size, &g1); err != nil {
+		return fmt.Errorf("powers of τ in G₁: %w", err)
+	}
+	srs.G1 = append(srs.G1, g1...)
+
+	if number != 0 {
+		return nil
+	}
+	if m.NbG2Points == 0 {
+		return errors.New("no points in G₂ in the first transcript")
+	}
+	if err := skip(r, int64(uint64(m.NbG1Points)-n)*ignitionG1Size); err != nil {
+		return err
+	}
+	var g2 []bn254.G2Affine
+	if err := decodeIgnition(r, 1, ignitionG2Size, &g2); err != nil {
+		return fmt.Errorf("powers of τ in G₂: %w", err)
+	}
+	srs.G2[1] = g2[0]
+	return nil
+}
+
+// decodeIgnition reads n points of pointSize bytes in the encoding of Ignition, and decodes them in
+// v with a bn254.Decoder
+func decodeIgnition(r io.Reader, n uint64, pointSize int, v interface{}) error {
+	buf := make([]byte, 4+n*uint64(pointSize))
+	binary.BigEndian.PutUint32(buf[:4], uint32(n))
+	if _, err := io.ReadFull(r, buf[4:]); err != nil {
+		return err
+	}
+	for i := 4; i < len(buf); i += pointSize {
+		p := buf[i : i+pointSize]
+
+		// reverse the order of the limbs of each coordinate to get big-endian coordinates
+		for j := 0; j < pointSize; j += ignitionElementSize {
+			e := p[j : j+ignitionElementSize]
+			for k := 0; k < ignitionElementSize/2; k += ignitionLimbSize {
+				var limb [ignitionLimbSize]byte
+				l := ignitionElementSize - ignitionLimbSize - k
+				copy(limb[:], e[k:k+ignitionLimbSize])
+				copy(e[k:k+ignitionLimbSize], e[l:l+ignitionLimbSize])
+				copy(e[l:l+ignitionLimbSize], limb[:])
+			}
+		}
+
+		// gnark-crypto encodes c1 first
+		if pointSize == ignitionG2Size {
+			for j := 0; j < pointSize; j += 2 * ignitionElementSize {
+				var c0 [ignitionElementSize]byte
+				copy(c0[:], p[j:j+ignitionElementSize])
+				copy(p[j:j+ignitionElementSize], p[j+ignitionElementSize:j+2*ignitionElementSize])
+				copy(p[j+ignitionElementSize:j+2*ignitionElementSize], c0[:])
+			}
+		}
+
+		if p[0]&ppotMask != 0 {
+			return errors.New("invalid coordinate: larger than the modulus")
+		}
+	}
+	return bn254.NewDecoder(bytes.NewReader(buf)).Decode(v)
+}
+
+// skip discards the next n bytes of r
+func skip(r io.Reader, n int64) error {
+	if n == 0 {
+		return nil
+	}
+	if s, ok := r.(io.Seeker); ok {
+		_, err := s.Seek(n, io.SeekCurrent)
+		return err
+	}
+	_, err := io.CopyN(io.Discard, r, n)
+	return err
+}
@@ -0,0 +1,110 @@
+// Copyright 2022 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package srs provides the KZG structured reference strings (SRS) needed by plonk.Setup, from the
+// public outputs of Powers of Tau ceremonies.
+//
+// A KZG SRS holds [τⁱ]G₁ for i < size, and G₂, [τ]G₂, for a τ that nobody knows as long as one
+// participant of the ceremony was honest. test.NewKZGSRS samples τ itself: it is only suitable for
+// tests.
+//
+// Ceremony files hold far more points than a circuit needs and are slow to read. They are read once
+// (ReadPowersOfTau, ReadIgnition), checked with pairings (Validate), truncated to the size of the
+// circuit (Size, Truncate), and stored in a Cache keyed by curve and size:
+//
+//	cache := srs.Cache{Dir: dir}
+//	size := srs.Size(ccs)
+//	kzgSRS, err := cache.Get(ecc.BN254, size, func() (kzg.SRS, error) {
+//		return srs.ReadIgnition(ignitionDir, size)
+//	})
+//	pk, vk, err := plonk.Setup(ccs, kzgSRS)
+package srs
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/kzg"
+	"github.com/consensys/gnark/frontend"
+
+	kzg_bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377/fr/kzg"
+	kzg_bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/fr/kzg"
+	kzg_bls24315 "github.com/consensys/gnark-crypto/ecc/bls24-315/fr/kzg"
+	kzg_bn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr/kzg"
+	kzg_bw6633 "github.com/consensys/gnark-crypto/ecc/bw6-633/fr/kzg"
+	kzg_bw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761/fr/kzg"
+)
+
+var (
+	// ErrInvalidSRS is returned when an SRS doesn't hold consecutive powers of τ
+	ErrInvalidSRS = errors.New("invalid SRS")
+
+	// ErrUnsupportedCurve is returned for SRS on curves gnark doesn't support
+	ErrUnsupportedCurve = errors.New("unsupported curve")
+)
+
+// Size returns the number of powers of τ in G₁ plonk.Setup needs for ccs
+func Size(ccs frontend.CompiledConstraintSystem) uint64 {
+	nbConstraints := ccs.GetNbConstraints()
+	_, _, public := ccs.GetNbVariables()
+	return ecc.NextPowerOfTwo(uint64(nbConstraints+public)) + 3
+}
+
+// Truncate returns an SRS holding the size first powers of τ in G₁ of srs; it shares its points with srs
+func Truncate(srs kzg.SRS, size uint64) (kzg.SRS, error) {
+	curveID, n, err := sizeOf(srs)
+	if err != nil {
+		return nil, err
+	}
+	if n < size {
+		return nil, fmt.Errorf("%s SRS of size %d can't be truncated to %d", curveID, n, size)
+	}
+	switch s := srs.(type) {
+	case *kzg_bn254.SRS:
+		return &kzg_bn254.SRS{G1: s.G1[:size], G2: s.G2}, nil
+	case *kzg_bls12381.SRS:
+		return &kzg_bls12381.SRS{G1: s.G1[:size], G2: s.G2}, nil
+	case *kzg_bls12377.SRS:
+		return &kzg_bls12377.SRS{G1: s.G1[:size], G2: s.G2}, nil
+	case *kzg_bw6761.SRS:
+		return &kzg_bw6761.SRS{G1: s.G1[:size], G2: s.G2}, nil
+	case *kzg_bls24315.SRS:
+		return &kzg_bls24315.SRS{G1: s.G1[:size], G2: s.G2}, nil
+	case *kzg_bw6633.SRS:
+		return &kzg_bw6633.SRS{G1: s.G1[:size], G2: s.G2}, nil
+	default:
+		panic("unreachable")
+	}
+}
+
+// sizeOf returns the curve of srs and its number of powers of τ in G₁
+func sizeOf(srs kzg.SRS) (ecc.ID, uint64, error) {
+	switch s := srs.(type) {
+	case *kzg_bn254.SRS:
+		return ecc.BN254, uint64(len(s.G1)), nil
+	case *kzg_bls12381.SRS:
+		return ecc.BLS12_381, uint64(len(s.G1)), nil
+	case *kzg_bls12377.SRS:
+		return ecc.BLS12_377, uint64(len(s.G1)), nil
+	case *kzg_bw6761.SRS:
+		return ecc.BW6_761, uint64(len(s.G1)), nil
+	case *kzg_bls24315.SRS:
+		return ecc.BLS24_315, uint64(len(s.G1)), nil
+	case *kzg_bw6633.SRS:
+		return ecc.BW6_633, uint64(len(s.G1)), nil
+	default:
+		return ecc.UNKNOWN, 0, fmt.Errorf("%w: %T", ErrUnsupportedCurve, srs)
+	}
+}
@@ -0,0 +1,296 @@
+// Copyright 2022 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package srs
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	kzg_bn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr/kzg"
+	kzg_bw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761/fr/kzg"
+	"github.com/consensys/gnark-crypto/kzg"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/stretchr/testify/require"
+)
+
+var tau = big.NewInt(424242)
+
+type cubicCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (circuit *cubicCircuit) Define(api frontend.API) error {
+	x3 := api.Mul(circuit.X, circuit.X, circuit.X)
+	api.AssertIsEqual(circuit.Y, api.Add(x3, circuit.X, 5))
+	return nil
+}
+
+// writePowersOfTau writes the beginning of a challenge or response file of a ceremony with 2^power
+// powers of tau, up to [τ]G₂
+func writePowersOfTau(t *testing.T, power uint8, compressed bool) []byte {
+	nbG1 := uint64(1)<<(power+1) - 1
+	srs, err := kzg_bn254.NewSRS(nbG1, tau)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	buf.Write(make([]byte, ppotHashSize))
+	toPowersOfTau := func(b []byte) []byte {
+		if compressed {
+			switch b[0] & (0b11 << 6) {
+			case mCompressedLargest:
+				b[0] = b[0]&^mCompressedLargest | ppotLargest
+			case mCompressedSmallest:
+				b[0] &^= mCompressedSmallest
+			}
+		}
+		return b
+	}
+	for i := range srs.G1 {
+		if compressed {
+			b := srs.G1[i].Bytes()
+			buf.Write(toPowersOfTau(b[:]))
+		} else {
+			b := srs.G1[i].RawBytes()
+			buf.Write(b[:])
+		}
+	}
+	for _, p := range srs.G2 {
+		if compressed {
+			b := p.Bytes()
+			buf.Write(toPowersOfTau(b[:]))
+		} else {
+			b := p.RawBytes()
+			buf.Write(b[:])
+		}
+	}
+	return buf.Bytes()
+}
+
+// writeIgnition writes the powers of τ of srs in transcripts of nbPoints G₁ points in dir
+func writeIgnition(t *testing.T, dir string, srs *kzg_bn254.SRS, nbPoints int) {
+	// Ignition coordinates are little-endian sequences of big-endian limbs
+	toIgnition := func(b []byte) []byte {
+		for j := 0; j < len(b); j += ignitionElementSize {
+			e := b[j : j+ignitionElementSize]
+			for k := 0; k < ignitionElementSize/2; k += ignitionLimbSize {
+				l := ignitionElementSize - ignitionLimbSize - k
+				for m := 0; m < ignitionLimbSize; m++ {
+					e[k+m], e[l+m] = e[l+m], e[k+m]
+				}
+			}
+		}
+		return b
+	}
+
+	g1 := srs.G1[1:]
+	for i := 0; len(g1) > 0; i++ {
+		n := nbPoints
+		if n > len(g1) {
+			n = len(g1)
+		}
+		var buf bytes.Buffer
+		m := ignitionManifest{
+			TranscriptNumber: uint32(i),
+			TotalG1Points:    uint32(len(srs.G1) - 1),
+			TotalG2Points:    1,
+			NbG1Points:       uint32(n),
+			StartFrom:        uint32(len(srs.G1) - 1 - len(g1)),
+		}
+		if i == 0 {
+			m.NbG2Points = 1
+		}
+		require.NoError(t, binary.Write(&buf, binary.BigEndian, &m))
+		for j := 0; j < n; j++ {
+			b := g1[j].RawBytes()
+			buf.Write(toIgnition(b[:]))
+		}
+		if i == 0 {
+			b := srs.G2[1].RawBytes()
+			var swapped [ignitionG2Size]byte
+			for j := 0; j < ignitionG2Size; j += 2 * ignitionElementSize {
+				copy(swapped[j:], b[j+ignitionElementSize:j+2*ignitionElementSize])
+				copy(swapped[j+ignitionElementSize:], b[j:j+ignitionElementSize])
+			}
+			buf.Write(toIgnition(swapped[:]))
+		}
+		g1 = g1[n:]
+		require.NoError(t, os.WriteFile(filepath.Join(dir, fmt.Sprintf("transcript%02d.dat", i)), buf.Bytes(), 0600))
+	}
+}
+
+func TestReadPowersOfTau(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254, scs.NewBuilder, &cubicCircuit{})
+	assert.NoError(err)
+	size := Size(ccs)
+	expected, err := kzg_bn254.NewSRS(size, tau)
+	assert.NoError(err)
+
+	for _, compressed := range []bool{false, true} {
+		data := writePowersOfTau(t, 4, compressed)
+		srs, err := ReadPowersOfTau(bytes.NewReader(data), 4, compressed, size)
+		assert.NoError(err)
+		assert.Equal(expected, srs)
+		assert.NoError(Validate(srs))
+
+		// the response file is not seekable
+		srs, err = ReadPowersOfTau(bytes.NewBuffer(data), 4, compressed, size)
+		assert.NoError(err)
+		assert.Equal(expected, srs)
+
+		_, err = ReadPowersOfTau(bytes.NewReader(data[:len(data)-1]), 4, compressed, size)
+		assert.Error(err)
+		// the ceremony has 31 powers of τ in G₁
+		_, err = ReadPowersOfTau(bytes.NewReader(data), 4, compressed, 32)
+		assert.Error(err)
+	}
+
+	srs, err := ReadPowersOfTau(bytes.NewReader(writePowersOfTau(t, 4, false)), 4, false, size)
+	assert.NoError(err)
+	pk, vk, err := plonk.Setup(ccs, srs)
+	assert.NoError(err)
+	witness, err := frontend.NewWitness(&cubicCircuit{X: 3, Y: 35}, ecc.BN254)
+	assert.NoError(err)
+	publicWitness, err := witness.Public()
+	assert.NoError(err)
+	proof, err := plonk.Prove(ccs, pk, witness)
+	assert.NoError(err)
+	assert.NoError(plonk.Verify(proof, vk, publicWitness))
+}
+
+func TestReadIgnition(t *testing.T) {
+	assert := require.New(t)
+
+	expected, err := kzg_bn254.NewSRS(20, tau)
+	assert.NoError(err)
+	dir := t.TempDir()
+	writeIgnition(t, dir, expected, 8)
+
+	for _, size := range []uint64{5, 9, 20} {
+		srs, err := ReadIgnition(dir, size)
+		assert.NoError(err)
+		truncated, err := Truncate(expected, size)
+		assert.NoError(err)
+		assert.Equal(truncated, srs)
+		assert.NoError(Validate(srs))
+	}
+
+	_, err = ReadIgnition(dir, 21)
+	assert.Error(err)
+
+	// missing transcript
+	assert.NoError(os.Rename(filepath.Join(dir, "transcript01.dat"), filepath.Join(dir, "transcript02.dat")))
+	_, err = ReadIgnition(dir, 10)
+	assert.True(errors.Is(err, fs.ErrNotExist))
+
+	// transcript out of order
+	assert.NoError(os.Rename(filepath.Join(dir, "transcript02.dat"), filepath.Join(dir, "transcript01.dat")))
+	assert.NoError(os.Rename(filepath.Join(dir, "transcript00.dat"), filepath.Join(dir, "transcript02.dat")))
+	assert.NoError(os.Rename(filepath.Join(dir, "transcript01.dat"), filepath.Join(dir, "transcript00.dat")))
+	_, err = ReadIgnition(dir, 5)
+	assert.Error(err)
+}
+
+func TestValidate(t *testing.T) {
+	assert := require.New(t)
+
+	srs, err := kzg_bn254.NewSRS(10, tau)
+	assert.NoError(err)
+	assert.NoError(Validate(srs))
+
+	// a point which isn't the next power of τ
+	var wrong bn254.G1Affine
+	wrong.Add(&srs.G1[4], &srs.G1[0])
+	tampered := &kzg_bn254.SRS{G1: append([]bn254.G1Affine{}, srs.G1...), G2: srs.G2}
+	tampered.G1[4] = wrong
+	assert.True(errors.Is(Validate(tampered), ErrInvalidSRS))
+
+	// [τ]G₂ doesn't match
+	tampered = &kzg_bn254.SRS{G1: srs.G1, G2: srs.G2}
+	tampered.G2[1].Add(&srs.G2[1], &srs.G2[0])
+	assert.True(errors.Is(Validate(tampered), ErrInvalidSRS))
+
+	// τ = 1
+	one, err := kzg_bn254.NewSRS(10, big.NewInt(1))
+	assert.NoError(err)
+	assert.True(errors.Is(Validate(one), ErrInvalidSRS))
+
+	// other curves
+	other, err := kzg_bw6761.NewSRS(10, tau)
+	assert.NoError(err)
+	assert.NoError(Validate(other))
+}
+
+func TestCache(t *testing.T) {
+	assert := require.New(t)
+
+	srs, err := kzg_bn254.NewSRS(20, tau)
+	assert.NoError(err)
+	cache := Cache{Dir: filepath.Join(t.TempDir(), "srs")}
+
+	_, err = cache.Load(ecc.BN254, 10)
+	assert.True(errors.Is(err, fs.ErrNotExist))
+
+	nbReads := 0
+	read := func() (kzg.SRS, error) {
+		nbReads++
+		return srs, nil
+	}
+	got, err := cache.Get(ecc.BN254, 20, read)
+	assert.NoError(err)
+	assert.Equal(kzg.SRS(srs), got)
+	assert.Equal(1, nbReads)
+
+	// smaller SRS are truncated from the cached ones, and cached
+	got, err = cache.Get(ecc.BN254, 10, read)
+	assert.NoError(err)
+	assert.Equal(1, nbReads)
+	truncated, err := Truncate(srs, 10)
+	assert.NoError(err)
+	assert.Equal(truncated, got)
+	_, err = os.Stat(filepath.Join(cache.Dir, "bn254_10.srs"))
+	assert.NoError(err)
+
+	_, err = cache.Load(ecc.BN254, 21)
+	assert.True(errors.Is(err, fs.ErrNotExist))
+	_, err = cache.Load(ecc.BW6_761, 10)
+	assert.True(errors.Is(err, fs.ErrNotExist))
+
+	// a read SRS on another curve or which is invalid is not cached
+	_, err = cache.Get(ecc.BW6_761, 10, read)
+	assert.Error(err)
+	tampered, err := kzg_bn254.NewSRS(30, tau)
+	assert.NoError(err)
+	tampered.G1[25] = tampered.G1[0]
+	_, err = cache.Get(ecc.BN254, 30, func() (kzg.SRS, error) {
+		return tampered, nil
+	})
+	assert.True(errors.Is(err, ErrInvalidSRS))
+	_, err = cache.Load(ecc.BN254, 30)
+	assert.True(errors.Is(err, fs.ErrNotExist))
+}
@@ -0,0 +1,332 @@
+// Copyright 2022 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package srs
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/kzg"
+
+	bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377"
+	fr_bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	kzg_bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377/fr/kzg"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	fr_bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	kzg_bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/fr/kzg"
+	bls24315 "github.com/consensys/gnark-crypto/ecc/bls24-315"
+	fr_bls24315 "github.com/consensys/gnark-crypto/ecc/bls24-315/fr"
+	kzg_bls24315 "github.com/consensys/gnark-crypto/ecc/bls24-315/fr/kzg"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	fr_bn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	kzg_bn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr/kzg"
+	bw6633 "github.com/consensys/gnark-crypto/ecc/bw6-633"
+	fr_bw6633 "github.com/consensys/gnark-crypto/ecc/bw6-633/fr"
+	kzg_bw6633 "github.com/consensys/gnark-crypto/ecc/bw6-633/fr/kzg"
+	bw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761"
+	fr_bw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761/fr"
+	kzg_bw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761/fr/kzg"
+)
+
+// Validate checks that srs holds consecutive powers of a single τ, starting from the standard
+// generators of the curve: [τⁱ]G₁ for i < len(srs.G1), and G₂, [τ]G₂, with τ ∉ {0, 1}.
+//
+// The ratios e([τⁱ⁺¹]G₁, G₂) = e([τⁱ]G₁, [τ]G₂) are checked at once on a random linear combination
+// of the powers: Validate costs two multi-exponentiations and a pairing check. The points are
+// assumed to be in the correct subgroups, which the decoders of this package and kzg.SRS.ReadFrom
+// check.
+func Validate(srs kzg.SRS) error {
+	switch s := srs.(type) {
+	case *kzg_bn254.SRS:
+		return validateBN254(s)
+	case *kzg_bls12381.SRS:
+		return validateBLS12381(s)
+	case *kzg_bls12377.SRS:
+		return validateBLS12377(s)
+	case *kzg_bw6761.SRS:
+		return validateBW6761(s)
+	case *kzg_bls24315.SRS:
+		return validateBLS24315(s)
+	case *kzg_bw6633.SRS:
+		return validateBW6633(s)
+	default:
+		_, _, err := sizeOf(srs)
+		return err
+	}
+}
+
+func validateBN254(srs *kzg_bn254.SRS) error {
+	if len(srs.G1) < 2 {
+		return fmt.Errorf("%w: %d powers of τ in G₁, at least 2 expected", ErrInvalidSRS, len(srs.G1))
+	}
+	_, _, g1, g2 := bn254.Generators()
+	if !srs.G1[0].Equal(&g1) || !srs.G2[0].Equal(&g2) {
+		return fmt.Errorf("%w: the first powers of τ are not the generators", ErrInvalidSRS)
+	}
+	if srs.G1[1].IsInfinity() || srs.G1[1].Equal(&g1) {
+		return fmt.Errorf("%w: τ is 0 or 1", ErrInvalidSRS)
+	}
+
+	// ρ⁰, ρ¹, ... for a random ρ
+	n := len(srs.G1) - 1
+	scalars := make([]fr_bn254.Element, n)
+	scalars[0].SetOne()
+	if n > 1 {
+		if _, err := scalars[1].SetRandom(); err != nil {
+			return err
+		}
+	}
+	for i := 2; i < n; i++ {
+		scalars[i].Mul(&scalars[i-1], &scalars[1])
+	}
+
+	var right, left bn254.G1Affine
+	config := ecc.MultiExpConfig{ScalarsMont: true}
+	if _, err := right.MultiExp(srs.G1[1:], scalars, config); err != nil {
+		return err
+	}
+	if _, err := left.MultiExp(srs.G1[:n], scalars, config); err != nil {
+		return err
+	}
+	left.Neg(&left)
+	ok, err := bn254.PairingCheck([]bn254.G1Affine{right, left}, []bn254.G2Affine{srs.G2[0], srs.G2[1]})
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("%w: the points are not consecutive powers of τ", ErrInvalidSRS)
+	}
+	return nil
+}
+
+func validateBLS12381(srs *kzg_bls12381.SRS) error {
+	if len(srs.G1) < 2 {
+		return fmt.Errorf("%w: %d powers of τ in G₁, at least 2 expected", ErrInvalidSRS, len(srs.G1))
+	}
+	_, _, g1, g2 := bls12381.Generators()
+	if !srs.G1[0].Equal(&g1) || !srs.G2[0].Equal(&g2) {
+		return fmt.Errorf("%w: the first powers of τ are not the generators", ErrInvalidSRS)
+	}
+	if srs.G1[1].IsInfinity() || srs.G1[1].Equal(&g1) {
+		return fmt.Errorf("%w: τ is 0 or 1", ErrInvalidSRS)
+	}
+
+	// ρ⁰, ρ¹, ... for a random ρ
+	n := len(srs.G1) - 1
+	scalars := make([]fr_bls12381.Element, n)
+	scalars[0].SetOne()
+	if n > 1 {
+		if _, err := scalars[1].SetRandom(); err != nil {
+			return err
+		}
+	}
+	for i := 2; i < n; i++ {
+		scalars[i].Mul(&scalars[i-1], &scalars[1])
+	}
+
+	var right, left bls12381.G1Affine
+	config := ecc.MultiExpConfig{ScalarsMont: true}
+	if _, err := right.MultiExp(srs.G1[1:], scalars, config); err != nil {
+		return err
+	}
+	if _, err := left.MultiExp(srs.G1[:n], scalars, config); err != nil {
+		return err
+	}
+	left.Neg(&left)
+	ok, err := bls12381.PairingCheck([]bls12381.G1Affine{right, left}, []bls12381.G2Affine{srs.G2[0], srs.G2[1]})
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("%w: the points are not consecutive powers of τ", ErrInvalidSRS)
+	}
+	return nil
+}
+
+func validateBLS12377(srs *kzg_bls12377.SRS) error {
+	if len(srs.G1) < 2 {
+		return fmt.Errorf("%w: %d powers of τ in G₁, at least 2 expected", ErrInvalidSRS, len(srs.G1))
+	}
+	_, _, g1, g2 := bls12377.Generators()
+	if !srs.G1[0].Equal(&g1) || !srs.G2[0].Equal(&g2) {
+		return fmt.Errorf("%w: the first powers of τ are not the generators", ErrInvalidSRS)
+	}
+	if srs.G1[1].IsInfinity() || srs.G1[1].Equal(&g1) {
+		return fmt.Errorf("%w: τ is 0 or 1", ErrInvalidSRS)
+	}
+
+	// ρ⁰, ρ¹, ... for a random ρ
+	n := len(srs.G1) - 1
+	scalars := make([]fr_bls12377.Element, n)
+	scalars[0].SetOne()
+	if n > 1 {
+		if _, err := scalars[1].SetRandom(); err != nil {
+			return err
+		}
+	}
+	for i := 2; i < n; i++ {
+		scalars[i].Mul(&scalars[i-1], &scalars[1])
+	}
+
+	var right, left bls12377.G1Affine
+	config := ecc.MultiExpConfig{ScalarsMont: true}
+	if _, err := right.MultiExp(srs.G1[1:], scalars, config); err != nil {
+		return err
+	}
+	if _, err := left.MultiExp(srs.G1[:n], scalars, config); err != nil {
+		return err
+	}
+	left.Neg(&left)
+	ok, err := bls12377.PairingCheck([]bls12377.G1Affine{right, left}, []bls12377.G2Affine{srs.G2[0], srs.G2[1]})
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("%w: the points are not consecutive powers of τ", ErrInvalidSRS)
+	}
+	return nil
+}
+
+func validateBW6761(srs *kzg_bw6761.SRS) error {
+	if len(srs.G1) < 2 {
+		return fmt.Errorf("%w: %d powers of τ in G₁, at least 2 expected", ErrInvalidSRS, len(srs.G1))
+	}
+	_, _, g1, g2 := bw6761.Generators()
+	if !srs.G1[0].Equal(&g1) || !srs.G2[0].Equal(&g2) {
+		return fmt.Errorf("%w: the first powers of τ are not the generators", ErrInvalidSRS)
+	}
+	if srs.G1[1].IsInfinity() || srs.G1[1].Equal(&g1) {
+		return fmt.Errorf("%w: τ is 0 or 1", ErrInvalidSRS)
+	}
+
+	// ρ⁰, ρ¹, ... for a random ρ
+	n := len(srs.G1) - 1
+	scalars := make([]fr_bw6761.Element, n)
+	scalars[0].SetOne()
+	if n > 1 {
+		if _, err := scalars[1].SetRandom(); err != nil {
+			return err
+		}
+	}
+	for i := 2; i < n; i++ {
+		scalars[i].Mul(&scalars[i-1], &scalars[1])
+	}
+
+	var right, left bw6761.G1Affine
+	config := ecc.MultiExpConfig{ScalarsMont: true}
+	if _, err := right.MultiExp(srs.G1[1:], scalars, config); err != nil {
+		return err
+	}
+	if _, err := left.MultiExp(srs.G1[:n], scalars, config); err != nil {
+		return err
+	}
+	left.Neg(&left)
+	ok, err := bw6761.PairingCheck([]bw6761.G1Affine{right, left}, []bw6761.G2Affine{srs.G2[0], srs.G2[1]})
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("%w: the points are not consecutive powers of τ", ErrInvalidSRS)
+	}
+	return nil
+}
+
+func validateBLS24315(srs *kzg_bls24315.SRS) error {
+	if len(srs.G1) < 2 {
+		return fmt.Errorf("%w: %d powers of τ in G₁, at least 2 expected", ErrInvalidSRS, len(srs.G1))
+	}
+	_, _, g1, g2 := bls24315.Generators()
+	if !srs.G1[0].Equal(&g1) || !srs.G2[0].Equal(&g2) {
+		return fmt.Errorf("%w: the first powers of τ are not the generators", ErrInvalidSRS)
+	}
+	if srs.G1[1].IsInfinity() || srs.G1[1].Equal(&g1) {
+		return fmt.Errorf("%w: τ is 0 or 1", ErrInvalidSRS)
+	}
+
+	// ρ⁰, ρ¹, ... for a random ρ
+	n := len(srs.G1) - 1
+	scalars := make([]fr_bls24315.Element, n)
+	scalars[0].SetOne()
+	if n > 1 {
+		if _, err := scalars[1].SetRandom(); err != nil {
+			return err
+		}
+	}
+	for i := 2; i < n; i++ {
+		scalars[i].Mul(&scalars[i-1], &scalars[1])
+	}
+
+	var right, left bls24315.G1Affine
+	config := ecc.MultiExpConfig{ScalarsMont: true}
+	if _, err := right.MultiExp(srs.G1[1:], scalars, config); err != nil {
+		return err
+	}
+	if _, err := left.MultiExp(srs.G1[:n], scalars, config); err != nil {
+		return err
+	}
+	left.Neg(&left)
+	ok, err := bls24315.PairingCheck([]bls24315.G1Affine{right, left}, []bls24315.G2Affine{srs.G2[0], srs.G2[1]})
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("%w: the points are not consecutive powers of τ", ErrInvalidSRS)
+	}
+	return nil
+}
+
+func validateBW6633(srs *kzg_bw6633.SRS) error {
+	if len(srs.G1) < 2 {
+		return fmt.Errorf("%w: %d powers of τ in G₁, at least 2 expected", ErrInvalidSRS, len(srs.G1))
+	}
+	_, _, g1, g2 := bw6633.Generators()
+	if !srs.G1[0].Equal(&g1) || !srs.G2[0].Equal(&g2) {
+		return fmt.Errorf("%w: the first powers of τ are not the generators", ErrInvalidSRS)
+	}
+	if srs.G1[1].IsInfinity() || srs.G1[1].Equal(&g1) {
+		return fmt.Errorf("%w: τ is 0 or 1", ErrInvalidSRS)
+	}
+
+	// ρ⁰, ρ¹, ... for a random ρ
+	n := len(srs.G1) - 1
+	scalars := make([]fr_bw6633.Element, n)
+	scalars[0].SetOne()
+	if n > 1 {
+		if _, err := scalars[1].SetRandom(); err != nil {
+			return err
+		}
+	}
+	for i := 2; i < n; i++ {
+		scalars[i].Mul(&scalars[i-1], &scalars[1])
+	}
+
+	var right, left bw6633.G1Affine
+	config := ecc.MultiExpConfig{ScalarsMont: true}
+	if _, err := right.MultiExp(srs.G1[1:], scalars, config); err != nil {
+		return err
+	}
+	if _, err := left.MultiExp(srs.G1[:n], scalars, config); err != nil {
+		return err
+	}
+	left.Neg(&left)
+	ok, err := bw6633.PairingCheck([]bw6633.G1Affine{right, left}, []bw6633.G2Affine{srs.G2[0], srs.G2[1]})
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("%w: the points are not consecutive powers of τ", ErrInvalidSRS)
+	}
+	return nil
+}
@@ -0,0 +1,252 @@
+// Copyright 2022 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cli implements the gnark command-line tool, which runs the compile / setup / prove /
+// verify pipeline on registered circuits.
+//
+// A binary registers its circuits with Register, and runs the tool with Run:
+//
+//	func main() {
+//		cli.Register("cubic", func() frontend.Circuit { return &cubic.Circuit{} })
+//		if err := cli.Run(os.Stdout, os.Args[1:]); err != nil {
+//			fmt.Fprintln(os.Stderr, err)
+//			os.Exit(1)
+//		}
+//	}
+//
+// The constraint systems, keys and proofs are written with a header recording their kind, curve,
+// backend and circuit (see package artifact), so that a file of another curve, backend or circuit is
+// rejected. The KZG SRS is read with its raw binary encoding, as produced by a ceremony. Witnesses are
+// JSON files, decoded with the schema of the compiled circuit.
+//
+// Subcommands
+//
+//	compile          -circuit name -o circuit.ccs
+//	setup            -ccs circuit.ccs [-srs srs.kzg] -pk circuit.pk -vk circuit.vk
+//	prove            -ccs circuit.ccs -pk circuit.pk [-srs srs.kzg] -witness witness.json -o proof
+//	verify           -vk circuit.vk -proof proof -public public.json (-circuit name | -ccs circuit.ccs) [-srs srs.kzg]
+//	export-solidity  -vk circuit.vk [-o verifier.sol]
+//	stats            -ccs circuit.ccs
+//
+// All subcommands take -curve (default bn254) and -backend (default groth16), which must match the
+// ones of the compile step. The plonk keys are serialized without the KZG SRS, hence plonk setup, prove
+// and verify read it from -srs.
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/consensys/gnark"
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/artifact"
+	"github.com/consensys/gnark/frontend"
+)
+
+var (
+	// ErrUnknownCommand is returned by Run for an unknown subcommand
+	ErrUnknownCommand = errors.New("unknown command")
+	// ErrUnknownCircuit is returned when a circuit name is not registered
+	ErrUnknownCircuit = errors.New("unknown circuit")
+	// ErrMissingFlag is returned when a required flag of a subcommand is not set
+	ErrMissingFlag = errors.New("missing flag")
+)
+
+var circuits = make(map[string]func() frontend.Circuit)
+
+// Register makes a circuit available to the compile and verify subcommands under the given name.
+//
+// newCircuit returns a new circuit object, sized as for frontend.Compile (slices allocated).
+// Register panics if the name is already registered.
+func Register(name string, newCircuit func() frontend.Circuit) {
+	if _, ok := circuits[name]; ok {
+		panic("circuit " + name + " already registered")
+	}
+	circuits[name] = newCircuit
+}
+
+// Circuits returns the sorted names of the registered circuits
+func Circuits() []string {
+	r := make([]string, 0, len(circuits))
+	for name := range circuits {
+		r = append(r, name)
+	}
+	sort.Strings(r)
+	return r
+}
+
+type command struct {
+	name, usage string
+	run         func(out io.Writer, args []string) error
+}
+
+var commands []command
+
+func init() {
+	commands = []command{
+		{"compile", "compile a registered circuit to a constraint system file", runCompile},
+		{"setup", "run the groth16 or plonk setup of a constraint system", runSetup},
+		{"prove", "prove a JSON witness", runProve},
+		{"verify", "verify a proof against a JSON public witness", runVerify},
+		{"export-solidity", "export a groth16 verifying key to a solidity verifier", runExportSolidity},
+		{"stats", "print the statistics of a constraint system", runStats},
+	}
+}
+
+// Run runs the subcommand args[0] with the flags args[1:], and writes its report to out
+func Run(out io.Writer, args []string) error {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		usage(out)
+		return nil
+	}
+	for _, c := range commands {
+		if c.name == args[0] {
+			err := c.run(out, args[1:])
+			if err != nil && !errors.Is(err, flag.ErrHelp) {
+				return fmt.Errorf("%s: %w", c.name, err)
+			}
+			return nil
+		}
+	}
+	usage(out)
+	return fmt.Errorf("%w %q", ErrUnknownCommand, args[0])
+}
+
+func usage(out io.Writer) {
+	fmt.Fprintln(out, "usage: gnark <command> [flags]")
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "commands:")
+	for _, c := range commands {
+		fmt.Fprintf(out, "  %-16s %s\n", c.name, c.usage)
+	}
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "circuits:", strings.Join(Circuits(), ", "))
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "run 'gnark <command> -h' for the flags of a command")
+}
+
+// flagSet returns a flag set writing its errors and usage to out
+func flagSet(out io.Writer, name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(out)
+	return fs
+}
+
+// curveFlag and backendFlag implement flag.Value
+type curveFlag struct{ id ecc.ID }
+
+func (f *curveFlag) String() string {
+	if f.id == ecc.UNKNOWN {
+		return ""
+	}
+	return f.id.String()
+}
+
+func (f *curveFlag) Set(s string) error {
+	for _, id := range gnark.Curves() {
+		if strings.EqualFold(id.String(), s) {
+			f.id = id
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown curve %q", s)
+}
+
+type backendFlag struct{ id backend.ID }
+
+func (f *backendFlag) String() string { return f.id.String() }
+
+func (f *backendFlag) Set(s string) error {
+	for _, id := range backend.Implemented() {
+		if strings.EqualFold(id.String(), s) {
+			f.id = id
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown backend %q", s)
+}
+
+// requireFlags returns ErrMissingFlag if one of the named flags is not set
+func requireFlags(fs *flag.FlagSet, names ...string) error {
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	for _, name := range names {
+		if !set[name] {
+			return fmt.Errorf("%w -%s", ErrMissingFlag, name)
+		}
+	}
+	return nil
+}
+
+// readFrom decodes the file at path into o, with its raw binary encoding
+func readFrom(path string, o io.ReaderFrom) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err := o.ReadFrom(bufio.NewReader(f)); err != nil {
+		return fmt.Errorf("reading %s: %w", path, err)
+	}
+	return nil
+}
+
+// readArtifact decodes the artifact at path into o (see package artifact)
+func readArtifact(path string, o interface{}) (artifact.Header, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return artifact.Header{}, err
+	}
+	defer f.Close()
+	h, err := artifact.ReadInto(bufio.NewReader(f), o)
+	if err != nil {
+		return h, fmt.Errorf("reading %s: %w", path, err)
+	}
+	return h, nil
+}
+
+// writeArtifact encodes o with its header into the file at path
+func writeArtifact(path string, o interface{}, circuitHash []byte) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	if _, err := artifact.Write(w, o, circuitHash); err != nil {
+		f.Close()
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// checkCircuit returns an error if the artifact at path was written for another circuit than the
+// one of circuitHash; artifacts without circuit hash are accepted
+func checkCircuit(path string, h artifact.Header, circuitHash [32]byte) error {
+	var zero [32]byte
+	if h.CircuitHash != zero && circuitHash != zero && h.CircuitHash != circuitHash {
+		return fmt.Errorf("%s was written for another circuit", path)
+	}
+	return nil
+}
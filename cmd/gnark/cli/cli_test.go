@@ -0,0 +1,156 @@
+// Copyright 2022 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/artifact"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+	"github.com/stretchr/testify/require"
+)
+
+type cubicCircuit struct {
+	X frontend.Variable `gnark:"x"`
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (circuit *cubicCircuit) Define(api frontend.API) error {
+	x3 := api.Mul(circuit.X, circuit.X, circuit.X)
+	api.AssertIsEqual(circuit.Y, api.Add(x3, circuit.X, 5))
+	return nil
+}
+
+type quadraticCircuit struct {
+	X frontend.Variable `gnark:"x"`
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (circuit *quadraticCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(circuit.Y, api.Add(api.Mul(circuit.X, circuit.X), 26))
+	return nil
+}
+
+func init() {
+	Register("cubic", func() frontend.Circuit { return &cubicCircuit{} })
+	Register("quadratic", func() frontend.Circuit { return &quadraticCircuit{} })
+}
+
+// run runs the tool and returns its report
+func run(assert *require.Assertions, args ...string) string {
+	var out bytes.Buffer
+	assert.NoError(Run(&out, args), out.String())
+	return out.String()
+}
+
+func TestGroth16Pipeline(t *testing.T) {
+	assert := require.New(t)
+	dir := t.TempDir()
+	path := func(name string) string { return filepath.Join(dir, name) }
+
+	assert.NoError(os.WriteFile(path("witness.json"), []byte(`{"x":3,"Y":35}`), 0600))
+	assert.NoError(os.WriteFile(path("public.json"), []byte(`{"Y":35}`), 0600))
+	assert.NoError(os.WriteFile(path("wrong.json"), []byte(`{"Y":36}`), 0600))
+
+	report := run(assert, "compile", "-circuit", "cubic", "-o", path("cubic.ccs"))
+	assert.Contains(report, "3 constraints")
+	report = run(assert, "stats", "-ccs", path("cubic.ccs"))
+	assert.Contains(report, "constraints:  3")
+	assert.Contains(report, "1 secret, 2 public")
+
+	run(assert, "setup", "-ccs", path("cubic.ccs"), "-pk", path("cubic.pk"), "-vk", path("cubic.vk"))
+	run(assert, "prove", "-ccs", path("cubic.ccs"), "-pk", path("cubic.pk"), "-witness", path("witness.json"), "-o", path("proof"))
+
+	// the schema of the public witness is taken from the registered circuit or from the constraint system;
+	// a full witness is accepted too
+	report = run(assert, "verify", "-vk", path("cubic.vk"), "-proof", path("proof"), "-public", path("public.json"), "-circuit", "cubic")
+	assert.Contains(report, "proof verified")
+	run(assert, "verify", "-vk", path("cubic.vk"), "-proof", path("proof"), "-public", path("public.json"), "-ccs", path("cubic.ccs"))
+	run(assert, "verify", "-vk", path("cubic.vk"), "-proof", path("proof"), "-public", path("witness.json"), "-circuit", "cubic")
+
+	var out bytes.Buffer
+	assert.Error(Run(&out, []string{"verify", "-vk", path("cubic.vk"), "-proof", path("proof"), "-public", path("wrong.json"), "-circuit", "cubic"}))
+
+	report = run(assert, "export-solidity", "-vk", path("cubic.vk"))
+	assert.Contains(report, "contract Verifier")
+
+	// the files record their curve, backend and circuit
+	err := Run(&out, []string{"prove", "-curve", "bls12_381", "-ccs", path("cubic.ccs"), "-pk", path("cubic.pk"), "-witness", path("witness.json"), "-o", path("proof")})
+	assert.True(errors.Is(err, artifact.ErrMismatch), err)
+	run(assert, "compile", "-circuit", "quadratic", "-o", path("quadratic.ccs"))
+	run(assert, "setup", "-ccs", path("quadratic.ccs"), "-pk", path("quadratic.pk"), "-vk", path("quadratic.vk"))
+	err = Run(&out, []string{"verify", "-vk", path("quadratic.vk"), "-proof", path("proof"), "-public", path("public.json"), "-circuit", "quadratic"})
+	assert.Error(err)
+	assert.Contains(err.Error(), "written for another circuit")
+}
+
+func TestPlonkPipeline(t *testing.T) {
+	assert := require.New(t)
+	dir := t.TempDir()
+	path := func(name string) string { return filepath.Join(dir, name) }
+
+	assert.NoError(os.WriteFile(path("witness.json"), []byte(`{"x":3,"Y":35}`), 0600))
+	assert.NoError(os.WriteFile(path("public.json"), []byte(`{"Y":35}`), 0600))
+
+	run(assert, "compile", "-circuit", "cubic", "-backend", "plonk", "-curve", "bls12_377", "-o", path("cubic.ccs"))
+
+	// the SRS is produced by a ceremony in production
+	ccs := plonk.NewCS(ecc.BLS12_377)
+	_, err := readArtifact(path("cubic.ccs"), ccs)
+	assert.NoError(err)
+	srs, err := test.NewKZGSRS(ccs)
+	assert.NoError(err)
+	f, err := os.Create(path("srs.kzg"))
+	assert.NoError(err)
+	_, err = srs.WriteTo(f)
+	assert.NoError(err)
+	assert.NoError(f.Close())
+
+	flags := []string{"-backend", "plonk", "-curve", "bls12_377", "-srs", path("srs.kzg")}
+	run(assert, append([]string{"setup", "-ccs", path("cubic.ccs"), "-pk", path("cubic.pk"), "-vk", path("cubic.vk")}, flags...)...)
+	run(assert, append([]string{"prove", "-ccs", path("cubic.ccs"), "-pk", path("cubic.pk"), "-witness", path("witness.json"), "-o", path("proof")}, flags...)...)
+	report := run(assert, append([]string{"verify", "-vk", path("cubic.vk"), "-proof", path("proof"), "-public", path("public.json"), "-circuit", "cubic"}, flags...)...)
+	assert.Contains(report, "proof verified")
+
+	// the SRS is required
+	var out bytes.Buffer
+	err = Run(&out, []string{"setup", "-backend", "plonk", "-curve", "bls12_377", "-ccs", path("cubic.ccs"), "-pk", path("cubic.pk"), "-vk", path("cubic.vk")})
+	assert.True(errors.Is(err, ErrMissingFlag), err)
+}
+
+func TestErrors(t *testing.T) {
+	assert := require.New(t)
+
+	var out bytes.Buffer
+	assert.True(errors.Is(Run(&out, []string{"deploy"}), ErrUnknownCommand))
+	assert.True(strings.HasPrefix(out.String(), "usage: gnark"))
+
+	out.Reset()
+	assert.True(errors.Is(Run(&out, []string{"compile", "-circuit", "quartic", "-o", "x"}), ErrUnknownCircuit))
+	assert.True(errors.Is(Run(&out, []string{"compile", "-circuit", "cubic"}), ErrMissingFlag))
+	assert.Error(Run(&out, []string{"compile", "-curve", "secp256k1", "-circuit", "cubic", "-o", "x"}))
+
+	// help is not an error
+	assert.NoError(Run(&out, []string{"prove", "-h"}))
+	assert.NoError(Run(&out, nil))
+}
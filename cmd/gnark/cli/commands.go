@@ -0,0 +1,396 @@
+// Copyright 2022 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/kzg"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/artifact"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/frontend/schema"
+	"github.com/consensys/gnark/std"
+)
+
+var tVariable = reflect.ValueOf(struct{ A frontend.Variable }{}).FieldByName("A").Type()
+
+// pipelineFlags are the -curve and -backend flags shared by the subcommands
+type pipelineFlags struct {
+	curve   curveFlag
+	backend backendFlag
+}
+
+func addPipelineFlags(fs *flag.FlagSet) *pipelineFlags {
+	p := &pipelineFlags{curveFlag{ecc.BN254}, backendFlag{backend.GROTH16}}
+	fs.Var(&p.curve, "curve", "curve of the constraint system (bn254, bls12_381, bls12_377, bw6_761, bls24_315, bw6_633)")
+	fs.Var(&p.backend, "backend", "proof system (groth16, plonk)")
+	return p
+}
+
+func runCompile(out io.Writer, args []string) error {
+	fs := flagSet(out, "compile")
+	p := addPipelineFlags(fs)
+	circuitName := fs.String("circuit", "", "name of the registered circuit")
+	output := fs.String("o", "", "constraint system output file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := requireFlags(fs, "circuit", "o"); err != nil {
+		return err
+	}
+
+	newCircuit, ok := circuits[*circuitName]
+	if !ok {
+		return fmt.Errorf("%w %q", ErrUnknownCircuit, *circuitName)
+	}
+	newBuilder := r1cs.NewBuilder
+	if p.backend.id == backend.PLONK {
+		newBuilder = scs.NewBuilder
+	}
+	ccs, err := frontend.Compile(p.curve.id, newBuilder, newCircuit())
+	if err != nil {
+		return err
+	}
+	if err := writeArtifact(*output, ccs, nil); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "compiled %s: %d constraints\n", *circuitName, ccs.GetNbConstraints())
+	return nil
+}
+
+func runSetup(out io.Writer, args []string) error {
+	fs := flagSet(out, "setup")
+	p := addPipelineFlags(fs)
+	ccsPath := fs.String("ccs", "", "constraint system file")
+	srsPath := fs.String("srs", "", "KZG SRS file (plonk)")
+	pkPath := fs.String("pk", "", "proving key output file")
+	vkPath := fs.String("vk", "", "verifying key output file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := requireFlags(fs, "ccs", "pk", "vk"); err != nil {
+		return err
+	}
+
+	ccs, err := p.readCS(*ccsPath)
+	if err != nil {
+		return err
+	}
+	circuitHash, err := artifact.CircuitHash(ccs)
+	if err != nil {
+		return err
+	}
+
+	var pk, vk interface{}
+	switch p.backend.id {
+	case backend.GROTH16:
+		pk, vk, err = groth16.Setup(ccs)
+	case backend.PLONK:
+		var srs kzg.SRS
+		if srs, err = p.readSRS(fs, *srsPath); err != nil {
+			return err
+		}
+		pk, vk, err = plonk.Setup(ccs, srs)
+	}
+	if err != nil {
+		return err
+	}
+	if err := writeArtifact(*pkPath, pk, circuitHash[:]); err != nil {
+		return err
+	}
+	return writeArtifact(*vkPath, vk, circuitHash[:])
+}
+
+func runProve(out io.Writer, args []string) error {
+	fs := flagSet(out, "prove")
+	p := addPipelineFlags(fs)
+	ccsPath := fs.String("ccs", "", "constraint system file")
+	pkPath := fs.String("pk", "", "proving key file")
+	srsPath := fs.String("srs", "", "KZG SRS file (plonk)")
+	witnessPath := fs.String("witness", "", "full witness JSON file")
+	output := fs.String("o", "", "proof output file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := requireFlags(fs, "ccs", "pk", "witness", "o"); err != nil {
+		return err
+	}
+
+	ccs, err := p.readCS(*ccsPath)
+	if err != nil {
+		return err
+	}
+	circuitHash, err := artifact.CircuitHash(ccs)
+	if err != nil {
+		return err
+	}
+	fullWitness, err := p.readWitness(*witnessPath, ccs.GetSchema())
+	if err != nil {
+		return err
+	}
+
+	// the constraint system may use the hints of the standard library
+	std.RegisterHints()
+
+	var proof interface{}
+	switch p.backend.id {
+	case backend.GROTH16:
+		pk := groth16.NewProvingKey(p.curve.id)
+		if err := readProvingKey(*pkPath, pk, circuitHash); err != nil {
+			return err
+		}
+		proof, err = groth16.Prove(ccs, pk, fullWitness)
+	case backend.PLONK:
+		pk := plonk.NewProvingKey(p.curve.id)
+		if err := readProvingKey(*pkPath, pk, circuitHash); err != nil {
+			return err
+		}
+		var srs kzg.SRS
+		if srs, err = p.readSRS(fs, *srsPath); err != nil {
+			return err
+		}
+		if err := pk.InitKZG(srs); err != nil {
+			return err
+		}
+		proof, err = plonk.Prove(ccs, pk, fullWitness)
+	}
+	if err != nil {
+		return err
+	}
+	return writeArtifact(*output, proof, circuitHash[:])
+}
+
+func runVerify(out io.Writer, args []string) error {
+	fs := flagSet(out, "verify")
+	p := addPipelineFlags(fs)
+	vkPath := fs.String("vk", "", "verifying key file")
+	proofPath := fs.String("proof", "", "proof file")
+	publicPath := fs.String("public", "", "public witness JSON file")
+	circuitName := fs.String("circuit", "", "name of the registered circuit, for the schema of the public witness")
+	ccsPath := fs.String("ccs", "", "constraint system file, for the schema of the public witness (instead of -circuit)")
+	srsPath := fs.String("srs", "", "KZG SRS file (plonk)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := requireFlags(fs, "vk", "proof", "public"); err != nil {
+		return err
+	}
+
+	// schema of the public witness
+	var s *schema.Schema
+	switch {
+	case *ccsPath != "":
+		ccs, err := p.readCS(*ccsPath)
+		if err != nil {
+			return err
+		}
+		s = ccs.GetSchema()
+	case *circuitName != "":
+		newCircuit, ok := circuits[*circuitName]
+		if !ok {
+			return fmt.Errorf("%w %q", ErrUnknownCircuit, *circuitName)
+		}
+		var err error
+		if s, err = schema.Parse(newCircuit(), tVariable, nil); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("%w -circuit or -ccs", ErrMissingFlag)
+	}
+	publicWitness, err := p.readWitness(*publicPath, s)
+	if err != nil {
+		return err
+	}
+	if publicWitness.Vector.Len() != s.NbPublic {
+		// a full witness was given
+		if publicWitness, err = publicWitness.Public(); err != nil {
+			return err
+		}
+	}
+
+	switch p.backend.id {
+	case backend.GROTH16:
+		vk, proof := groth16.NewVerifyingKey(p.curve.id), groth16.NewProof(p.curve.id)
+		if err := readVerifyingKeyAndProof(*vkPath, vk, *proofPath, proof); err != nil {
+			return err
+		}
+		err = groth16.Verify(proof, vk, publicWitness)
+	case backend.PLONK:
+		vk, proof := plonk.NewVerifyingKey(p.curve.id), plonk.NewProof(p.curve.id)
+		if err := readVerifyingKeyAndProof(*vkPath, vk, *proofPath, proof); err != nil {
+			return err
+		}
+		var srs kzg.SRS
+		if srs, err = p.readSRS(fs, *srsPath); err != nil {
+			return err
+		}
+		if err := vk.InitKZG(srs); err != nil {
+			return err
+		}
+		err = plonk.Verify(proof, vk, publicWitness)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(out, "proof verified")
+	return nil
+}
+
+func runExportSolidity(out io.Writer, args []string) error {
+	fs := flagSet(out, "export-solidity")
+	p := addPipelineFlags(fs)
+	vkPath := fs.String("vk", "", "groth16 verifying key file")
+	output := fs.String("o", "", "solidity output file (default: standard output)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := requireFlags(fs, "vk"); err != nil {
+		return err
+	}
+	if p.backend.id != backend.GROTH16 {
+		return errors.New("solidity export is only implemented for groth16")
+	}
+
+	vk := groth16.NewVerifyingKey(p.curve.id)
+	if _, err := readArtifact(*vkPath, vk); err != nil {
+		return err
+	}
+	if *output == "" {
+		return vk.ExportSolidity(out)
+	}
+
+	f, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	if err := vk.ExportSolidity(w); err != nil {
+		f.Close()
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func runStats(out io.Writer, args []string) error {
+	fs := flagSet(out, "stats")
+	p := addPipelineFlags(fs)
+	ccsPath := fs.String("ccs", "", "constraint system file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := requireFlags(fs, "ccs"); err != nil {
+		return err
+	}
+
+	ccs, err := p.readCS(*ccsPath)
+	if err != nil {
+		return err
+	}
+
+	internal, secret, public := ccs.GetNbVariables()
+	fmt.Fprintf(out, "curve:        %s\n", ccs.CurveID())
+	fmt.Fprintf(out, "backend:      %s\n", p.backend.id)
+	fmt.Fprintf(out, "constraints:  %d\n", ccs.GetNbConstraints())
+	fmt.Fprintf(out, "coefficients: %d\n", ccs.GetNbCoefficients())
+	fmt.Fprintf(out, "variables:    %d internal, %d secret, %d public\n", internal, secret, public)
+	for _, c := range ccs.GetCounters() {
+		fmt.Fprintln(out, c)
+	}
+	return nil
+}
+
+// readCS reads the constraint system of the pipeline curve and backend at path
+func (p *pipelineFlags) readCS(path string) (frontend.CompiledConstraintSystem, error) {
+	var ccs frontend.CompiledConstraintSystem
+	switch p.backend.id {
+	case backend.GROTH16:
+		ccs = groth16.NewCS(p.curve.id)
+	case backend.PLONK:
+		ccs = plonk.NewCS(p.curve.id)
+	}
+	if _, err := readArtifact(path, ccs); err != nil {
+		return nil, err
+	}
+	return ccs, nil
+}
+
+// readProvingKey reads the proving key at path, which must belong to the circuit of circuitHash
+func readProvingKey(path string, pk interface{}, circuitHash [32]byte) error {
+	h, err := readArtifact(path, pk)
+	if err != nil {
+		return err
+	}
+	return checkCircuit(path, h, circuitHash)
+}
+
+// readVerifyingKeyAndProof reads the verifying key and the proof, which must belong to the same circuit
+func readVerifyingKeyAndProof(vkPath string, vk interface{}, proofPath string, proof interface{}) error {
+	h, err := readArtifact(vkPath, vk)
+	if err != nil {
+		return err
+	}
+	hProof, err := readArtifact(proofPath, proof)
+	if err != nil {
+		return err
+	}
+	return checkCircuit(proofPath, hProof, h.CircuitHash)
+}
+
+// readWitness reads the JSON witness at path, full or public, with the schema s
+func (p *pipelineFlags) readWitness(path string, s *schema.Schema) (*witness.Witness, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	w, err := witness.New(p.curve.id, s)
+	if err != nil {
+		return nil, err
+	}
+	if err := w.UnmarshalJSON(data); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	return w, nil
+}
+
+// readSRS reads the KZG SRS of the pipeline curve at path, which must be set with the -srs flag
+func (p *pipelineFlags) readSRS(fs *flag.FlagSet, path string) (kzg.SRS, error) {
+	if err := requireFlags(fs, "srs"); err != nil {
+		return nil, err
+	}
+	srs := kzg.NewSRS(p.curve.id)
+	if err := readFrom(path, srs); err != nil {
+		return nil, err
+	}
+	return srs, nil
+}
@@ -0,0 +1,41 @@
+// Copyright 2022 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command gnark runs the compile / setup / prove / verify pipeline on the example circuits.
+//
+// To use it with other circuits, build a binary registering them with cli.Register
+// (see package github.com/consensys/gnark/cmd/gnark/cli).
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/consensys/gnark/cmd/gnark/cli"
+	"github.com/consensys/gnark/examples/cubic"
+	"github.com/consensys/gnark/examples/exponentiate"
+	"github.com/consensys/gnark/examples/mimc"
+	"github.com/consensys/gnark/frontend"
+)
+
+func main() {
+	cli.Register("cubic", func() frontend.Circuit { return &cubic.Circuit{} })
+	cli.Register("exponentiate", func() frontend.Circuit { return &exponentiate.Circuit{} })
+	cli.Register("mimc", func() frontend.Circuit { return &mimc.Circuit{} })
+
+	if err := cli.Run(os.Stdout, os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "gnark:", err)
+		os.Exit(1)
+	}
+}
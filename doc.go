@@ -18,6 +18,9 @@ package gnark
 
 import "github.com/consensys/gnark-crypto/ecc"
 
+// Version of gnark, recorded in the headers of the serialized objects (see backend/artifact)
+const Version = "0.7.0"
+
 // Curves return the curves supported by gnark
 func Curves() []ecc.ID {
 	return []ecc.ID{ecc.BN254, ecc.BLS12_377, ecc.BLS12_381, ecc.BW6_761, ecc.BLS24_315, ecc.BW6_633}
@@ -0,0 +1,378 @@
+// Copyright 2022 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package acir
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/test"
+	"github.com/stretchr/testify/require"
+)
+
+// testProgram is the ACIR of
+//
+//	fn main(x: u8, y: pub Field, z: u8) -> pub u8 {
+//	    assert(x != 0);
+//	    assert(x * x + 3 == y);
+//	    assert(x % 3 < 4);
+//	    let _ = x.to_le_radix(16, 2);
+//	    x ^ z
+//	}
+//
+// with witnesses x = 1, y = 2, 1/x = 3, x*x + 3 = 4, the radix 16 digits of x = 5 and 6, x / 3 = 7,
+// x % 3 = 8, z = 10 and the return value = 11. "-1" is replaced by the hex encoding of p - 1.
+const testProgram = `{
+	"current_witness_index": 11,
+	"opcodes": [
+		{"Directive": {"Invert": {"x": 1, "result": 3}}},
+		{"Arithmetic": {"mul_terms": [["01", 1, 3]], "linear_combinations": [], "q_c": "-1"}},
+		{"Arithmetic": {"mul_terms": [["0x01", 1, 1]], "linear_combinations": [["-1", 4]], "q_c": "03"}},
+		{"Arithmetic": {"mul_terms": [], "linear_combinations": [["01", 4], ["-1", 2]], "q_c": "00"}},
+		{"BlackBoxFuncCall": {"name": "RANGE", "inputs": [{"witness": 1, "num_bits": 8}], "outputs": []}},
+		{"Directive": {"Quotient": {
+			"a": {"mul_terms": [], "linear_combinations": [["01", 1]], "q_c": "00"},
+			"b": {"mul_terms": [], "linear_combinations": [], "q_c": "03"},
+			"q": 7, "r": 8, "predicate": null}}},
+		{"Arithmetic": {"mul_terms": [], "linear_combinations": [["03", 7], ["01", 8], ["-1", 1]], "q_c": "00"}},
+		{"BlackBoxFuncCall": {"name": "RANGE", "inputs": [{"witness": 8, "num_bits": 2}], "outputs": []}},
+		{"Directive": {"ToRadix": {"a": {"mul_terms": [], "linear_combinations": [["01", 1]], "q_c": "00"}, "b": [5, 6], "radix": 16}}},
+		{"Arithmetic": {"mul_terms": [], "linear_combinations": [["10", 6], ["01", 5], ["-1", 1]], "q_c": "00"}},
+		{"BlackBoxFuncCall": {"name": "XOR", "inputs": [{"witness": 1, "num_bits": 8}, {"witness": 10, "num_bits": 8}], "outputs": [11]}}
+	],
+	"public_parameters": [2],
+	"return_values": [11]
+}`
+
+func readTestProgram(t *testing.T, program string) *Program {
+	minusOne := new(big.Int).Sub(ecc.BN254.Info().Fr.Modulus(), big.NewInt(1))
+	program = strings.ReplaceAll(program, `"-1"`, `"`+minusOne.Text(16)+`"`)
+	p, err := ReadProgram(strings.NewReader(program))
+	require.NoError(t, err)
+	return p
+}
+
+func TestReadProgram(t *testing.T) {
+	assert := require.New(t)
+	p := readTestProgram(t, testProgram)
+
+	assert.Equal(uint32(11), p.CurrentWitnessIndex)
+	assert.Len(p.Opcodes, 11)
+	assert.Equal([]Witness{2, 11}, p.PublicInputs())
+	assert.Equal("1", p.Opcodes[2].Arithmetic.MulTerms[0].Coeff.String())
+	assert.Equal("3", p.Opcodes[2].Arithmetic.Constant.String())
+	assert.Equal(uint32(16), p.Opcodes[8].Directive.ToRadix.Radix)
+
+	c := NewCircuit(p)
+	assert.Equal([]Witness{2, 11}, c.PublicWitnesses())
+	assert.Equal([]Witness{1, 4, 10}, c.SecretWitnesses())
+}
+
+func TestReadWitness(t *testing.T) {
+	assert := require.New(t)
+	w, err := ReadWitness(strings.NewReader(`{"1": "0x07", "2": "34", "10": 5}`))
+	assert.NoError(err)
+	assert.Equal("7", w[1].String())
+	assert.Equal("52", w[2].String())
+	assert.Equal("5", w[10].String())
+
+	_, err = ReadWitness(strings.NewReader(`{"a": "07"}`))
+	assert.Error(err)
+}
+
+func TestProve(t *testing.T) {
+	assert := require.New(t)
+	p := readTestProgram(t, testProgram)
+
+	// 4 and 11 are solved from the program
+	assignment, err := NewAssignment(ecc.BN254, p, WitnessMap{1: big.NewInt(7), 2: big.NewInt(52), 10: big.NewInt(5)})
+	assert.NoError(err)
+	assert.Equal(big.NewInt(2), assignment.Public[1], "return value should be 7 ^ 5")
+
+	fullWitness, err := frontend.NewWitness(assignment, ecc.BN254)
+	assert.NoError(err)
+	publicWitness, err := fullWitness.Public()
+	assert.NoError(err)
+
+	// groth16
+	ccs, err := frontend.Compile(ecc.BN254, r1cs.NewBuilder, NewCircuit(p))
+	assert.NoError(err)
+	pk, vk, err := groth16.Setup(ccs)
+	assert.NoError(err)
+	proof, err := groth16.Prove(ccs, pk, fullWitness)
+	assert.NoError(err)
+	assert.NoError(groth16.Verify(proof, vk, publicWitness))
+
+	// plonk
+	ccs, err = frontend.Compile(ecc.BN254, scs.NewBuilder, NewCircuit(p))
+	assert.NoError(err)
+	srs, err := test.NewKZGSRS(ccs)
+	assert.NoError(err)
+	ppk, pvk, err := plonk.Setup(ccs, srs)
+	assert.NoError(err)
+	pproof, err := plonk.Prove(ccs, ppk, fullWitness)
+	assert.NoError(err)
+	assert.NoError(plonk.Verify(pproof, pvk, publicWitness))
+}
+
+func TestWrongWitness(t *testing.T) {
+	assert := require.New(t)
+	p := readTestProgram(t, testProgram)
+	ccs, err := frontend.Compile(ecc.BN254, r1cs.NewBuilder, NewCircuit(p))
+	assert.NoError(err)
+
+	for _, w := range []WitnessMap{
+		{1: big.NewInt(7), 2: big.NewInt(51), 10: big.NewInt(5)},                    // y != x*x + 3
+		{1: big.NewInt(7), 2: big.NewInt(52), 10: big.NewInt(5), 11: big.NewInt(3)}, // wrong return value
+		{1: big.NewInt(0), 2: big.NewInt(3), 10: big.NewInt(5)},                     // x == 0
+	} {
+		assignment, err := NewAssignment(ecc.BN254, p, w)
+		assert.NoError(err)
+		fullWitness, err := frontend.NewWitness(assignment, ecc.BN254)
+		assert.NoError(err)
+		assert.Error(ccs.IsSolved(fullWitness))
+	}
+
+	_, err = NewAssignment(ecc.BN254, p, WitnessMap{2: big.NewInt(52)})
+	assert.Error(err, "x is not assigned")
+}
+
+func TestLegacyGates(t *testing.T) {
+	assert := require.New(t)
+	p, err := ReadProgram(strings.NewReader(`{
+		"current_witness_index": 3,
+		"gates": [
+			{"Range": [1, 4]},
+			{"And": {"a": 1, "b": 2, "result": 3, "num_bits": 4}}
+		],
+		"public_parameters": [[3]],
+		"return_values": []
+	}`))
+	assert.NoError(err)
+	assert.Equal("RANGE", p.Opcodes[0].BlackBoxFuncCall.Name)
+	assert.Equal("AND", p.Opcodes[1].BlackBoxFuncCall.Name)
+	assert.Equal([]Witness{3}, p.PublicInputs())
+
+	ccs, err := frontend.Compile(ecc.BN254, r1cs.NewBuilder, NewCircuit(p))
+	assert.NoError(err)
+	assignment, err := NewAssignment(ecc.BN254, p, WitnessMap{1: big.NewInt(12), 2: big.NewInt(10)})
+	assert.NoError(err)
+	fullWitness, err := frontend.NewWitness(assignment, ecc.BN254)
+	assert.NoError(err)
+	assert.NoError(ccs.IsSolved(fullWitness))
+}
+
+func TestUnsupported(t *testing.T) {
+	p, err := ReadProgram(strings.NewReader(`{
+		"current_witness_index": 2,
+		"opcodes": [
+			{"BlackBoxFuncCall": {"name": "SHA256", "inputs": [{"witness": 1, "num_bits": 8}], "outputs": [2]}}
+		],
+		"public_parameters": [2],
+		"return_values": []
+	}`))
+	require.NoError(t, err)
+	_, err = frontend.Compile(ecc.BN254, r1cs.NewBuilder, NewCircuit(p))
+	require.True(t, errors.Is(err, ErrUnsupported), err)
+}
+
+// bincodeWriter encodes the acir types as bincode::serialize, see bincodeReader. This isn't output
+// of nargo: it follows the layout documented in the package documentation.
+type bincodeWriter struct {
+	bytes.Buffer
+}
+
+func (b *bincodeWriter) u32(v uint32) {
+	var buf [4]byte
+	binary.LittleEndian.PutUint32(buf[:], v)
+	b.Write(buf[:])
+}
+
+func (b *bincodeWriter) length(n int) {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], uint64(n))
+	b.Write(buf[:])
+}
+
+func (b *bincodeWriter) fieldElement(v *big.Int) {
+	s := fmt.Sprintf("%064x", v)
+	b.length(len(s))
+	b.WriteString(s)
+}
+
+func (b *bincodeWriter) witnesses(ws []Witness) {
+	b.length(len(ws))
+	for _, w := range ws {
+		b.u32(uint32(w))
+	}
+}
+
+func (b *bincodeWriter) expression(e *Expression) {
+	b.length(len(e.MulTerms))
+	for i := range e.MulTerms {
+		b.fieldElement(&e.MulTerms[i].Coeff)
+		b.u32(uint32(e.MulTerms[i].L))
+		b.u32(uint32(e.MulTerms[i].R))
+	}
+	b.length(len(e.Linear))
+	for i := range e.Linear {
+		b.fieldElement(&e.Linear[i].Coeff)
+		b.u32(uint32(e.Linear[i].W))
+	}
+	b.fieldElement(&e.Constant)
+}
+
+func (b *bincodeWriter) program(p *Program) {
+	b.u32(p.CurrentWitnessIndex)
+	b.length(len(p.Opcodes))
+	for _, o := range p.Opcodes {
+		switch {
+		case o.Arithmetic != nil:
+			b.u32(0)
+			b.expression(o.Arithmetic)
+		case o.BlackBoxFuncCall != nil:
+			b.u32(1)
+			c := o.BlackBoxFuncCall
+			switch c.Name {
+			case "AND", "XOR":
+				b.u32(map[string]uint32{"AND": 0, "XOR": 1}[c.Name])
+				for _, in := range c.Inputs {
+					b.u32(uint32(in.Witness))
+					b.u32(in.NumBits)
+				}
+				b.u32(uint32(c.Outputs[0]))
+			case "RANGE":
+				b.u32(2)
+				b.u32(uint32(c.Inputs[0].Witness))
+				b.u32(c.Inputs[0].NumBits)
+			}
+		case o.Directive != nil:
+			b.u32(2)
+			d := o.Directive
+			switch {
+			case d.Invert != nil:
+				b.u32(0)
+				b.u32(uint32(d.Invert.X))
+				b.u32(uint32(d.Invert.Result))
+			case d.Quotient != nil:
+				b.u32(1)
+				b.expression(&d.Quotient.A)
+				b.expression(&d.Quotient.B)
+				b.u32(uint32(d.Quotient.Q))
+				b.u32(uint32(d.Quotient.R))
+				if d.Quotient.Predicate == nil {
+					b.WriteByte(0)
+				} else {
+					b.WriteByte(1)
+					b.expression(d.Quotient.Predicate)
+				}
+			case d.ToRadix != nil:
+				b.u32(2)
+				b.expression(&d.ToRadix.A)
+				b.witnesses(d.ToRadix.Digits)
+				b.u32(d.ToRadix.Radix)
+			}
+		}
+	}
+	b.witnesses(p.PublicParameters)
+	b.witnesses(p.ReturnValues)
+}
+
+func gzipBytes(t *testing.T, data []byte) []byte {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, err := zw.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func TestNargoBinaryEncoding(t *testing.T) {
+	assert := require.New(t)
+	expected := readTestProgram(t, testProgram)
+
+	var b bincodeWriter
+	b.program(expected)
+	encoded := gzipBytes(t, b.Bytes())
+	p, err := ReadProgram(bytes.NewReader(encoded))
+	assert.NoError(err)
+	var reencoded bincodeWriter
+	reencoded.program(p)
+	assert.Equal(b.Bytes(), reencoded.Bytes())
+	assert.Equal(uint32(16), p.Opcodes[8].Directive.ToRadix.Radix)
+	assert.Equal([]Witness{2, 11}, p.PublicInputs())
+
+	// truncated and trailing data
+	_, err = ReadProgram(bytes.NewReader(gzipBytes(t, b.Bytes()[:b.Len()-1])))
+	assert.Error(err)
+	_, err = ReadProgram(bytes.NewReader(gzipBytes(t, append(b.Bytes(), 0))))
+	assert.Error(err)
+
+	// witness map of x = 7, y = 52, z = 5
+	b.Reset()
+	b.length(3)
+	for _, e := range []struct{ w, v uint32 }{{1, 7}, {2, 52}, {10, 5}} {
+		b.u32(e.w)
+		b.fieldElement(new(big.Int).SetUint64(uint64(e.v)))
+	}
+	w, err := ReadWitness(bytes.NewReader(gzipBytes(t, b.Bytes())))
+	assert.NoError(err)
+	assert.Equal(WitnessMap{1: big.NewInt(7), 2: big.NewInt(52), 10: big.NewInt(5)}, w)
+
+	ccs, err := frontend.Compile(ecc.BN254, r1cs.NewBuilder, NewCircuit(p))
+	assert.NoError(err)
+	assignment, err := NewAssignment(ecc.BN254, p, w)
+	assert.NoError(err)
+	fullWitness, err := frontend.NewWitness(assignment, ecc.BN254)
+	assert.NoError(err)
+	assert.NoError(ccs.IsSolved(fullWitness))
+
+	// an opcode whose layout is unknown
+	b.Reset()
+	b.u32(1)
+	b.length(1)
+	b.u32(1) // BlackBoxFuncCall
+	b.u32(3) // SHA256
+	_, err = ReadProgram(bytes.NewReader(gzipBytes(t, b.Bytes())))
+	assert.True(errors.Is(err, ErrUnsupported), err)
+}
+
+func TestBlackBoxFuncCallEnum(t *testing.T) {
+	assert := require.New(t)
+	p, err := ReadProgram(strings.NewReader(`{
+		"current_witness_index": 3,
+		"opcodes": [
+			{"BlackBoxFuncCall": {"RANGE": {"input": {"witness": 1, "num_bits": 4}}}},
+			{"BlackBoxFuncCall": {"AND": {"lhs": {"witness": 1, "num_bits": 4}, "rhs": {"witness": 2, "num_bits": 4}, "output": 3}}}
+		],
+		"public_parameters": [3],
+		"return_values": []
+	}`))
+	assert.NoError(err)
+	assert.Equal(&BlackBoxFuncCall{Name: "RANGE", Inputs: []FunctionInput{{1, 4}}}, p.Opcodes[0].BlackBoxFuncCall)
+	assert.Equal(&BlackBoxFuncCall{Name: "AND", Inputs: []FunctionInput{{1, 4}, {2, 4}}, Outputs: []Witness{3}}, p.Opcodes[1].BlackBoxFuncCall)
+}
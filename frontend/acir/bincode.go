@@ -0,0 +1,243 @@
+// Copyright 2022 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package acir
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+)
+
+// maxFieldElementLength bounds the length of the hex strings of the field elements
+const maxFieldElementLength = 128
+
+// variants of the enums of the acir crate, in declaration order: bincode encodes a variant as its
+// index. Only the variants listed up to the supported ones matter, the other ones are rejected.
+var (
+	opcodeVariants           = []string{"Arithmetic", "BlackBoxFuncCall", "Directive", "Block", "ROM", "RAM", "Oracle", "Brillig"}
+	blackBoxFuncCallVariants = []string{"AND", "XOR", "RANGE", "SHA256", "Blake2s", "SchnorrVerify", "Pedersen", "HashToField128Security", "EcdsaSecp256k1", "FixedBaseScalarMul", "Keccak256"}
+	directiveVariants        = []string{"Invert", "Quotient", "ToLeRadix", "PermutationSort", "Log"}
+)
+
+// bincodeReader decodes the bincode encoding (bincode 1 with its default options, as
+// bincode::serialize) of the types of the acir crate: integers are little-endian, lengths are
+// u64, enum variants are u32 indices, options are a u8 tag, and field elements are their hex
+// strings. The first error is sticky: the following reads return zero values.
+type bincodeReader struct {
+	r   io.Reader
+	err error
+}
+
+func (b *bincodeReader) fail(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
+
+func (b *bincodeReader) read(n int) []byte {
+	buf := make([]byte, n)
+	if b.err != nil {
+		return buf
+	}
+	if _, err := io.ReadFull(b.r, buf); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		b.fail(err)
+	}
+	return buf
+}
+
+func (b *bincodeReader) u8() uint8 {
+	return b.read(1)[0]
+}
+
+func (b *bincodeReader) u32() uint32 {
+	return binary.LittleEndian.Uint32(b.read(4))
+}
+
+// length returns the length of a sequence, a map or a string
+func (b *bincodeReader) length() int {
+	n := binary.LittleEndian.Uint64(b.read(8))
+	if n > 1<<32 {
+		b.fail(fmt.Errorf("invalid length %d", n))
+		return 0
+	}
+	return int(n)
+}
+
+// variant returns the name of the variant of an enum
+func (b *bincodeReader) variant(names []string) string {
+	i := b.u32()
+	if b.err != nil {
+		return ""
+	}
+	if int(i) >= len(names) {
+		b.fail(fmt.Errorf("invalid enum variant %d", i))
+		return ""
+	}
+	return names[i]
+}
+
+// end checks that the whole input was decoded, which catches most layout mismatches
+func (b *bincodeReader) end() {
+	if b.err != nil {
+		return
+	}
+	if n, _ := b.r.Read(make([]byte, 1)); n != 0 {
+		b.fail(errors.New("trailing data after the bincode encoding"))
+	}
+}
+
+func (b *bincodeReader) fieldElement(v *big.Int) {
+	n := b.length()
+	if n > maxFieldElementLength {
+		b.fail(fmt.Errorf("invalid field element length %d", n))
+	}
+	s := b.read(n)
+	if b.err == nil {
+		b.fail(parseFieldElement(string(s), v))
+	}
+}
+
+func (b *bincodeReader) witness() Witness {
+	return Witness(b.u32())
+}
+
+// witnesses decodes a Vec or a BTreeSet of witnesses
+func (b *bincodeReader) witnesses() []Witness {
+	var res []Witness
+	for i, n := 0, b.length(); i < n && b.err == nil; i++ {
+		res = append(res, b.witness())
+	}
+	return res
+}
+
+func (b *bincodeReader) program() *Program {
+	var p Program
+	p.CurrentWitnessIndex = b.u32()
+	for i, n := 0, b.length(); i < n && b.err == nil; i++ {
+		o := b.opcode()
+		if b.err != nil {
+			b.err = fmt.Errorf("opcode %d: %w", i, b.err)
+		}
+		p.Opcodes = append(p.Opcodes, o)
+	}
+	p.PublicParameters = b.witnesses()
+	p.ReturnValues = b.witnesses()
+	return &p
+}
+
+func (b *bincodeReader) opcode() Opcode {
+	name := b.variant(opcodeVariants)
+	o := Opcode{Name: name}
+	switch name {
+	case "":
+	case "Arithmetic":
+		o.Arithmetic = new(Expression)
+		b.expression(o.Arithmetic)
+	case "BlackBoxFuncCall":
+		o.BlackBoxFuncCall = b.blackBoxFuncCall()
+	case "Directive":
+		o.Directive = b.directive()
+	default:
+		// the layout of the opcode is unknown, so the following ones can't be decoded
+		b.fail(fmt.Errorf("%w: opcode %s", ErrUnsupported, name))
+	}
+	return o
+}
+
+func (b *bincodeReader) expression(e *Expression) {
+	for i, n := 0, b.length(); i < n && b.err == nil; i++ {
+		var t MulTerm
+		b.fieldElement(&t.Coeff)
+		t.L, t.R = b.witness(), b.witness()
+		e.MulTerms = append(e.MulTerms, t)
+	}
+	for i, n := 0, b.length(); i < n && b.err == nil; i++ {
+		var t LinearTerm
+		b.fieldElement(&t.Coeff)
+		t.W = b.witness()
+		e.Linear = append(e.Linear, t)
+	}
+	b.fieldElement(&e.Constant)
+}
+
+func (b *bincodeReader) functionInput() FunctionInput {
+	return FunctionInput{Witness: b.witness(), NumBits: b.u32()}
+}
+
+// blackBoxFuncCall decodes a BlackBoxFuncCall enum: AND { lhs, rhs, output }, XOR { lhs, rhs,
+// output }, RANGE { input }, ...
+func (b *bincodeReader) blackBoxFuncCall() *BlackBoxFuncCall {
+	name := b.variant(blackBoxFuncCallVariants)
+	c := &BlackBoxFuncCall{Name: name}
+	switch name {
+	case "":
+	case "AND", "XOR":
+		c.Inputs = []FunctionInput{b.functionInput(), b.functionInput()}
+		c.Outputs = []Witness{b.witness()}
+	case "RANGE":
+		c.Inputs = []FunctionInput{b.functionInput()}
+	default:
+		b.fail(fmt.Errorf("%w: black box function %s", ErrUnsupported, name))
+	}
+	return c
+}
+
+// directive decodes a Directive enum: Invert { x, result }, Quotient { a, b, q, r, predicate },
+// ToLeRadix { a, b, radix }, ...
+func (b *bincodeReader) directive() *Directive {
+	name := b.variant(directiveVariants)
+	d := &Directive{Name: name}
+	switch name {
+	case "":
+	case "Invert":
+		d.Invert = &InvertDirective{X: b.witness(), Result: b.witness()}
+	case "Quotient":
+		q := new(QuotientDirective)
+		b.expression(&q.A)
+		b.expression(&q.B)
+		q.Q, q.R = b.witness(), b.witness()
+		if b.u8() == 1 {
+			q.Predicate = new(Expression)
+			b.expression(q.Predicate)
+		}
+		d.Quotient = q
+	case "ToLeRadix":
+		r := new(ToRadixDirective)
+		b.expression(&r.A)
+		r.Digits = b.witnesses()
+		r.Radix = b.u32()
+		d.Name, d.ToRadix = "ToRadix", r
+	default:
+		b.fail(fmt.Errorf("%w: directive %s", ErrUnsupported, name))
+	}
+	return d
+}
+
+// witnessMap decodes a WitnessMap, a BTreeMap from witnesses to field elements
+func (b *bincodeReader) witnessMap() WitnessMap {
+	n := b.length()
+	res := make(WitnessMap)
+	for i := 0; i < n && b.err == nil; i++ {
+		w := b.witness()
+		res[w] = new(big.Int)
+		b.fieldElement(res[w])
+	}
+	return res
+}
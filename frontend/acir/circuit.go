@@ -0,0 +1,357 @@
+// Copyright 2022 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package acir translates Noir ACIR programs into gnark circuits.
+//
+// An ACIR program is replayed through the frontend.API of a builder: arithmetic opcodes become
+// assertions, directives become hints, and the RANGE, AND and XOR black box functions are
+// implemented with api.ToBinary. Other black box functions (SHA256, Pedersen, ...) have no gnark
+// counterpart with matching outputs and are rejected with ErrUnsupported.
+//
+// The public inputs of the circuit are the public parameters and return values of the program,
+// sorted by witness index. The secret inputs are the other witnesses used by the program, except
+// the outputs of directives and black box functions, which are computed by the circuit.
+//
+//	p, _ := acir.ReadProgram(r)
+//	ccs, _ := frontend.Compile(ecc.BN254, r1cs.NewBuilder, acir.NewCircuit(p))
+//	assignment, _ := acir.NewAssignment(ecc.BN254, p, witnessMap)
+//	witness, _ := frontend.NewWitness(assignment, ecc.BN254)
+//
+// Noir programs are defined over the scalar field of BN254 by default.
+//
+// ReadProgram and ReadWitness read the serde_json encoding of the types of the acir crate, and the
+// gzip-compressed bincode encoding in which nargo stores compiled programs and solved witnesses
+// (the base64-decoded "bytecode" of the build artifact, and the witness file). Bincode isn't
+// self-describing, so the latter is decoded with the layout of the acir versions in which
+// BlackBoxFuncCall is an enum:
+//
+//	Circuit { current_witness_index: u32, opcodes: Vec<Opcode>, public_parameters, return_values: BTreeSet<Witness> }
+//	Opcode { Arithmetic(Expression), BlackBoxFuncCall(BlackBoxFuncCall), Directive(Directive), ... }
+//	Expression { mul_terms: Vec<(FieldElement, Witness, Witness)>, linear_combinations: Vec<(FieldElement, Witness)>, q_c: FieldElement }
+//	BlackBoxFuncCall { AND { lhs, rhs: FunctionInput, output: Witness }, XOR { ... }, RANGE { input: FunctionInput }, ... }
+//	Directive { Invert { x, result: Witness }, Quotient(QuotientDirective), ToLeRadix { a: Expression, b: Vec<Witness>, radix: u32 }, ... }
+//	WitnessMap(BTreeMap<Witness, FieldElement>)
+//
+// where field elements are hex strings. As the layout of the variants that can't be translated
+// isn't known, decoding stops at the first of them with ErrUnsupported; programs of other acir
+// versions fail to decode. They can still be imported by serializing them with serde_json.
+package acir
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/consensys/gnark/backend/hint"
+	"github.com/consensys/gnark/frontend"
+)
+
+// ErrUnsupported is returned for opcodes, directives and black box functions that can't be translated
+var ErrUnsupported = errors.New("unsupported ACIR")
+
+// Circuit is a gnark circuit replaying an ACIR program, see NewCircuit
+type Circuit struct {
+	Public []frontend.Variable `gnark:",public"`
+	Secret []frontend.Variable
+
+	layout *layout `gnark:"-"`
+}
+
+// layout maps the inputs of a Circuit to the witnesses of its program
+type layout struct {
+	program           *Program
+	public, secret    []Witness
+	computedWitnesses map[Witness]struct{}
+}
+
+// NewCircuit returns a circuit replaying p, to be compiled with frontend.Compile
+func NewCircuit(p *Program) *Circuit {
+	l := &layout{
+		program:           p,
+		public:            p.PublicInputs(),
+		computedWitnesses: make(map[Witness]struct{}),
+	}
+
+	used := make(map[Witness]struct{})
+	use := func(ws ...Witness) {
+		for _, w := range ws {
+			used[w] = struct{}{}
+		}
+	}
+	useExpression := func(e *Expression) {
+		if e == nil {
+			return
+		}
+		for _, t := range e.MulTerms {
+			use(t.L, t.R)
+		}
+		for _, t := range e.Linear {
+			use(t.W)
+		}
+	}
+	compute := func(ws ...Witness) {
+		for _, w := range ws {
+			l.computedWitnesses[w] = struct{}{}
+		}
+	}
+	for _, o := range p.Opcodes {
+		switch {
+		case o.Arithmetic != nil:
+			useExpression(o.Arithmetic)
+		case o.BlackBoxFuncCall != nil:
+			for _, in := range o.BlackBoxFuncCall.Inputs {
+				use(in.Witness)
+			}
+			compute(o.BlackBoxFuncCall.Outputs...)
+		case o.Directive != nil:
+			d := o.Directive
+			switch {
+			case d.Invert != nil:
+				use(d.Invert.X)
+				compute(d.Invert.Result)
+			case d.Quotient != nil:
+				useExpression(&d.Quotient.A)
+				useExpression(&d.Quotient.B)
+				useExpression(d.Quotient.Predicate)
+				compute(d.Quotient.Q, d.Quotient.R)
+			case d.ToRadix != nil:
+				useExpression(&d.ToRadix.A)
+				compute(d.ToRadix.Digits...)
+			}
+		}
+	}
+
+	isPublic := make(map[Witness]struct{}, len(l.public))
+	for _, w := range l.public {
+		isPublic[w] = struct{}{}
+	}
+	for w := range used {
+		_, public := isPublic[w]
+		_, computed := l.computedWitnesses[w]
+		if !public && !computed {
+			l.secret = append(l.secret, w)
+		}
+	}
+	sort.Slice(l.secret, func(i, j int) bool { return l.secret[i] < l.secret[j] })
+
+	return &Circuit{
+		Public: make([]frontend.Variable, len(l.public)),
+		Secret: make([]frontend.Variable, len(l.secret)),
+		layout: l,
+	}
+}
+
+// PublicWitnesses returns the witnesses of the public inputs of the circuit, in order
+func (c *Circuit) PublicWitnesses() []Witness {
+	return c.layout.public
+}
+
+// SecretWitnesses returns the witnesses of the secret inputs of the circuit, in order
+func (c *Circuit) SecretWitnesses() []Witness {
+	return c.layout.secret
+}
+
+// Define replays the opcodes of the ACIR program
+func (c *Circuit) Define(api frontend.API) error {
+	if c.layout == nil {
+		return errors.New("acir: circuit must be created with NewCircuit")
+	}
+	r := replay{api: api, vars: make(map[Witness]frontend.Variable)}
+	for i, w := range c.layout.public {
+		r.vars[w] = c.Public[i]
+	}
+	for i, w := range c.layout.secret {
+		r.vars[w] = c.Secret[i]
+	}
+
+	for i, o := range c.layout.program.Opcodes {
+		var err error
+		switch {
+		case o.Arithmetic != nil:
+			var e frontend.Variable
+			if e, err = r.expression(o.Arithmetic); err == nil {
+				api.AssertIsEqual(e, 0)
+			}
+		case o.BlackBoxFuncCall != nil:
+			err = r.blackBoxFuncCall(o.BlackBoxFuncCall)
+		case o.Directive != nil:
+			err = r.directive(o.Directive)
+		default:
+			err = fmt.Errorf("%w: opcode %s", ErrUnsupported, o.Name)
+		}
+		if err != nil {
+			return fmt.Errorf("opcode %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// replay holds the variables of the witnesses assigned so far
+type replay struct {
+	api  frontend.API
+	vars map[Witness]frontend.Variable
+}
+
+func (r *replay) get(w Witness) (frontend.Variable, error) {
+	v, ok := r.vars[w]
+	if !ok {
+		return nil, fmt.Errorf("witness %d is used before being assigned", w)
+	}
+	return v, nil
+}
+
+// set assigns v to w, or asserts that they are equal if w is already assigned
+func (r *replay) set(w Witness, v frontend.Variable) {
+	if prev, ok := r.vars[w]; ok {
+		r.api.AssertIsEqual(prev, v)
+		return
+	}
+	r.vars[w] = v
+}
+
+func (r *replay) expression(e *Expression) (frontend.Variable, error) {
+	terms := make([]frontend.Variable, 0, 2+len(e.MulTerms)+len(e.Linear))
+	terms = append(terms, new(big.Int).Set(&e.Constant))
+	for _, t := range e.MulTerms {
+		left, err := r.get(t.L)
+		if err != nil {
+			return nil, err
+		}
+		right, err := r.get(t.R)
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, r.api.Mul(new(big.Int).Set(&t.Coeff), left, right))
+	}
+	for _, t := range e.Linear {
+		v, err := r.get(t.W)
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, r.api.Mul(new(big.Int).Set(&t.Coeff), v))
+	}
+	if len(terms) == 1 {
+		terms = append(terms, 0)
+	}
+	return r.api.Add(terms[0], terms[1], terms[2:]...), nil
+}
+
+func (r *replay) blackBoxFuncCall(c *BlackBoxFuncCall) error {
+	nbBits := r.api.Compiler().Curve().Info().Fr.Bits
+	switch c.Name {
+	case "RANGE":
+		if len(c.Inputs) != 1 {
+			return fmt.Errorf("RANGE expects 1 input, got %d", len(c.Inputs))
+		}
+		v, err := r.get(c.Inputs[0].Witness)
+		if err != nil {
+			return err
+		}
+		if int(c.Inputs[0].NumBits) < nbBits {
+			r.api.ToBinary(v, int(c.Inputs[0].NumBits))
+		}
+		return nil
+	case "AND", "XOR":
+		if len(c.Inputs) != 2 || len(c.Outputs) != 1 {
+			return fmt.Errorf("%s expects 2 inputs and 1 output, got %d and %d", c.Name, len(c.Inputs), len(c.Outputs))
+		}
+		n := int(c.Inputs[0].NumBits)
+		if m := int(c.Inputs[1].NumBits); m > n {
+			n = m
+		}
+		if n >= nbBits {
+			return fmt.Errorf("%s on %d bits exceeds the field size", c.Name, n)
+		}
+		a, err := r.get(c.Inputs[0].Witness)
+		if err != nil {
+			return err
+		}
+		b, err := r.get(c.Inputs[1].Witness)
+		if err != nil {
+			return err
+		}
+		aBits, bBits := r.api.ToBinary(a, n), r.api.ToBinary(b, n)
+		res := make([]frontend.Variable, n)
+		for i := range res {
+			if c.Name == "AND" {
+				res[i] = r.api.And(aBits[i], bBits[i])
+			} else {
+				res[i] = r.api.Xor(aBits[i], bBits[i])
+			}
+		}
+		r.set(c.Outputs[0], r.api.FromBinary(res...))
+		return nil
+	default:
+		return fmt.Errorf("%w: black box function %s", ErrUnsupported, c.Name)
+	}
+}
+
+// directive computes the outputs of d with a hint; the outputs are constrained by the arithmetic
+// opcodes of the program. If all the outputs are already assigned, the directive is skipped.
+func (r *replay) directive(d *Directive) error {
+	var outputs []Witness
+	var inputs []frontend.Variable
+	var f hint.Function
+	switch {
+	case d.Invert != nil:
+		x, err := r.get(d.Invert.X)
+		if err != nil {
+			return err
+		}
+		f, inputs, outputs = InvertHint, []frontend.Variable{x}, []Witness{d.Invert.Result}
+	case d.Quotient != nil:
+		a, err := r.expression(&d.Quotient.A)
+		if err != nil {
+			return err
+		}
+		b, err := r.expression(&d.Quotient.B)
+		if err != nil {
+			return err
+		}
+		var predicate frontend.Variable = 1
+		if d.Quotient.Predicate != nil {
+			if predicate, err = r.expression(d.Quotient.Predicate); err != nil {
+				return err
+			}
+		}
+		f, inputs, outputs = QuotientHint, []frontend.Variable{a, b, predicate}, []Witness{d.Quotient.Q, d.Quotient.R}
+	case d.ToRadix != nil:
+		a, err := r.expression(&d.ToRadix.A)
+		if err != nil {
+			return err
+		}
+		f, inputs, outputs = ToRadixHint, []frontend.Variable{d.ToRadix.Radix, a}, d.ToRadix.Digits
+	default:
+		return fmt.Errorf("%w: directive %s", ErrUnsupported, d.Name)
+	}
+	assigned := true
+	for _, w := range outputs {
+		if _, ok := r.vars[w]; !ok {
+			assigned = false
+		}
+	}
+	if assigned {
+		return nil
+	}
+
+	results, err := r.api.Compiler().NewHint(f, len(outputs), inputs...)
+	if err != nil {
+		return err
+	}
+	for i, w := range outputs {
+		r.set(w, results[i])
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+// Copyright 2022 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package acir
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/hint"
+)
+
+func init() {
+	hint.Register(InvertHint)
+	hint.Register(QuotientHint)
+	hint.Register(ToRadixHint)
+}
+
+// InvertHint implements the Invert directive: it expects one input x and returns 1/x, or 0 if x is 0
+func InvertHint(curveID ecc.ID, inputs []*big.Int, results []*big.Int) error {
+	if inputs[0].Sign() == 0 {
+		results[0].SetUint64(0)
+		return nil
+	}
+	if results[0].ModInverse(inputs[0], curveID.Info().Fr.Modulus()) == nil {
+		return errors.New("input is not invertible")
+	}
+	return nil
+}
+
+// QuotientHint implements the Quotient directive: it expects three inputs a, b and predicate,
+// and returns the quotient and the remainder of the euclidean division of a by b, or 0 and 0 if
+// b or the predicate is 0
+func QuotientHint(_ ecc.ID, inputs []*big.Int, results []*big.Int) error {
+	a, b, predicate := inputs[0], inputs[1], inputs[2]
+	if predicate.Sign() == 0 || b.Sign() == 0 {
+		results[0].SetUint64(0)
+		results[1].SetUint64(0)
+		return nil
+	}
+	results[0].DivMod(a, b, results[1])
+	return nil
+}
+
+// ToRadixHint implements the ToRadix directive: it expects two inputs radix and a, and returns the
+// first little-endian digits of a in base radix. The number of digits is the length of results.
+func ToRadixHint(_ ecc.ID, inputs []*big.Int, results []*big.Int) error {
+	radix := inputs[0]
+	if radix.Cmp(big.NewInt(2)) < 0 {
+		return errors.New("radix must be at least 2")
+	}
+	a := new(big.Int).Set(inputs[1])
+	for i := range results {
+		a.DivMod(a, radix, results[i])
+	}
+	return nil
+}
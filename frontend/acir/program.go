@@ -0,0 +1,455 @@
+// Copyright 2022 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package acir
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+	"strings"
+)
+
+// Witness is the index of an ACIR witness
+type Witness uint32
+
+// Program is an ACIR circuit
+type Program struct {
+	// CurrentWitnessIndex is the largest witness index used by the program
+	CurrentWitnessIndex uint32
+
+	Opcodes []Opcode
+
+	// PublicParameters and ReturnValues are the public inputs of the program
+	PublicParameters []Witness
+	ReturnValues     []Witness
+}
+
+// Opcode is an ACIR opcode; exactly one of Arithmetic, BlackBoxFuncCall or Directive is set,
+// unless the opcode isn't supported, in which case only Name is set
+type Opcode struct {
+	Name string
+
+	Arithmetic       *Expression
+	BlackBoxFuncCall *BlackBoxFuncCall
+	Directive        *Directive
+}
+
+// Expression is the quadratic expression
+// Σ MulTerms[i].Coeff * MulTerms[i].L * MulTerms[i].R + Σ Linear[i].Coeff * Linear[i].W + Constant
+// An Arithmetic opcode asserts that its expression equals 0.
+type Expression struct {
+	MulTerms []MulTerm
+	Linear   []LinearTerm
+	Constant big.Int
+}
+
+// MulTerm is the term Coeff * L * R of an Expression
+type MulTerm struct {
+	Coeff big.Int
+	L, R  Witness
+}
+
+// LinearTerm is the term Coeff * W of an Expression
+type LinearTerm struct {
+	Coeff big.Int
+	W     Witness
+}
+
+// FunctionInput is a witness input of a black box function, of at most NumBits bits
+type FunctionInput struct {
+	Witness Witness
+	NumBits uint32
+}
+
+// BlackBoxFuncCall is a call to a black box function, such as RANGE, AND or XOR
+type BlackBoxFuncCall struct {
+	Name    string
+	Inputs  []FunctionInput
+	Outputs []Witness
+}
+
+// Directive computes witnesses without constraining them; exactly one of Invert, Quotient or
+// ToRadix is set, unless the directive isn't supported, in which case only Name is set
+type Directive struct {
+	Name string
+
+	Invert   *InvertDirective
+	Quotient *QuotientDirective
+	ToRadix  *ToRadixDirective
+}
+
+// InvertDirective sets Result to 1/X, or 0 if X is 0
+type InvertDirective struct {
+	X, Result Witness
+}
+
+// QuotientDirective sets Q and R to the quotient and the remainder of the euclidean division of
+// A by B, or 0 if Predicate is set and evaluates to 0
+type QuotientDirective struct {
+	A, B      Expression
+	Q, R      Witness
+	Predicate *Expression
+}
+
+// ToRadixDirective sets Digits to the little-endian decomposition of A in base Radix
+type ToRadixDirective struct {
+	A      Expression
+	Digits []Witness
+	Radix  uint32
+}
+
+// ReadProgram reads a JSON-encoded ACIR circuit from r, as serialized by serde_json from the acir
+// Circuit type: {"current_witness_index", "opcodes", "public_parameters", "return_values"}.
+// Field elements are hex strings, with or without a 0x prefix. The legacy "gates" encoding, with
+// Range, And and Xor gates and GadgetCall black box calls, is also accepted.
+//
+// nargo's gzip-compressed bincode encoding of the Circuit type is also accepted, see the package
+// documentation.
+func ReadProgram(r io.Reader) (*Program, error) {
+	br, compressed, err := decompress(r)
+	if err != nil {
+		return nil, err
+	}
+	if compressed {
+		b := bincodeReader{r: br}
+		p := b.program()
+		b.end()
+		if b.err != nil {
+			return nil, fmt.Errorf("bincode: %w", b.err)
+		}
+		return p, nil
+	}
+	var p Program
+	if err := json.NewDecoder(br).Decode(&p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// decompress returns a reader of the decompressed content of r and true if r starts with the gzip
+// magic number, as the programs and witnesses serialized by nargo, or a reader equivalent to r
+// and false otherwise
+func decompress(r io.Reader) (io.Reader, bool, error) {
+	br := bufio.NewReader(r)
+	magic, err := br.Peek(2)
+	if err != nil && err != io.EOF {
+		return nil, false, err
+	}
+	if len(magic) != 2 || magic[0] != 0x1f || magic[1] != 0x8b {
+		return br, false, nil
+	}
+	zr, err := gzip.NewReader(br)
+	if err != nil {
+		return nil, false, err
+	}
+	return bufio.NewReader(zr), true, nil
+}
+
+// PublicInputs returns the sorted public parameters and return values of the program
+func (p *Program) PublicInputs() []Witness {
+	seen := make(map[Witness]struct{})
+	var res []Witness
+	for _, l := range [][]Witness{p.PublicParameters, p.ReturnValues} {
+		for _, w := range l {
+			if _, ok := seen[w]; !ok {
+				seen[w] = struct{}{}
+				res = append(res, w)
+			}
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i] < res[j] })
+	return res
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (p *Program) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		CurrentWitnessIndex uint32          `json:"current_witness_index"`
+		Opcodes             []Opcode        `json:"opcodes"`
+		Gates               []Opcode        `json:"gates"`
+		PublicParameters    json.RawMessage `json:"public_parameters"`
+		ReturnValues        json.RawMessage `json:"return_values"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	p.CurrentWitnessIndex = raw.CurrentWitnessIndex
+	p.Opcodes = raw.Opcodes
+	if len(p.Opcodes) == 0 {
+		p.Opcodes = raw.Gates
+	}
+	var err error
+	if p.PublicParameters, err = unmarshalWitnesses(raw.PublicParameters); err != nil {
+		return err
+	}
+	if p.ReturnValues, err = unmarshalWitnesses(raw.ReturnValues); err != nil {
+		return err
+	}
+	return nil
+}
+
+// unmarshalWitnesses decodes a list of witnesses, serialized as an array or as a PublicInputs
+// newtype wrapping an array
+func unmarshalWitnesses(data json.RawMessage) ([]Witness, error) {
+	if len(data) == 0 || string(data) == "null" {
+		return nil, nil
+	}
+	var res []Witness
+	if err := json.Unmarshal(data, &res); err == nil {
+		return res, nil
+	}
+	var wrapped [1][]Witness
+	if err := json.Unmarshal(data, &wrapped); err != nil {
+		return nil, fmt.Errorf("invalid witness list %s", data)
+	}
+	return wrapped[0], nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler; opcodes are serialized as {"<Name>": <value>}
+func (o *Opcode) UnmarshalJSON(data []byte) error {
+	name, value, err := unmarshalVariant(data)
+	if err != nil {
+		return err
+	}
+	*o = Opcode{Name: name}
+	switch name {
+	case "Arithmetic":
+		o.Arithmetic = new(Expression)
+		return json.Unmarshal(value, o.Arithmetic)
+	case "BlackBoxFuncCall", "GadgetCall":
+		o.Name = "BlackBoxFuncCall"
+		o.BlackBoxFuncCall = new(BlackBoxFuncCall)
+		return json.Unmarshal(value, o.BlackBoxFuncCall)
+	case "Directive":
+		o.Directive = new(Directive)
+		return json.Unmarshal(value, o.Directive)
+	case "Range":
+		var r [2]uint32
+		if err := json.Unmarshal(value, &r); err != nil {
+			return err
+		}
+		o.Name = "BlackBoxFuncCall"
+		o.BlackBoxFuncCall = &BlackBoxFuncCall{
+			Name:   "RANGE",
+			Inputs: []FunctionInput{{Witness: Witness(r[0]), NumBits: r[1]}},
+		}
+	case "And", "Xor":
+		var g struct {
+			A       Witness `json:"a"`
+			B       Witness `json:"b"`
+			Result  Witness `json:"result"`
+			NumBits uint32  `json:"num_bits"`
+		}
+		if err := json.Unmarshal(value, &g); err != nil {
+			return err
+		}
+		o.Name = "BlackBoxFuncCall"
+		o.BlackBoxFuncCall = &BlackBoxFuncCall{
+			Name:    strings.ToUpper(name),
+			Inputs:  []FunctionInput{{Witness: g.A, NumBits: g.NumBits}, {Witness: g.B, NumBits: g.NumBits}},
+			Outputs: []Witness{g.Result},
+		}
+	}
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (e *Expression) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		MulTerms [][3]json.RawMessage `json:"mul_terms"`
+		Linear   [][2]json.RawMessage `json:"linear_combinations"`
+		Constant json.RawMessage      `json:"q_c"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*e = Expression{
+		MulTerms: make([]MulTerm, len(raw.MulTerms)),
+		Linear:   make([]LinearTerm, len(raw.Linear)),
+	}
+	for i, t := range raw.MulTerms {
+		if err := unmarshalFieldElement(t[0], &e.MulTerms[i].Coeff); err != nil {
+			return err
+		}
+		if err := json.Unmarshal(t[1], &e.MulTerms[i].L); err != nil {
+			return err
+		}
+		if err := json.Unmarshal(t[2], &e.MulTerms[i].R); err != nil {
+			return err
+		}
+	}
+	for i, t := range raw.Linear {
+		if err := unmarshalFieldElement(t[0], &e.Linear[i].Coeff); err != nil {
+			return err
+		}
+		if err := json.Unmarshal(t[1], &e.Linear[i].W); err != nil {
+			return err
+		}
+	}
+	if len(raw.Constant) != 0 {
+		return unmarshalFieldElement(raw.Constant, &e.Constant)
+	}
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Calls are serialized as {"name", "inputs", "outputs"},
+// or as an enum in later acir versions: {"AND": {"lhs", "rhs", "output"}}, {"RANGE": {"input"}}, ...
+func (c *BlackBoxFuncCall) UnmarshalJSON(data []byte) error {
+	type functionInput struct {
+		Witness Witness `json:"witness"`
+		NumBits uint32  `json:"num_bits"`
+	}
+	var raw struct {
+		Name    *string         `json:"name"`
+		Inputs  []functionInput `json:"inputs"`
+		Outputs []Witness       `json:"outputs"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if raw.Name == nil {
+		name, value, err := unmarshalVariant(data)
+		if err != nil {
+			return err
+		}
+		var call struct {
+			Input  functionInput `json:"input"`
+			LHS    functionInput `json:"lhs"`
+			RHS    functionInput `json:"rhs"`
+			Output Witness       `json:"output"`
+		}
+		*c = BlackBoxFuncCall{Name: name}
+		switch name {
+		case "AND", "XOR":
+			if err := json.Unmarshal(value, &call); err != nil {
+				return err
+			}
+			c.Inputs = []FunctionInput{FunctionInput(call.LHS), FunctionInput(call.RHS)}
+			c.Outputs = []Witness{call.Output}
+		case "RANGE":
+			if err := json.Unmarshal(value, &call); err != nil {
+				return err
+			}
+			c.Inputs = []FunctionInput{FunctionInput(call.Input)}
+		}
+		return nil
+	}
+	c.Name = strings.ToUpper(*raw.Name)
+	c.Inputs = make([]FunctionInput, len(raw.Inputs))
+	for i, in := range raw.Inputs {
+		c.Inputs[i] = FunctionInput(in)
+	}
+	c.Outputs = raw.Outputs
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler; directives are serialized as {"<Name>": <value>}
+func (d *Directive) UnmarshalJSON(data []byte) error {
+	name, value, err := unmarshalVariant(data)
+	if err != nil {
+		return err
+	}
+	*d = Directive{Name: name}
+	switch name {
+	case "Invert":
+		var raw struct {
+			X      Witness `json:"x"`
+			Result Witness `json:"result"`
+		}
+		if err := json.Unmarshal(value, &raw); err != nil {
+			return err
+		}
+		d.Invert = &InvertDirective{X: raw.X, Result: raw.Result}
+	case "Quotient":
+		var raw struct {
+			A         Expression  `json:"a"`
+			B         Expression  `json:"b"`
+			Q         Witness     `json:"q"`
+			R         Witness     `json:"r"`
+			Predicate *Expression `json:"predicate"`
+		}
+		if err := json.Unmarshal(value, &raw); err != nil {
+			return err
+		}
+		d.Quotient = &QuotientDirective{A: raw.A, B: raw.B, Q: raw.Q, R: raw.R, Predicate: raw.Predicate}
+	case "ToRadix", "ToLeRadix", "Split":
+		var raw struct {
+			A       Expression `json:"a"`
+			B       []Witness  `json:"b"`
+			Radix   uint32     `json:"radix"`
+			BitSize uint32     `json:"bit_size"`
+		}
+		if err := json.Unmarshal(value, &raw); err != nil {
+			return err
+		}
+		if name == "Split" {
+			// Split is the legacy binary decomposition
+			raw.Radix = 2
+		}
+		d.Name = "ToRadix"
+		d.ToRadix = &ToRadixDirective{A: raw.A, Digits: raw.B, Radix: raw.Radix}
+	}
+	return nil
+}
+
+// unmarshalVariant decodes a serde externally tagged enum value, {"<Name>": <value>} or "<Name>"
+func unmarshalVariant(data []byte) (string, json.RawMessage, error) {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		return name, nil, nil
+	}
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(data, &m); err != nil {
+		return "", nil, err
+	}
+	if len(m) != 1 {
+		return "", nil, fmt.Errorf("invalid enum value %s", data)
+	}
+	for name, value := range m {
+		return name, value, nil
+	}
+	panic("unreachable")
+}
+
+// unmarshalFieldElement decodes a field element, serialized as a hex string with an optional 0x
+// prefix, or as a JSON number
+func unmarshalFieldElement(data json.RawMessage, v *big.Int) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		// not a string, try a number
+		if _, ok := v.SetString(string(data), 10); !ok {
+			return fmt.Errorf("invalid field element %s", data)
+		}
+		return nil
+	}
+	return parseFieldElement(s, v)
+}
+
+// parseFieldElement decodes the hex string of a field element, with an optional 0x prefix
+func parseFieldElement(s string, v *big.Int) error {
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X")
+	if s == "" {
+		v.SetUint64(0)
+		return nil
+	}
+	if _, ok := v.SetString(s, 16); !ok {
+		return fmt.Errorf("invalid field element %q", s)
+	}
+	return nil
+}
@@ -0,0 +1,277 @@
+// Copyright 2022 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package acir
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/hint"
+)
+
+// WitnessMap maps ACIR witnesses to their values
+type WitnessMap map[Witness]*big.Int
+
+// ReadWitness reads a JSON-encoded ACIR witness map from r, as serialized by serde_json:
+// {"<witness index>": "<hex value>", ...}
+//
+// nargo's gzip-compressed bincode encoding of the WitnessMap type is also accepted, see the
+// package documentation.
+func ReadWitness(r io.Reader) (WitnessMap, error) {
+	br, compressed, err := decompress(r)
+	if err != nil {
+		return nil, err
+	}
+	if compressed {
+		b := bincodeReader{r: br}
+		w := b.witnessMap()
+		b.end()
+		if b.err != nil {
+			return nil, fmt.Errorf("bincode: %w", b.err)
+		}
+		return w, nil
+	}
+	var raw map[string]json.RawMessage
+	if err := json.NewDecoder(br).Decode(&raw); err != nil {
+		return nil, err
+	}
+	res := make(WitnessMap, len(raw))
+	for k, v := range raw {
+		w, err := strconv.ParseUint(k, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid witness index %q", k)
+		}
+		res[Witness(w)] = new(big.Int)
+		if err := unmarshalFieldElement(v, res[Witness(w)]); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// NewAssignment returns an assignment of NewCircuit(p) from the partial witness w, to be passed to
+// frontend.NewWitness.
+//
+// Inputs missing from w are solved as the ACVM would: an arithmetic opcode with a single unknown
+// witness of degree 1 is solved for it, and directives and the AND and XOR black box functions are
+// evaluated when their inputs are known.
+func NewAssignment(curveID ecc.ID, p *Program, w WitnessMap) (*Circuit, error) {
+	c := NewCircuit(p)
+	values := make(WitnessMap, len(w))
+	for k, v := range w {
+		values[k] = v
+	}
+	s := solver{values: values, curveID: curveID, modulus: curveID.Info().Fr.Modulus()}
+
+	for progress := true; progress; {
+		progress = false
+		for i := range p.Opcodes {
+			solved, err := s.solve(&p.Opcodes[i])
+			if err != nil {
+				return nil, fmt.Errorf("opcode %d: %w", i, err)
+			}
+			progress = progress || solved
+		}
+	}
+
+	for i, id := range c.layout.public {
+		v, ok := values[id]
+		if !ok {
+			return nil, fmt.Errorf("public witness %d is not assigned", id)
+		}
+		c.Public[i] = v
+	}
+	for i, id := range c.layout.secret {
+		v, ok := values[id]
+		if !ok {
+			return nil, fmt.Errorf("secret witness %d is not assigned", id)
+		}
+		c.Secret[i] = v
+	}
+	return c, nil
+}
+
+// solver evaluates ACIR opcodes natively to complete a partial witness
+type solver struct {
+	values  WitnessMap
+	curveID ecc.ID
+	modulus *big.Int
+}
+
+// solve assigns the unknown witnesses of o that can be computed, and returns true if it did
+func (s *solver) solve(o *Opcode) (bool, error) {
+	switch {
+	case o.Arithmetic != nil:
+		return s.arithmetic(o.Arithmetic), nil
+	case o.BlackBoxFuncCall != nil:
+		c := o.BlackBoxFuncCall
+		if (c.Name != "AND" && c.Name != "XOR") || len(c.Inputs) != 2 || len(c.Outputs) != 1 || s.known(c.Outputs[0]) {
+			return false, nil
+		}
+		a, okA := s.values[c.Inputs[0].Witness]
+		b, okB := s.values[c.Inputs[1].Witness]
+		if !okA || !okB {
+			return false, nil
+		}
+		res := new(big.Int)
+		if c.Name == "AND" {
+			res.And(a, b)
+		} else {
+			res.Xor(a, b)
+		}
+		s.values[c.Outputs[0]] = res
+		return true, nil
+	case o.Directive != nil:
+		return s.directive(o.Directive)
+	}
+	return false, nil
+}
+
+func (s *solver) known(w Witness) bool {
+	_, ok := s.values[w]
+	return ok
+}
+
+// arithmetic solves e == 0 if it has exactly one unknown witness, appearing in linear terms or in
+// products with a known witness
+func (s *solver) arithmetic(e *Expression) bool {
+	var unknown *Witness
+	var coeff, acc big.Int
+	acc.Set(&e.Constant)
+	addTerm := func(c *big.Int, factors ...Witness) bool {
+		t := new(big.Int).Set(c)
+		var u *Witness
+		for i := range factors {
+			if v, ok := s.values[factors[i]]; ok {
+				t.Mul(t, v)
+			} else if u == nil {
+				u = &factors[i]
+			} else {
+				return false // degree 2 in an unknown
+			}
+		}
+		if u == nil {
+			acc.Add(&acc, t)
+			return true
+		}
+		if unknown != nil && *unknown != *u {
+			return false
+		}
+		unknown = u
+		coeff.Add(&coeff, t)
+		return true
+	}
+	for _, t := range e.MulTerms {
+		if !addTerm(&t.Coeff, t.L, t.R) {
+			return false
+		}
+	}
+	for _, t := range e.Linear {
+		if !addTerm(&t.Coeff, t.W) {
+			return false
+		}
+	}
+	if unknown == nil {
+		return false
+	}
+	coeff.Mod(&coeff, s.modulus)
+	if coeff.ModInverse(&coeff, s.modulus) == nil {
+		return false
+	}
+	res := new(big.Int).Neg(&acc)
+	res.Mul(res, &coeff).Mod(res, s.modulus)
+	s.values[*unknown] = res
+	return true
+}
+
+func (s *solver) directive(d *Directive) (bool, error) {
+	var outputs []Witness
+	var inputs []*big.Int
+	var f hint.Function
+	switch {
+	case d.Invert != nil:
+		x, ok := s.values[d.Invert.X]
+		if !ok {
+			return false, nil
+		}
+		f, inputs, outputs = InvertHint, []*big.Int{x}, []Witness{d.Invert.Result}
+	case d.Quotient != nil:
+		a, okA := s.evaluate(&d.Quotient.A)
+		b, okB := s.evaluate(&d.Quotient.B)
+		predicate, okP := big.NewInt(1), true
+		if d.Quotient.Predicate != nil {
+			predicate, okP = s.evaluate(d.Quotient.Predicate)
+		}
+		if !okA || !okB || !okP {
+			return false, nil
+		}
+		f, inputs, outputs = QuotientHint, []*big.Int{a, b, predicate}, []Witness{d.Quotient.Q, d.Quotient.R}
+	case d.ToRadix != nil:
+		a, ok := s.evaluate(&d.ToRadix.A)
+		if !ok {
+			return false, nil
+		}
+		f, inputs, outputs = ToRadixHint, []*big.Int{new(big.Int).SetUint64(uint64(d.ToRadix.Radix)), a}, d.ToRadix.Digits
+	default:
+		return false, nil
+	}
+
+	solved := true
+	for _, w := range outputs {
+		solved = solved && s.known(w)
+	}
+	if solved {
+		return false, nil
+	}
+	results := make([]*big.Int, len(outputs))
+	for i := range results {
+		results[i] = new(big.Int)
+	}
+	if err := f(s.curveID, inputs, results); err != nil {
+		return false, err
+	}
+	for i, w := range outputs {
+		if !s.known(w) {
+			s.values[w] = results[i]
+		}
+	}
+	return true, nil
+}
+
+// evaluate returns the value of e mod the field modulus, and false if a witness is unknown
+func (s *solver) evaluate(e *Expression) (*big.Int, bool) {
+	res := new(big.Int).Set(&e.Constant)
+	var t big.Int
+	for _, m := range e.MulTerms {
+		l, okL := s.values[m.L]
+		r, okR := s.values[m.R]
+		if !okL || !okR {
+			return nil, false
+		}
+		res.Add(res, t.Mul(&m.Coeff, l).Mul(&t, r))
+	}
+	for _, m := range e.Linear {
+		v, ok := s.values[m.W]
+		if !ok {
+			return nil, false
+		}
+		res.Add(res, t.Mul(&m.Coeff, v))
+	}
+	return res.Mod(res, s.modulus), true
+}
@@ -15,6 +15,7 @@
 package frontend
 
 import (
+	"encoding/json"
 	"io"
 
 	"github.com/consensys/gnark-crypto/ecc"
@@ -30,9 +31,17 @@ type CompiledConstraintSystem interface {
 	io.WriterTo
 	io.ReaderFrom
 
+	// MarshalJSON and UnmarshalJSON encode the constraint system in a human-readable, lossless form
+	json.Marshaler
+	json.Unmarshaler
+
 	// IsSolved returns nil if given witness solves the constraint system and error otherwise
 	IsSolved(witness *witness.Witness, opts ...backend.ProverOption) error
 
+	// CompleteWitness solves the constraint system with the given full witness and sets the
+	// values of the public outputs (see schema.Tag) in the witness vector
+	CompleteWitness(witness *witness.Witness, opts ...backend.ProverOption) error
+
 	// GetNbVariables return number of internal, secret and public Variables
 	GetNbVariables() (internal, secret, public int)
 	GetNbConstraints() int
@@ -50,7 +50,7 @@ func Compile(curveID ecc.ID, newBuilder NewBuilder, circuit Circuit, opts ...Com
 
 	// parse the circuit builds a schema of the circuit
 	// and call circuit.Define() method to initialize a list of constraints in the compiler
-	if err = parseCircuit(builder, circuit); err != nil {
+	if err = parseCircuit(builder, circuit, opt); err != nil {
 		log.Err(err).Msg("parsing circuit")
 		return nil, fmt.Errorf("parse circuit: %w", err)
 
@@ -60,7 +60,7 @@ func Compile(curveID ecc.ID, newBuilder NewBuilder, circuit Circuit, opts ...Com
 	return builder.Compile()
 }
 
-func parseCircuit(builder Builder, circuit Circuit) (err error) {
+func parseCircuit(builder Builder, circuit Circuit, opt CompileConfig) (err error) {
 	// ensure circuit.Define has pointer receiver
 	if reflect.ValueOf(circuit).Kind() != reflect.Ptr {
 		return errors.New("frontend.Circuit methods must be defined on pointer receiver")
@@ -74,8 +74,22 @@ func parseCircuit(builder Builder, circuit Circuit) (err error) {
 	log := logger.Logger()
 	log.Info().Int("nbSecret", s.NbSecret).Int("nbPublic", s.NbPublic).Msg("parsed circuit inputs")
 
+	// if the public inputs are compressed, the only public variable is their hash
+	// and the public inputs are allocated as secret variables
+	hashPublicInputs := opt.PublicInputsHash != nil
+	var publicInputs []Variable
+	var publicInputsHash Variable
+
 	// this not only set the schema, but sets the wire offsets for public, secret and internal wires
-	builder.SetSchema(s)
+	if hashPublicInputs {
+		builder.SetSchema(publicInputsHashSchema(s))
+		publicInputsHash = builder.AddPublicVariable(PublicInputsHashName)
+	} else {
+		builder.SetSchema(s)
+	}
+
+	// public outputs wires, to be tied to the values assigned in circuit.Define()
+	outputs := make(map[string]Variable, len(s.Outputs))
 
 	// leaf handlers are called when encoutering leafs in the circuit data struct
 	// leafs are Constraints that need to be initialized in the context of compiling a circuit
@@ -86,7 +100,17 @@ func parseCircuit(builder Builder, circuit Circuit) (err error) {
 			case schema.Secret:
 				tInput.Set(reflect.ValueOf(builder.AddSecretVariable(name)))
 			case schema.Public:
-				tInput.Set(reflect.ValueOf(builder.AddPublicVariable(name)))
+				var v Variable
+				if hashPublicInputs {
+					v = builder.AddSecretVariable(name)
+					publicInputs = append(publicInputs, v)
+				} else {
+					v = builder.AddPublicVariable(name)
+				}
+				if s.IsOutput(name) {
+					outputs[name] = v
+				}
+				tInput.Set(reflect.ValueOf(v))
 			case schema.Unset:
 				return errors.New("can't set val " + name + " visibility is unset")
 			}
@@ -114,9 +138,79 @@ func parseCircuit(builder Builder, circuit Circuit) (err error) {
 		return fmt.Errorf("define circuit: %w", err)
 	}
 
+	if len(outputs) != 0 {
+		if err = tieOutputs(builder, circuit, outputs); err != nil {
+			return err
+		}
+	}
+
+	if hashPublicInputs {
+		h, err := opt.PublicInputsHash(builder)
+		if err != nil {
+			return fmt.Errorf("public inputs hash: %w", err)
+		}
+		h.Write(publicInputs...)
+		builder.AssertIsEqual(h.Sum(), publicInputsHash)
+	}
+
 	return
 }
 
+// tieOutputs constrains the public outputs to equal the values assigned in circuit.Define()
+func tieOutputs(builder Builder, circuit Circuit, outputs map[string]Variable) error {
+	var outputHandler schema.LeafHandler = func(visibility schema.Visibility, name string, tInput reflect.Value) error {
+		wire, ok := outputs[name]
+		if !ok {
+			return nil
+		}
+		v := tInput.Interface()
+		if v == nil || reflect.DeepEqual(v, wire) {
+			return fmt.Errorf("output %s not assigned in Define", name)
+		}
+		builder.AssertIsEqual(v, wire)
+		return nil
+	}
+	_, err := schema.Parse(circuit, tVariable, outputHandler)
+	return err
+}
+
+// publicInputsHashSchema returns the schema of a circuit compiled with WithPublicInputsHash:
+// the public inputs hash, followed by the circuit fields, all secret
+func publicInputsHashSchema(s *schema.Schema) *schema.Schema {
+	var toSecret func(fields []schema.Field) []schema.Field
+	toSecret = func(fields []schema.Field) []schema.Field {
+		r := make([]schema.Field, len(fields))
+		for i, f := range fields {
+			r[i] = f
+			if f.Visibility == schema.Public {
+				r[i].Visibility = schema.Secret
+			}
+			// outputs are secret inputs too: they are assigned, and hashed, as any public input
+			// (CompleteWitness can't solve them)
+			r[i].Output = false
+			if len(f.SubFields) != 0 {
+				r[i].SubFields = toSecret(f.SubFields)
+			}
+		}
+		return r
+	}
+
+	fields := make([]schema.Field, 0, len(s.Fields)+1)
+	fields = append(fields, schema.Field{
+		Name:       PublicInputsHashName,
+		Visibility: schema.Public,
+		Type:       schema.Leaf,
+	})
+	fields = append(fields, toSecret(s.Fields)...)
+
+	return &schema.Schema{
+		Fields:           fields,
+		NbPublic:         1,
+		NbSecret:         s.NbPublic + s.NbSecret,
+		PublicInputsHash: true,
+	}
+}
+
 // CompileOption defines option for altering the behaviour of the Compile
 // method. See the descriptions of the functions returning instances of this
 // type for available options.
@@ -125,6 +219,7 @@ type CompileOption func(opt *CompileConfig) error
 type CompileConfig struct {
 	Capacity                  int
 	IgnoreUnconstrainedInputs bool
+	PublicInputsHash          func(api API) (PublicInputsHasher, error)
 }
 
 // WithCapacity is a compile option that specifies the estimated capacity needed
@@ -151,6 +246,35 @@ func IgnoreUnconstrainedInputs() CompileOption {
 	}
 }
 
+// PublicInputsHashName is the name of the single public variable of a circuit compiled
+// with WithPublicInputsHash
+const PublicInputsHashName = "PublicInputsHash"
+
+// PublicInputsHasher is the in-circuit hash function used by WithPublicInputsHash.
+// The gadgets implementing std/hash.Hash satisfy this interface.
+type PublicInputsHasher interface {
+	Write(data ...Variable)
+	Sum() Variable
+}
+
+// WithPublicInputsHash is a compile option that replaces the public inputs of the circuit with
+// a single public variable, constrained to be the hash of the public inputs.
+//
+// newHasher instantiates the hash gadget (for example MiMC); the public inputs are written to it
+// in the schema order, that is, in the order of the public witness vector. The public inputs
+// become secret inputs of the compiled circuit.
+//
+// This reduces the size of the verifying key and the cost of the verification, which no longer
+// depend on the number of public inputs. The witnesses of the compiled circuit are obtained with
+// PublicInputsHashWitness, from the witness of the original circuit and the hash of its public
+// part computed natively with the same hash function.
+func WithPublicInputsHash(newHasher func(api API) (PublicInputsHasher, error)) CompileOption {
+	return func(opt *CompileConfig) error {
+		opt.PublicInputsHash = newHasher
+		return nil
+	}
+}
+
 var tVariable reflect.Type
 
 func init() {
@@ -60,6 +60,20 @@ func (cs *ConstraintSystem) GetCounters() []Counter { return cs.Counters }
 
 func (cs *ConstraintSystem) GetSchema() *schema.Schema { return cs.Schema }
 
+// OutputWires returns the wire IDs of the public outputs (see schema.Schema.Outputs)
+func (cs *ConstraintSystem) OutputWires() []int {
+	if cs.Schema == nil || len(cs.Schema.Outputs) == 0 {
+		return nil
+	}
+	r := make([]int, 0, len(cs.Schema.Outputs))
+	for i, name := range cs.Public {
+		if cs.Schema.IsOutput(name) {
+			r = append(r, i)
+		}
+	}
+	return r
+}
+
 // Counter contains measurements of useful statistics between two Tag
 type Counter struct {
 	From, To      string
@@ -1,6 +1,7 @@
 package compiled
 
 import (
+	"encoding/json"
 	"fmt"
 	"math/big"
 	"reflect"
@@ -128,3 +129,120 @@ func (h *Hint) UnmarshalCBOR(b []byte) error {
 	h.Wires = v.Wires
 	return nil
 }
+
+// hintInputJSON is the JSON encoding of a hint input, which sets exactly one of its fields, so that the
+// type of the input is preserved
+type hintInputJSON struct {
+	LinearExpression *LinearExpression `json:",omitempty"`
+	Term             *Term             `json:",omitempty"`
+	BigInt           *big.Int          `json:",omitempty"`
+	BigIntPtr        *big.Int          `json:",omitempty"`
+}
+
+// hintJSON is the JSON encoding of a Hint
+type hintJSON struct {
+	ID     hint.ID
+	Name   string `json:",omitempty"`
+	Inputs []hintInputJSON
+	Wires  []int
+}
+
+// MarshalJSON implements json.Marshaler
+//
+// Each input is encoded as an object with a single field, its type (LinearExpression, Term, BigInt or
+// BigIntPtr), for example {"Term": "c1*i4"} or {"BigInt": 42}.
+func (h Hint) MarshalJSON() ([]byte, error) {
+	v, err := h.toJSON()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+func (h Hint) toJSON() (hintJSON, error) {
+	v := hintJSON{ID: h.ID, Wires: h.Wires}
+	if h.Inputs != nil {
+		v.Inputs = make([]hintInputJSON, len(h.Inputs))
+	}
+	for i := range h.Inputs {
+		switch vit := h.Inputs[i].(type) {
+		case LinearExpression:
+			if vit == nil {
+				vit = LinearExpression{}
+			}
+			v.Inputs[i].LinearExpression = &vit
+		case Term:
+			v.Inputs[i].Term = &vit
+		case big.Int:
+			v.Inputs[i].BigInt = &vit
+		case *big.Int:
+			v.Inputs[i].BigIntPtr = vit
+		default:
+			return hintJSON{}, fmt.Errorf("unsupported hint input type %T", vit)
+		}
+	}
+	return v, nil
+}
+
+// NamedHint is a Hint along with the name of its function (see hint.Name), as registered in
+// ConstraintSystem.MHintsDependencies. It is JSON encoded as the Hint, with an additional "Name" field,
+// and decodes into a Hint.
+type NamedHint struct {
+	*Hint
+	Name string
+}
+
+// MarshalJSON implements json.Marshaler
+func (h NamedHint) MarshalJSON() ([]byte, error) {
+	v, err := h.Hint.toJSON()
+	if err != nil {
+		return nil, err
+	}
+	v.Name = h.Name
+	return json.Marshal(v)
+}
+
+// NamedHints returns the hints of the constraint system (see MHints) along with the names of their
+// functions, for JSON encoding
+func (cs *ConstraintSystem) NamedHints() map[int]NamedHint {
+	if cs.MHints == nil {
+		return nil
+	}
+	r := make(map[int]NamedHint, len(cs.MHints))
+	for wireID, h := range cs.MHints {
+		r[wireID] = NamedHint{Hint: h, Name: cs.MHintsDependencies[h.ID]}
+	}
+	return r
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+//
+// The "Name" field written by NamedHint is ignored, hint functions are resolved from their ID.
+func (h *Hint) UnmarshalJSON(data []byte) error {
+	var v hintJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	var inputs []interface{}
+	if v.Inputs != nil {
+		inputs = make([]interface{}, len(v.Inputs))
+	}
+	for i, vin := range v.Inputs {
+		switch {
+		case vin.LinearExpression != nil:
+			inputs[i] = *vin.LinearExpression
+		case vin.Term != nil:
+			inputs[i] = *vin.Term
+		case vin.BigInt != nil:
+			inputs[i] = *vin.BigInt
+		case vin.BigIntPtr != nil:
+			inputs[i] = vin.BigIntPtr
+		default:
+			return fmt.Errorf("hint input %d: missing value", i)
+		}
+	}
+	h.ID = v.ID
+	h.Inputs = inputs
+	h.Wires = v.Wires
+	return nil
+}
@@ -15,6 +15,8 @@
 package compiled
 
 import (
+	"encoding/json"
+	"fmt"
 	"math/big"
 	"strconv"
 	"strings"
@@ -160,19 +162,98 @@ func (t Term) CoeffID() int {
 func (t Term) string(sbb *strings.Builder, coeffs []big.Int) {
 	sbb.WriteString(coeffs[t.CoeffID()].String())
 	sbb.WriteString("*")
-	switch t.VariableVisibility() {
+	sbb.WriteByte(visibilityLetter(t.VariableVisibility()))
+	sbb.WriteString(strconv.Itoa(t.WireID()))
+}
+
+func visibilityLetter(v schema.Visibility) byte {
+	switch v {
 	case schema.Internal:
-		sbb.WriteString("i")
+		return 'i'
 	case schema.Public:
-		sbb.WriteString("p")
+		return 'p'
 	case schema.Secret:
-		sbb.WriteString("s")
+		return 's'
 	case schema.Virtual:
-		sbb.WriteString("v")
+		return 'v'
 	case schema.Unset:
-		sbb.WriteString("u")
+		return 'u'
 	default:
 		panic("not implemented")
 	}
+}
+
+// MarshalJSON implements json.Marshaler
+//
+// A Term is encoded as "c<coeffID>*<visibility><wireID>", where visibility is one of i, p, s, v or u
+// (internal, public, secret, virtual or unset), for example "c3*s0" for minus the first secret variable.
+// TermDelimitor is encoded as "|". Terms with other bits set are encoded as their uint64 value.
+func (t Term) MarshalJSON() ([]byte, error) {
+	if t == TermDelimitor {
+		return []byte(`"|"`), nil
+	}
+	visibility := (uint64(t) & maskVariableVisibility) >> shiftVariableVisibility
+	if uint64(t)&(maskDelimitor|maskFutureUse) != 0 || visibility > variableVirtual {
+		return []byte(strconv.FormatUint(uint64(t), 10)), nil
+	}
+	var sbb strings.Builder
+	sbb.WriteByte('"')
+	sbb.WriteByte('c')
+	sbb.WriteString(strconv.Itoa(t.CoeffID()))
+	sbb.WriteByte('*')
+	sbb.WriteByte(visibilityLetter(t.VariableVisibility()))
 	sbb.WriteString(strconv.Itoa(t.WireID()))
+	sbb.WriteByte('"')
+	return []byte(sbb.String()), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (t *Term) UnmarshalJSON(data []byte) error {
+	if len(data) == 0 || data[0] != '"' {
+		v, err := strconv.ParseUint(string(data), 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid term %s", data)
+		}
+		*t = Term(v)
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	if s == "|" {
+		*t = TermDelimitor
+		return nil
+	}
+
+	invalid := fmt.Errorf("invalid term %q, expected c<coeffID>*<visibility><wireID>", s)
+	i := strings.IndexByte(s, '*')
+	if i < 0 || i+1 >= len(s) || s[0] != 'c' {
+		return invalid
+	}
+	coeffID, err := strconv.ParseUint(s[1:i], 10, nbBitsCoeffID)
+	if err != nil {
+		return invalid
+	}
+	wireID, err := strconv.ParseUint(s[i+2:], 10, nbBitsWireID)
+	if err != nil {
+		return invalid
+	}
+	var visibility schema.Visibility
+	switch s[i+1] {
+	case 'i':
+		visibility = schema.Internal
+	case 'p':
+		visibility = schema.Public
+	case 's':
+		visibility = schema.Secret
+	case 'v':
+		visibility = schema.Virtual
+	case 'u':
+		visibility = schema.Unset
+	default:
+		return invalid
+	}
+	*t = Pack(int(wireID), int(coeffID), visibility)
+	return nil
 }
@@ -21,6 +21,7 @@ type Field struct {
 	Name       string
 	NameTag    string
 	Visibility Visibility
+	Output     bool // public variable computed by the circuit (`gnark:",output"`)
 	Type       FieldType
 	SubFields  []Field // will be set only if it's a struct, or an array of struct
 	ArraySize  int
@@ -29,6 +29,24 @@ type Schema struct {
 	Fields   []Field
 	NbPublic int
 	NbSecret int
+
+	// Outputs lists the full names of the public variables tagged as outputs (`gnark:",output"`),
+	// in the order they are visited. Outputs are counted in NbPublic.
+	Outputs []string
+
+	// PublicInputsHash is set if the circuit was compiled with frontend.WithPublicInputsHash:
+	// its only public variable is the hash of the public inputs, which are secret variables.
+	PublicInputsHash bool
+}
+
+// IsOutput returns true if the variable with the given full name is a public output
+func (s *Schema) IsOutput(name string) bool {
+	for _, o := range s.Outputs {
+		if o == name {
+			return true
+		}
+	}
+	return false
 }
 
 // LeafHandler is the handler function that will be called when Visit reaches leafs of the struct
@@ -43,12 +61,13 @@ func Parse(circuit interface{}, tLeaf reflect.Type, handler LeafHandler) (*Schem
 	// same for tLeaf it is in practice always frontend.Variable
 
 	var nbPublic, nbSecret int
-	fields, err := parse(nil, circuit, tLeaf, "", "", "", Unset, handler, &nbPublic, &nbSecret)
+	var outputs []string
+	fields, err := parse(nil, circuit, tLeaf, "", "", "", Unset, false, handler, &nbPublic, &nbSecret, &outputs)
 	if err != nil {
 		return nil, err
 	}
 
-	return &Schema{Fields: fields, NbPublic: nbPublic, NbSecret: nbSecret}, nil
+	return &Schema{Fields: fields, NbPublic: nbPublic, NbSecret: nbSecret, Outputs: outputs}, nil
 }
 
 // Instantiate builds a concrete type using reflect matching the provided schema
@@ -132,7 +151,7 @@ func toStructField(fields []Field, leafType reflect.Type, omitEmpty bool) []refl
 	for i, f := range fields {
 		r[i] = reflect.StructField{
 			Name: f.Name,
-			Tag:  structTag(f.NameTag, f.Visibility, omitEmpty),
+			Tag:  structTag(f.NameTag, f.Visibility, f.Output, omitEmpty),
 		}
 		switch f.Type {
 		case Leaf:
@@ -167,11 +186,15 @@ func arrayElementType(n int, fields []Field, leafType reflect.Type, omitEmpty bo
 	panic("invalid array type")
 }
 
-func structTag(baseNameTag string, visibility Visibility, omitEmpty bool) reflect.StructTag {
+func structTag(baseNameTag string, visibility Visibility, output, omitEmpty bool) reflect.StructTag {
 	sOmitEmpty := ""
 	if omitEmpty {
 		sOmitEmpty = ",omitempty"
 	}
+	sVisibility := visibility.String()
+	if output {
+		sVisibility = string(optOutput)
+	}
 	if visibility == Unset {
 		if baseNameTag != "" {
 			return reflect.StructTag(fmt.Sprintf("gnark:\"%s\" json:\"%s%s\"", baseNameTag, baseNameTag, sOmitEmpty))
@@ -180,17 +203,18 @@ func structTag(baseNameTag string, visibility Visibility, omitEmpty bool) reflec
 	}
 	if baseNameTag == "" {
 		if !omitEmpty {
-			return reflect.StructTag(fmt.Sprintf("gnark:\",%s\"", visibility.String()))
+			return reflect.StructTag(fmt.Sprintf("gnark:\",%s\"", sVisibility))
 		}
-		return reflect.StructTag(fmt.Sprintf("gnark:\",%s\" json:\",omitempty\"", visibility.String()))
+		return reflect.StructTag(fmt.Sprintf("gnark:\",%s\" json:\",omitempty\"", sVisibility))
 	}
-	return reflect.StructTag(fmt.Sprintf("gnark:\"%s,%s\" json:\"%s%s\"", baseNameTag, visibility.String(), baseNameTag, sOmitEmpty))
+	return reflect.StructTag(fmt.Sprintf("gnark:\"%s,%s\" json:\"%s%s\"", baseNameTag, sVisibility, baseNameTag, sOmitEmpty))
 }
 
 // parentFullName: the name of parent with its ancestors separated by "_"
 // parentGoName: the name of parent (Go struct definition)
 // parentTagName: may be empty, set if a struct tag with name is set
-func parse(r []Field, input interface{}, target reflect.Type, parentFullName, parentGoName, parentTagName string, parentVisibility Visibility, handler LeafHandler, nbPublic, nbSecret *int) ([]Field, error) {
+// parentOutput: true if the parent is tagged as an output
+func parse(r []Field, input interface{}, target reflect.Type, parentFullName, parentGoName, parentTagName string, parentVisibility Visibility, parentOutput bool, handler LeafHandler, nbPublic, nbSecret *int, outputs *[]string) ([]Field, error) {
 	tValue := reflect.ValueOf(input)
 
 	// get pointed value if needed
@@ -208,6 +232,9 @@ func parse(r []Field, input interface{}, target reflect.Type, parentFullName, pa
 			(*nbSecret)++
 		} else if v == Public {
 			(*nbPublic)++
+			if parentOutput {
+				*outputs = append(*outputs, parentFullName)
+			}
 		}
 
 		if handler != nil {
@@ -221,6 +248,7 @@ func parse(r []Field, input interface{}, target reflect.Type, parentFullName, pa
 			NameTag:    parentTagName,
 			Type:       Leaf,
 			Visibility: v,
+			Output:     parentOutput,
 		}), nil
 	}
 
@@ -240,6 +268,7 @@ func parse(r []Field, input interface{}, target reflect.Type, parentFullName, pa
 
 			// default visibility is Unset
 			visibility := Unset
+			output := parentOutput
 
 			// variable name is field name, unless overriden by gnark tag value
 			name := f.Name
@@ -253,12 +282,19 @@ func parse(r []Field, input interface{}, target reflect.Type, parentFullName, pa
 					nameTag = ""
 				}
 				opts = tagOptions(strings.TrimSpace(string(opts)))
+				if opts.contains(string(optOutput)) && (opts.contains(string(optPublic)) || opts.contains(string(optSecret))) {
+					return r, fmt.Errorf("invalid gnark struct tag option on %s. \"output\" is public and can't be combined with \"public\" or \"secret\"", getFullName(parentGoName, name, nameTag))
+				}
 				if opts == "" || opts.contains(string(optSecret)) {
 					visibility = Secret
 				} else if opts.contains(string(optPublic)) {
 					visibility = Public
+				} else if opts.contains(string(optOutput)) {
+					// outputs are public variables computed by the circuit
+					visibility = Public
+					output = true
 				} else {
-					return r, fmt.Errorf("invalid gnark struct tag option on %s. must be \"public\", \"secret\" or \"-\"", getFullName(parentGoName, name, nameTag))
+					return r, fmt.Errorf("invalid gnark struct tag option on %s. must be \"public\", \"secret\", \"output\" or \"-\"", getFullName(parentGoName, name, nameTag))
 				}
 			}
 
@@ -278,7 +314,7 @@ func parse(r []Field, input interface{}, target reflect.Type, parentFullName, pa
 			if fValue.CanAddr() && fValue.Addr().CanInterface() {
 				value := fValue.Addr().Interface()
 				var err error
-				subFields, err = parse(subFields, value, target, getFullName(parentFullName, name, nameTag), name, nameTag, visibility, handler, nbPublic, nbSecret)
+				subFields, err = parse(subFields, value, target, getFullName(parentFullName, name, nameTag), name, nameTag, visibility, output, handler, nbPublic, nbSecret, outputs)
 				if err != nil {
 					return r, err
 				}
@@ -303,6 +339,7 @@ func parse(r []Field, input interface{}, target reflect.Type, parentFullName, pa
 			Type:       Struct,
 			SubFields:  subFields,
 			Visibility: parentVisibility, // == Secret,
+			Output:     parentOutput,
 		}), nil
 
 	}
@@ -327,7 +364,7 @@ func parse(r []Field, input interface{}, target reflect.Type, parentFullName, pa
 				val := tValue.Index(j)
 				if val.CanAddr() && val.Addr().CanInterface() {
 					fqn := getFullName(parentFullName, strconv.Itoa(j), "")
-					if _, err := parse(nil, val.Addr().Interface(), target, fqn, fqn, parentTagName, parentVisibility, handler, nbPublic, nbSecret); err != nil {
+					if _, err := parse(nil, val.Addr().Interface(), target, fqn, fqn, parentTagName, parentVisibility, parentOutput, handler, nbPublic, nbSecret, outputs); err != nil {
 						return nil, err
 					}
 				}
@@ -338,6 +375,7 @@ func parse(r []Field, input interface{}, target reflect.Type, parentFullName, pa
 				NameTag:    parentTagName,
 				Type:       Array,
 				Visibility: parentVisibility,
+				Output:     parentOutput,
 				ArraySize:  tValue.Len(),
 			}), nil
 		}
@@ -349,7 +387,7 @@ func parse(r []Field, input interface{}, target reflect.Type, parentFullName, pa
 			val := tValue.Index(j)
 			if val.CanAddr() && val.Addr().CanInterface() {
 				fqn := getFullName(parentFullName, strconv.Itoa(j), "")
-				subFields, err = parse(subFields, val.Addr().Interface(), target, fqn, fqn, parentTagName, parentVisibility, handler, nbPublic, nbSecret)
+				subFields, err = parse(subFields, val.Addr().Interface(), target, fqn, fqn, parentTagName, parentVisibility, parentOutput, handler, nbPublic, nbSecret, outputs)
 				if err != nil {
 					return nil, err
 				}
@@ -365,6 +403,7 @@ func parse(r []Field, input interface{}, target reflect.Type, parentFullName, pa
 			Type:       Array,
 			SubFields:  subFields[:1], // TODO @gbotrel we should ensure that elements are not heterogeneous?
 			Visibility: parentVisibility,
+			Output:     parentOutput,
 			ArraySize:  tValue.Len(),
 		}), nil
 
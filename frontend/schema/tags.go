@@ -22,12 +22,20 @@ import (
 //			Z frontend.Variable `gnark:"-"`
 // 		}
 // it is then the developer responsability to do circuit.Z = circuit.Y in the Define() method
+//
+// the "output" option declares a public variable whose value is computed by the circuit:
+// 		type MyCircuit struct {
+// 			X frontend.Variable
+// 			Y frontend.Variable `gnark:",output"`
+// 		}
+// the Define() method must then assign it, for example circuit.Y = api.Mul(circuit.X, circuit.X)
 type Tag string
 
 const (
 	tagKey    Tag = "gnark"
 	optPublic Tag = "public"
 	optSecret Tag = "secret"
+	optOutput Tag = "output"
 	optOmit   Tag = "-"
 )
 
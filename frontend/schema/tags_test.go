@@ -149,6 +149,39 @@ func TestStructTags(t *testing.T) {
 		t.Run("array", func(t *testing.T) { testParseTags(t, &s, expected) })
 	}
 
+	// outputs
+	{
+		s := struct {
+			A variable
+			B variable `gnark:",output"`
+			C struct {
+				D variable
+			} `gnark:",output"`
+		}{}
+		expected := make(map[string]Visibility)
+		expected["A"] = Secret
+		expected["B"] = Public
+		expected["C_D"] = Public
+		t.Run("output", func(t *testing.T) { testParseTags(t, &s, expected) })
+
+		schema, err := Parse(&s, tVariable, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(schema.Outputs, []string{"B", "C_D"}) {
+			t.Fatal("unexpected outputs", schema.Outputs)
+		}
+
+		// output is public, it can't be combined with another visibility
+		for _, tag := range []string{"public", "secret"} {
+			f := reflect.StructField{Name: "A", Type: tVariable, Tag: reflect.StructTag(`gnark:",` + tag + `,output"`)}
+			invalid := reflect.New(reflect.StructOf([]reflect.StructField{f})).Interface()
+			if _, err := Parse(invalid, tVariable, nil); err == nil {
+				t.Fatal("expected an error for the tag option", tag+",output")
+			}
+		}
+	}
+
 	// slice
 	{
 		s := struct {
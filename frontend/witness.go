@@ -1,8 +1,17 @@
 package frontend
 
 import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+
 	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/frontend/schema"
 )
 
 // NewWitness build an orderded vector of field elements from the given assignment (Circuit)
@@ -29,6 +38,113 @@ func NewWitness(assignment Circuit, curveID ecc.ID, opts ...WitnessOption) (*wit
 	return w, nil
 }
 
+// CompleteWitness builds the full and public witnesses of a circuit with public outputs
+// (see schema.Tag). The outputs of the assignment may be left unassigned; their values
+// are computed by solving the compiled constraint system ccs.
+//
+// Returns an error if the assignment has missing inputs or if the solver fails.
+//
+// CompleteWitness doesn't support circuits compiled with WithPublicInputsHash: their public inputs
+// hash depends on the outputs, which must then be assigned (see PublicInputsHashWitness).
+func CompleteWitness(ccs CompiledConstraintSystem, assignment Circuit, opts ...backend.ProverOption) (full, public *witness.Witness, err error) {
+	if s := ccs.GetSchema(); s != nil && s.PublicInputsHash {
+		return nil, nil, errors.New("can't complete the witness of a circuit compiled with WithPublicInputsHash, assign its outputs and use PublicInputsHashWitness")
+	}
+	s, err := schema.Parse(assignment, tVariable, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// unassigned outputs are temporarily set to 0 to build the witness vector
+	var unassigned []reflect.Value
+	var handler schema.LeafHandler = func(visibility schema.Visibility, name string, tInput reflect.Value) error {
+		if s.IsOutput(name) && tInput.IsNil() && tInput.CanSet() {
+			tInput.Set(reflect.ValueOf(0))
+			unassigned = append(unassigned, tInput)
+		}
+		return nil
+	}
+	if _, err = schema.Parse(assignment, tVariable, handler); err != nil {
+		return nil, nil, err
+	}
+	full, err = NewWitness(assignment, ccs.CurveID())
+	for _, v := range unassigned {
+		v.Set(reflect.Zero(v.Type()))
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err = ccs.CompleteWitness(full, opts...); err != nil {
+		return nil, nil, err
+	}
+	public, err = full.Public()
+	if err != nil {
+		return nil, nil, err
+	}
+	return full, public, nil
+}
+
+// PublicInputsHashWitness returns the full and public witnesses of a circuit compiled with
+// WithPublicInputsHash.
+//
+// fullWitness is the full witness of the original circuit (as returned by NewWitness), and hash
+// the hash of its public part, computed natively with the hash function given to WithPublicInputsHash.
+func PublicInputsHashWitness(fullWitness *witness.Witness, hash *big.Int) (full, public *witness.Witness, err error) {
+	s := fullWitness.Schema
+	if s == nil {
+		return nil, nil, errors.New("missing Schema")
+	}
+	frSize := fullWitness.CurveID.Info().Fr.Bytes
+	data, err := fullWitness.MarshalBinary()
+	if err != nil {
+		return nil, nil, err
+	}
+	n := int(binary.BigEndian.Uint32(data[:4]))
+	if n != s.NbPublic+s.NbSecret {
+		return nil, nil, fmt.Errorf("%w: got %d elements, expected %d (full)", witness.ErrInvalidWitness, n, s.NbPublic+s.NbSecret)
+	}
+	publicVector, secretVector := data[4:4+s.NbPublic*frSize], data[4+s.NbPublic*frSize:]
+
+	// the compiled circuit inputs are [hash | inputs in the order of the circuit fields]
+	var buf bytes.Buffer
+	var bn [4]byte
+	binary.BigEndian.PutUint32(bn[:], uint32(n+1))
+	buf.Write(bn[:])
+
+	var h big.Int
+	h.Mod(hash, fullWitness.CurveID.Info().Fr.Modulus())
+	buf.Write(h.FillBytes(make([]byte, frSize)))
+
+	var handler schema.LeafHandler = func(visibility schema.Visibility, name string, tInput reflect.Value) error {
+		switch visibility {
+		case schema.Public:
+			buf.Write(publicVector[:frSize])
+			publicVector = publicVector[frSize:]
+		case schema.Secret:
+			buf.Write(secretVector[:frSize])
+			secretVector = secretVector[frSize:]
+		}
+		return nil
+	}
+	if _, err = schema.Parse(s.Instantiate(tVariable), tVariable, handler); err != nil {
+		return nil, nil, err
+	}
+
+	full, err = witness.New(fullWitness.CurveID, publicInputsHashSchema(s))
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = full.UnmarshalBinary(buf.Bytes()); err != nil {
+		return nil, nil, err
+	}
+	public, err = full.Public()
+	if err != nil {
+		return nil, nil, err
+	}
+	return full, public, nil
+}
+
 // default options
 func options(opts ...WitnessOption) (witnessConfig, error) {
 	// apply options
@@ -0,0 +1,273 @@
+// Copyright 2022 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zkinterface
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	flatbuffers "github.com/google/flatbuffers/go"
+)
+
+// This file encodes and decodes the subset of the zkInterface messages used by this package, following
+// https://github.com/QED-it/zkinterface/blob/master/zkinterface.fbs:
+//
+//	union Message { CircuitHeader, ConstraintSystem, Witness, Command }
+//
+//	table CircuitHeader {
+//	    instance_variables :Variables;
+//	    free_variable_id   :uint64;
+//	    field_maximum      :[ubyte];
+//	    configuration      :[KeyValue];
+//	}
+//	table ConstraintSystem { constraints :[BilinearConstraint]; info :[KeyValue]; }
+//	table Witness { assigned_variables :Variables; }
+//	table BilinearConstraint { linear_combination_a, linear_combination_b, linear_combination_c :Variables; }
+//	table Variables { variable_ids :[uint64]; values :[ubyte]; info :[KeyValue]; }
+//	table Root { message :Message; }
+//
+//	root_type Root;
+//	file_identifier "zkif";
+//
+// Each message is a flatbuffer prefixed with its size, as a little-endian uint32.
+// Command messages and KeyValue metadata are ignored.
+
+// message types, as in the Message union
+const (
+	messageNone byte = iota
+	messageCircuitHeader
+	messageConstraintSystem
+	messageWitness
+	messageCommand
+)
+
+var fileIdentifier = []byte("zkif")
+
+// maxMessageSize bounds the size of a message, flatbuffers can't address more than 2GB
+const maxMessageSize = 1 << 31
+
+// variables is a list of variable ids and optionally their values, concatenated in little-endian
+type variables struct {
+	ids    []uint64
+	values []byte
+}
+
+type circuitHeader struct {
+	instance       variables
+	freeVariableID uint64
+	fieldMaximum   []byte
+}
+
+type bilinearConstraint struct {
+	a, b, c variables
+}
+
+// message is a decoded zkInterface message; only the field matching kind is set
+type message struct {
+	kind        byte
+	header      *circuitHeader
+	constraints []bilinearConstraint
+	witness     *variables
+}
+
+// messageWriter writes zkInterface messages to w
+type messageWriter struct {
+	w io.Writer
+	b *flatbuffers.Builder
+}
+
+func newMessageWriter(w io.Writer) *messageWriter {
+	return &messageWriter{w: w, b: flatbuffers.NewBuilder(1024)}
+}
+
+func (mw *messageWriter) writeHeader(h *circuitHeader) error {
+	b := mw.b
+	instance := mw.variables(&h.instance)
+	fieldMaximum := b.CreateByteVector(h.fieldMaximum)
+	b.StartObject(4)
+	b.PrependUOffsetTSlot(0, instance, 0)
+	b.PrependUint64Slot(1, h.freeVariableID, 0)
+	b.PrependUOffsetTSlot(2, fieldMaximum, 0)
+	return mw.finish(messageCircuitHeader, b.EndObject())
+}
+
+func (mw *messageWriter) writeConstraintSystem(constraints []bilinearConstraint) error {
+	b := mw.b
+	offsets := make([]flatbuffers.UOffsetT, len(constraints))
+	for i := range constraints {
+		lA := mw.variables(&constraints[i].a)
+		lB := mw.variables(&constraints[i].b)
+		lC := mw.variables(&constraints[i].c)
+		b.StartObject(3)
+		b.PrependUOffsetTSlot(0, lA, 0)
+		b.PrependUOffsetTSlot(1, lB, 0)
+		b.PrependUOffsetTSlot(2, lC, 0)
+		offsets[i] = b.EndObject()
+	}
+	b.StartVector(4, len(offsets), 4)
+	for i := len(offsets) - 1; i >= 0; i-- {
+		b.PrependUOffsetT(offsets[i])
+	}
+	vector := b.EndVector(len(offsets))
+	b.StartObject(2)
+	b.PrependUOffsetTSlot(0, vector, 0)
+	return mw.finish(messageConstraintSystem, b.EndObject())
+}
+
+func (mw *messageWriter) writeWitness(assigned *variables) error {
+	b := mw.b
+	v := mw.variables(assigned)
+	b.StartObject(1)
+	b.PrependUOffsetTSlot(0, v, 0)
+	return mw.finish(messageWitness, b.EndObject())
+}
+
+func (mw *messageWriter) variables(v *variables) flatbuffers.UOffsetT {
+	b := mw.b
+	b.StartVector(8, len(v.ids), 8)
+	for i := len(v.ids) - 1; i >= 0; i-- {
+		b.PrependUint64(v.ids[i])
+	}
+	ids := b.EndVector(len(v.ids))
+	var values flatbuffers.UOffsetT
+	if len(v.values) != 0 {
+		values = b.CreateByteVector(v.values)
+	}
+	b.StartObject(3)
+	b.PrependUOffsetTSlot(0, ids, 0)
+	b.PrependUOffsetTSlot(1, values, 0)
+	return b.EndObject()
+}
+
+// finish wraps msg in a Root table and writes the size-prefixed buffer
+func (mw *messageWriter) finish(kind byte, msg flatbuffers.UOffsetT) error {
+	b := mw.b
+	defer b.Reset()
+	b.StartObject(2)
+	b.PrependByteSlot(0, kind, messageNone)
+	b.PrependUOffsetTSlot(1, msg, 0)
+	b.FinishWithFileIdentifier(b.EndObject(), fileIdentifier)
+
+	buf := b.FinishedBytes()
+	var prefix [4]byte
+	binary.LittleEndian.PutUint32(prefix[:], uint32(len(buf)))
+	if _, err := mw.w.Write(prefix[:]); err != nil {
+		return err
+	}
+	_, err := mw.w.Write(buf)
+	return err
+}
+
+// readMessage reads the next message from r; it returns io.EOF if r has no more messages
+func readMessage(r io.Reader) (*message, error) {
+	var prefix [4]byte
+	if _, err := io.ReadFull(r, prefix[:]); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			err = fmt.Errorf("%w: truncated size prefix", ErrInvalidMessage)
+		}
+		return nil, err
+	}
+	size := binary.LittleEndian.Uint32(prefix[:])
+	if size < 8 || size >= maxMessageSize {
+		return nil, fmt.Errorf("%w: invalid size %d", ErrInvalidMessage, size)
+	}
+	buf := make([]byte, size)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidMessage, err)
+	}
+	if string(buf[4:8]) != string(fileIdentifier) {
+		return nil, fmt.Errorf("%w: missing zkif file identifier", ErrInvalidMessage)
+	}
+	return decodeMessage(buf)
+}
+
+// decodeMessage decodes the Root table in buf; the flatbuffers accessors don't check bounds, so
+// that a malformed buffer is reported as an error instead of a panic
+func decodeMessage(buf []byte) (m *message, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			m, err = nil, fmt.Errorf("%w: %v", ErrInvalidMessage, r)
+		}
+	}()
+
+	root := &flatbuffers.Table{Bytes: buf, Pos: flatbuffers.GetUOffsetT(buf)}
+	m = &message{kind: root.GetByteSlot(4, messageNone)}
+	o := flatbuffers.UOffsetT(root.Offset(6))
+	if o == 0 {
+		m.kind = messageNone
+		return m, nil
+	}
+	t := new(flatbuffers.Table)
+	root.Union(t, o)
+
+	switch m.kind {
+	case messageCircuitHeader:
+		h := new(circuitHeader)
+		if v := subTable(t, 4); v != nil {
+			h.instance = decodeVariables(v)
+		}
+		h.freeVariableID = t.GetUint64Slot(6, 0)
+		if o := flatbuffers.UOffsetT(t.Offset(8)); o != 0 {
+			h.fieldMaximum = t.ByteVector(o + t.Pos)
+		}
+		m.header = h
+	case messageConstraintSystem:
+		if o := flatbuffers.UOffsetT(t.Offset(4)); o != 0 {
+			n := t.VectorLen(o)
+			start := t.Vector(o)
+			m.constraints = make([]bilinearConstraint, n)
+			for i := 0; i < n; i++ {
+				c := &flatbuffers.Table{Bytes: buf, Pos: t.Indirect(start + flatbuffers.UOffsetT(i)*4)}
+				for j, lc := range []*variables{&m.constraints[i].a, &m.constraints[i].b, &m.constraints[i].c} {
+					if v := subTable(c, flatbuffers.VOffsetT(4+2*j)); v != nil {
+						*lc = decodeVariables(v)
+					}
+				}
+			}
+		}
+	case messageWitness:
+		m.witness = new(variables)
+		if v := subTable(t, 4); v != nil {
+			*m.witness = decodeVariables(v)
+		}
+	}
+	return m, nil
+}
+
+// subTable returns the table in the field at vtable offset slot of t, or nil if it is not set
+func subTable(t *flatbuffers.Table, slot flatbuffers.VOffsetT) *flatbuffers.Table {
+	o := flatbuffers.UOffsetT(t.Offset(slot))
+	if o == 0 {
+		return nil
+	}
+	return &flatbuffers.Table{Bytes: t.Bytes, Pos: t.Indirect(o + t.Pos)}
+}
+
+func decodeVariables(t *flatbuffers.Table) variables {
+	var v variables
+	if o := flatbuffers.UOffsetT(t.Offset(4)); o != 0 {
+		n := t.VectorLen(o)
+		start := t.Vector(o)
+		v.ids = make([]uint64, n)
+		for i := range v.ids {
+			v.ids[i] = t.GetUint64(start + flatbuffers.UOffsetT(i)*8)
+		}
+	}
+	if o := flatbuffers.UOffsetT(t.Offset(6)); o != 0 {
+		v.values = t.ByteVector(o + t.Pos)
+	}
+	return v
+}
@@ -0,0 +1,610 @@
+// Copyright 2022 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package zkinterface exports and imports R1CS constraint systems and witnesses in the
+// zkInterface format (https://github.com/QED-it/zkinterface).
+//
+// A circuit is a stream of messages: a CircuitHeader followed by one or more ConstraintSystem
+// messages. A witness is a CircuitHeader holding the values of the instance (public) variables,
+// followed by one or more Witness messages holding the values of the other variables.
+//
+// On export, zkInterface variable ids are the gnark wire ids: 0 is the constant one, public
+// wires are the instance variables, and secret and internal wires are both witness variables.
+//
+// On import, the instance variables become the public wires of the constraint system, in the
+// order of the header, and all the other variables become secret wires, sorted by id.
+// The imported constraint system has no internal wires: the witness must assign every variable,
+// and solving it only checks the constraints. The fields of its schema are named "V<id>".
+package zkinterface
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+	"strconv"
+
+	"github.com/consensys/gnark"
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12377fr "github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	bls12381fr "github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	bls24315fr "github.com/consensys/gnark-crypto/ecc/bls24-315/fr"
+	bn254fr "github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	bw6633fr "github.com/consensys/gnark-crypto/ecc/bw6-633/fr"
+	bw6761fr "github.com/consensys/gnark-crypto/ecc/bw6-761/fr"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/hint"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/compiled"
+	"github.com/consensys/gnark/frontend/cs"
+	"github.com/consensys/gnark/frontend/schema"
+
+	bls12377r1cs "github.com/consensys/gnark/internal/backend/bls12-377/cs"
+	bls12381r1cs "github.com/consensys/gnark/internal/backend/bls12-381/cs"
+	bls24315r1cs "github.com/consensys/gnark/internal/backend/bls24-315/cs"
+	bn254r1cs "github.com/consensys/gnark/internal/backend/bn254/cs"
+	bw6633r1cs "github.com/consensys/gnark/internal/backend/bw6-633/cs"
+	bw6761r1cs "github.com/consensys/gnark/internal/backend/bw6-761/cs"
+
+	bls12377witness "github.com/consensys/gnark/internal/backend/bls12-377/witness"
+	bls12381witness "github.com/consensys/gnark/internal/backend/bls12-381/witness"
+	bls24315witness "github.com/consensys/gnark/internal/backend/bls24-315/witness"
+	bn254witness "github.com/consensys/gnark/internal/backend/bn254/witness"
+	bw6633witness "github.com/consensys/gnark/internal/backend/bw6-633/witness"
+	bw6761witness "github.com/consensys/gnark/internal/backend/bw6-761/witness"
+)
+
+var (
+	// ErrInvalidMessage is returned when a zkInterface message is malformed
+	ErrInvalidMessage = errors.New("invalid zkInterface message")
+
+	// ErrUnsupported is returned for constraint systems or fields that can't be represented
+	ErrUnsupported = errors.New("unsupported by zkInterface")
+)
+
+// maxConstraintsPerMessage bounds the number of constraints in a ConstraintSystem message,
+// to keep messages well under the flatbuffers size limit
+const maxConstraintsPerMessage = 1 << 14
+
+// WriteCircuit writes the header and the constraints of the R1CS ccs to w
+func WriteCircuit(w io.Writer, ccs frontend.CompiledConstraintSystem) error {
+	r1cs, coefficients, err := toR1CS(ccs)
+	if err != nil {
+		return err
+	}
+	modulus := ccs.CurveID().Info().Fr.Modulus()
+	size := ccs.FrSize()
+
+	mw := newMessageWriter(w)
+	if err := mw.writeHeader(newHeader(r1cs, modulus, size)); err != nil {
+		return err
+	}
+
+	values := make([][]byte, len(coefficients))
+	for i := range coefficients {
+		values[i] = encodeValue(&coefficients[i], modulus, size)
+	}
+	toVariables := func(l compiled.LinearExpression) variables {
+		v := variables{
+			ids:    make([]uint64, len(l)),
+			values: make([]byte, 0, len(l)*size),
+		}
+		for i, t := range l {
+			v.ids[i] = uint64(t.WireID())
+			v.values = append(v.values, values[t.CoeffID()]...)
+		}
+		return v
+	}
+
+	constraints := make([]bilinearConstraint, 0, maxConstraintsPerMessage)
+	for i, r1c := range r1cs.Constraints {
+		constraints = append(constraints, bilinearConstraint{
+			a: toVariables(r1c.L),
+			b: toVariables(r1c.R),
+			c: toVariables(r1c.O),
+		})
+		if len(constraints) == maxConstraintsPerMessage || i == len(r1cs.Constraints)-1 {
+			if err := mw.writeConstraintSystem(constraints); err != nil {
+				return err
+			}
+			constraints = constraints[:0]
+		}
+	}
+	return nil
+}
+
+// WriteWitness solves the R1CS ccs with the given full witness and writes the values of all
+// the variables to w: the public wires in the header, and the secret and internal wires in a
+// Witness message.
+func WriteWitness(w io.Writer, ccs frontend.CompiledConstraintSystem, fullWitness *witness.Witness, opts ...backend.ProverOption) error {
+	r1cs, _, err := toR1CS(ccs)
+	if err != nil {
+		return err
+	}
+	solution, err := solve(ccs, fullWitness, opts...)
+	if err != nil {
+		return err
+	}
+	modulus := ccs.CurveID().Info().Fr.Modulus()
+	size := ccs.FrSize()
+
+	header := newHeader(r1cs, modulus, size)
+	header.instance.values = make([]byte, 0, len(header.instance.ids)*size)
+	for _, id := range header.instance.ids {
+		header.instance.values = append(header.instance.values, encodeValue(&solution[id], modulus, size)...)
+	}
+
+	var assigned variables
+	for id := r1cs.NbPublicVariables; id < len(solution); id++ {
+		assigned.ids = append(assigned.ids, uint64(id))
+		assigned.values = append(assigned.values, encodeValue(&solution[id], modulus, size)...)
+	}
+
+	mw := newMessageWriter(w)
+	if err := mw.writeHeader(header); err != nil {
+		return err
+	}
+	return mw.writeWitness(&assigned)
+}
+
+// ReadCircuit reads a circuit header and its constraints from r, and returns the corresponding
+// R1CS, over the curve whose scalar field matches the field_maximum of the header.
+func ReadCircuit(r io.Reader) (frontend.CompiledConstraintSystem, error) {
+	var header *circuitHeader
+	var constraints []bilinearConstraint
+	for {
+		m, err := readMessage(r)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch m.kind {
+		case messageCircuitHeader:
+			if header != nil {
+				return nil, fmt.Errorf("%w: multiple circuit headers", ErrInvalidMessage)
+			}
+			header = m.header
+		case messageConstraintSystem:
+			constraints = append(constraints, m.constraints...)
+		}
+	}
+	if header == nil {
+		return nil, fmt.Errorf("%w: missing circuit header", ErrInvalidMessage)
+	}
+	curveID, err := curveFromFieldMaximum(header.fieldMaximum)
+	if err != nil {
+		return nil, err
+	}
+	modulus := curveID.Info().Fr.Modulus()
+
+	// wire 0 is the constant one, then the instance variables, then the other variables by id
+	wireIDs := map[uint64]int{0: 0}
+	for _, id := range header.instance.ids {
+		if id == 0 || id >= header.freeVariableID {
+			return nil, fmt.Errorf("%w: invalid instance variable id %d", ErrInvalidMessage, id)
+		}
+		if _, ok := wireIDs[id]; ok {
+			return nil, fmt.Errorf("%w: duplicate instance variable id %d", ErrInvalidMessage, id)
+		}
+		wireIDs[id] = len(wireIDs)
+	}
+	nbPublic := len(wireIDs)
+
+	var secret []uint64
+	for i := range constraints {
+		for _, l := range []*variables{&constraints[i].a, &constraints[i].b, &constraints[i].c} {
+			for _, id := range l.ids {
+				if id >= header.freeVariableID {
+					return nil, fmt.Errorf("%w: variable id %d is not below free_variable_id %d", ErrInvalidMessage, id, header.freeVariableID)
+				}
+				if _, ok := wireIDs[id]; !ok {
+					wireIDs[id] = -1
+					secret = append(secret, id)
+				}
+			}
+		}
+	}
+	sort.Slice(secret, func(i, j int) bool { return secret[i] < secret[j] })
+	for i, id := range secret {
+		wireIDs[id] = nbPublic + i
+	}
+
+	res := compiled.R1CS{
+		ConstraintSystem: compiled.ConstraintSystem{
+			NbPublicVariables:  nbPublic,
+			NbSecretVariables:  len(secret),
+			Public:             make([]string, 1, nbPublic),
+			Secret:             make([]string, 0, len(secret)),
+			MDebug:             make(map[int]int),
+			MHints:             make(map[int]*compiled.Hint),
+			MHintsDependencies: make(map[hint.ID]string),
+			CurveID:            curveID,
+		},
+		Constraints: make([]compiled.R1C, len(constraints)),
+	}
+	res.Public[0] = "one"
+	s := &schema.Schema{NbPublic: nbPublic - 1, NbSecret: len(secret)}
+	for _, id := range header.instance.ids {
+		name := variableName(id)
+		res.Public = append(res.Public, name)
+		s.Fields = append(s.Fields, schema.Field{Name: name, NameTag: name, Visibility: schema.Public, Type: schema.Leaf})
+	}
+	for _, id := range secret {
+		name := variableName(id)
+		res.Secret = append(res.Secret, name)
+		s.Fields = append(s.Fields, schema.Field{Name: name, NameTag: name, Visibility: schema.Secret, Type: schema.Leaf})
+	}
+	res.Schema = s
+
+	coeffs := cs.NewCoeffTable()
+	toLinearExpression := func(v *variables) (compiled.LinearExpression, error) {
+		values, err := decodeValues(v, modulus)
+		if err != nil {
+			return nil, err
+		}
+		l := make(compiled.LinearExpression, len(v.ids))
+		for i, id := range v.ids {
+			wireID := wireIDs[id]
+			visibility := schema.Secret
+			if wireID < nbPublic {
+				visibility = schema.Public
+			}
+			coeff := big.NewInt(1)
+			if values != nil {
+				coeff = &values[i]
+			}
+			l[i] = compiled.Pack(wireID, coeffs.CoeffID(coeff), visibility)
+		}
+		return l, nil
+	}
+	for i := range constraints {
+		var err error
+		if res.Constraints[i].L, err = toLinearExpression(&constraints[i].a); err != nil {
+			return nil, err
+		}
+		if res.Constraints[i].R, err = toLinearExpression(&constraints[i].b); err != nil {
+			return nil, err
+		}
+		if res.Constraints[i].O, err = toLinearExpression(&constraints[i].c); err != nil {
+			return nil, err
+		}
+	}
+
+	// all the wires are inputs, so that the constraints are independent
+	if len(res.Constraints) != 0 {
+		level := make([]int, len(res.Constraints))
+		for i := range level {
+			level[i] = i
+		}
+		res.Levels = [][]int{level}
+	}
+
+	switch curveID {
+	case ecc.BLS12_377:
+		return bls12377r1cs.NewR1CS(res, coeffs.Coeffs), nil
+	case ecc.BLS12_381:
+		return bls12381r1cs.NewR1CS(res, coeffs.Coeffs), nil
+	case ecc.BN254:
+		return bn254r1cs.NewR1CS(res, coeffs.Coeffs), nil
+	case ecc.BW6_761:
+		return bw6761r1cs.NewR1CS(res, coeffs.Coeffs), nil
+	case ecc.BW6_633:
+		return bw6633r1cs.NewR1CS(res, coeffs.Coeffs), nil
+	case ecc.BLS24_315:
+		return bls24315r1cs.NewR1CS(res, coeffs.Coeffs), nil
+	default:
+		panic("not implemented")
+	}
+}
+
+// ReadWitness reads a witness from r and returns the full witness of ccs, a constraint system
+// returned by ReadCircuit. Every variable of ccs must be assigned.
+func ReadWitness(r io.Reader, ccs frontend.CompiledConstraintSystem) (*witness.Witness, error) {
+	s := ccs.GetSchema()
+	if s == nil {
+		return nil, fmt.Errorf("%w: constraint system has no schema", ErrUnsupported)
+	}
+	modulus := ccs.CurveID().Info().Fr.Modulus()
+
+	assignment := make(map[uint64]*big.Int)
+	assign := func(v *variables) error {
+		if len(v.ids) != 0 && len(v.values) == 0 {
+			return fmt.Errorf("%w: missing variable values", ErrInvalidMessage)
+		}
+		values, err := decodeValues(v, modulus)
+		if err != nil {
+			return err
+		}
+		for i, id := range v.ids {
+			assignment[id] = &values[i]
+		}
+		return nil
+	}
+	for {
+		m, err := readMessage(r)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch m.kind {
+		case messageCircuitHeader:
+			if len(m.header.instance.values) != 0 {
+				err = assign(&m.header.instance)
+			}
+		case messageWitness:
+			err = assign(m.witness)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	size := ccs.FrSize()
+	nbValues := len(s.Fields)
+	data := make([]byte, 4, 4+nbValues*size)
+	binary.BigEndian.PutUint32(data, uint32(nbValues))
+	for _, f := range s.Fields {
+		id, err := variableID(f.Name)
+		if err != nil {
+			return nil, err
+		}
+		v, ok := assignment[id]
+		if !ok {
+			return nil, fmt.Errorf("%w: variable %d is not assigned", witness.ErrInvalidWitness, id)
+		}
+		data = append(data, v.FillBytes(make([]byte, size))...)
+	}
+
+	w, err := witness.New(ccs.CurveID(), s)
+	if err != nil {
+		return nil, err
+	}
+	if err := w.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// newHeader returns a circuit header with the public wires as instance variables, without values
+func newHeader(r1cs *compiled.R1CS, modulus *big.Int, size int) *circuitHeader {
+	h := &circuitHeader{
+		freeVariableID: uint64(r1cs.NbPublicVariables + r1cs.NbSecretVariables + r1cs.NbInternalVariables),
+	}
+	h.instance.ids = make([]uint64, r1cs.NbPublicVariables-1)
+	for i := range h.instance.ids {
+		h.instance.ids[i] = uint64(i + 1)
+	}
+	fieldMaximum := new(big.Int).Sub(modulus, big.NewInt(1))
+	h.fieldMaximum = encodeValue(fieldMaximum, modulus, size)
+	return h
+}
+
+// encodeValue returns v mod modulus in little-endian, on size bytes
+func encodeValue(v, modulus *big.Int, size int) []byte {
+	var r big.Int
+	b := r.Mod(v, modulus).FillBytes(make([]byte, size))
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+	return b
+}
+
+// decodeValues returns the values of v reduced mod modulus, or nil if v has no values;
+// zkInterface values are little-endian, all of the same size
+func decodeValues(v *variables, modulus *big.Int) ([]big.Int, error) {
+	if len(v.values) == 0 {
+		return nil, nil
+	}
+	if len(v.ids) == 0 || len(v.values)%len(v.ids) != 0 {
+		return nil, fmt.Errorf("%w: %d bytes of values for %d variables", ErrInvalidMessage, len(v.values), len(v.ids))
+	}
+	size := len(v.values) / len(v.ids)
+	res := make([]big.Int, len(v.ids))
+	buf := make([]byte, size)
+	for i := range res {
+		copy(buf, v.values[i*size:(i+1)*size])
+		for k, l := 0, size-1; k < l; k, l = k+1, l-1 {
+			buf[k], buf[l] = buf[l], buf[k]
+		}
+		res[i].SetBytes(buf).Mod(&res[i], modulus)
+	}
+	return res, nil
+}
+
+// curveFromFieldMaximum returns the curve whose scalar field modulus is fieldMaximum + 1
+func curveFromFieldMaximum(fieldMaximum []byte) (ecc.ID, error) {
+	if len(fieldMaximum) == 0 {
+		return ecc.UNKNOWN, fmt.Errorf("%w: missing field_maximum", ErrInvalidMessage)
+	}
+	b := make([]byte, len(fieldMaximum))
+	for i := range b {
+		b[i] = fieldMaximum[len(b)-1-i]
+	}
+	modulus := new(big.Int).SetBytes(b)
+	modulus.Add(modulus, big.NewInt(1))
+	for _, curveID := range gnark.Curves() {
+		if curveID.Info().Fr.Modulus().Cmp(modulus) == 0 {
+			return curveID, nil
+		}
+	}
+	return ecc.UNKNOWN, fmt.Errorf("%w: no curve with scalar field modulus %s", ErrUnsupported, modulus.String())
+}
+
+func variableName(id uint64) string {
+	return "V" + strconv.FormatUint(id, 10)
+}
+
+func variableID(name string) (uint64, error) {
+	if len(name) < 2 || name[0] != 'V' {
+		return 0, fmt.Errorf("%w: field %q is not a zkInterface variable", ErrUnsupported, name)
+	}
+	id, err := strconv.ParseUint(name[1:], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: field %q is not a zkInterface variable", ErrUnsupported, name)
+	}
+	return id, nil
+}
+
+// toR1CS returns the compiled R1CS of ccs and its coefficients
+func toR1CS(ccs frontend.CompiledConstraintSystem) (*compiled.R1CS, []big.Int, error) {
+	var coefficients []big.Int
+	var r1cs *compiled.R1CS
+	switch tccs := ccs.(type) {
+	case *bls12377r1cs.R1CS:
+		r1cs = &tccs.R1CS
+		coefficients = make([]big.Int, len(tccs.Coefficients))
+		for i := range tccs.Coefficients {
+			tccs.Coefficients[i].ToBigIntRegular(&coefficients[i])
+		}
+	case *bls12381r1cs.R1CS:
+		r1cs = &tccs.R1CS
+		coefficients = make([]big.Int, len(tccs.Coefficients))
+		for i := range tccs.Coefficients {
+			tccs.Coefficients[i].ToBigIntRegular(&coefficients[i])
+		}
+	case *bn254r1cs.R1CS:
+		r1cs = &tccs.R1CS
+		coefficients = make([]big.Int, len(tccs.Coefficients))
+		for i := range tccs.Coefficients {
+			tccs.Coefficients[i].ToBigIntRegular(&coefficients[i])
+		}
+	case *bw6761r1cs.R1CS:
+		r1cs = &tccs.R1CS
+		coefficients = make([]big.Int, len(tccs.Coefficients))
+		for i := range tccs.Coefficients {
+			tccs.Coefficients[i].ToBigIntRegular(&coefficients[i])
+		}
+	case *bw6633r1cs.R1CS:
+		r1cs = &tccs.R1CS
+		coefficients = make([]big.Int, len(tccs.Coefficients))
+		for i := range tccs.Coefficients {
+			tccs.Coefficients[i].ToBigIntRegular(&coefficients[i])
+		}
+	case *bls24315r1cs.R1CS:
+		r1cs = &tccs.R1CS
+		coefficients = make([]big.Int, len(tccs.Coefficients))
+		for i := range tccs.Coefficients {
+			tccs.Coefficients[i].ToBigIntRegular(&coefficients[i])
+		}
+	default:
+		return nil, nil, fmt.Errorf("%w: %T is not a R1CS", ErrUnsupported, ccs)
+	}
+	return r1cs, coefficients, nil
+}
+
+// solve solves ccs with the given full witness and returns the values of all the wires
+func solve(ccs frontend.CompiledConstraintSystem, fullWitness *witness.Witness, opts ...backend.ProverOption) ([]big.Int, error) {
+	opt, err := backend.NewProverConfig(opts...)
+	if err != nil {
+		return nil, err
+	}
+	nbConstraints := ccs.GetNbConstraints()
+	var solution []big.Int
+	switch tccs := ccs.(type) {
+	case *bls12377r1cs.R1CS:
+		var a, b, c = make([]bls12377fr.Element, nbConstraints), make([]bls12377fr.Element, nbConstraints), make([]bls12377fr.Element, nbConstraints)
+		v, ok := fullWitness.Vector.(*bls12377witness.Witness)
+		if !ok {
+			return nil, witness.ErrInvalidWitness
+		}
+		wires, err := tccs.Solve(*v, a, b, c, opt)
+		if err != nil {
+			return nil, err
+		}
+		solution = make([]big.Int, len(wires))
+		for i := range wires {
+			wires[i].ToBigIntRegular(&solution[i])
+		}
+	case *bls12381r1cs.R1CS:
+		var a, b, c = make([]bls12381fr.Element, nbConstraints), make([]bls12381fr.Element, nbConstraints), make([]bls12381fr.Element, nbConstraints)
+		v, ok := fullWitness.Vector.(*bls12381witness.Witness)
+		if !ok {
+			return nil, witness.ErrInvalidWitness
+		}
+		wires, err := tccs.Solve(*v, a, b, c, opt)
+		if err != nil {
+			return nil, err
+		}
+		solution = make([]big.Int, len(wires))
+		for i := range wires {
+			wires[i].ToBigIntRegular(&solution[i])
+		}
+	case *bn254r1cs.R1CS:
+		var a, b, c = make([]bn254fr.Element, nbConstraints), make([]bn254fr.Element, nbConstraints), make([]bn254fr.Element, nbConstraints)
+		v, ok := fullWitness.Vector.(*bn254witness.Witness)
+		if !ok {
+			return nil, witness.ErrInvalidWitness
+		}
+		wires, err := tccs.Solve(*v, a, b, c, opt)
+		if err != nil {
+			return nil, err
+		}
+		solution = make([]big.Int, len(wires))
+		for i := range wires {
+			wires[i].ToBigIntRegular(&solution[i])
+		}
+	case *bw6761r1cs.R1CS:
+		var a, b, c = make([]bw6761fr.Element, nbConstraints), make([]bw6761fr.Element, nbConstraints), make([]bw6761fr.Element, nbConstraints)
+		v, ok := fullWitness.Vector.(*bw6761witness.Witness)
+		if !ok {
+			return nil, witness.ErrInvalidWitness
+		}
+		wires, err := tccs.Solve(*v, a, b, c, opt)
+		if err != nil {
+			return nil, err
+		}
+		solution = make([]big.Int, len(wires))
+		for i := range wires {
+			wires[i].ToBigIntRegular(&solution[i])
+		}
+	case *bw6633r1cs.R1CS:
+		var a, b, c = make([]bw6633fr.Element, nbConstraints), make([]bw6633fr.Element, nbConstraints), make([]bw6633fr.Element, nbConstraints)
+		v, ok := fullWitness.Vector.(*bw6633witness.Witness)
+		if !ok {
+			return nil, witness.ErrInvalidWitness
+		}
+		wires, err := tccs.Solve(*v, a, b, c, opt)
+		if err != nil {
+			return nil, err
+		}
+		solution = make([]big.Int, len(wires))
+		for i := range wires {
+			wires[i].ToBigIntRegular(&solution[i])
+		}
+	case *bls24315r1cs.R1CS:
+		var a, b, c = make([]bls24315fr.Element, nbConstraints), make([]bls24315fr.Element, nbConstraints), make([]bls24315fr.Element, nbConstraints)
+		v, ok := fullWitness.Vector.(*bls24315witness.Witness)
+		if !ok {
+			return nil, witness.ErrInvalidWitness
+		}
+		wires, err := tccs.Solve(*v, a, b, c, opt)
+		if err != nil {
+			return nil, err
+		}
+		solution = make([]big.Int, len(wires))
+		for i := range wires {
+			wires[i].ToBigIntRegular(&solution[i])
+		}
+	default:
+		return nil, fmt.Errorf("%w: %T is not a R1CS", ErrUnsupported, ccs)
+	}
+	return solution, nil
+}
@@ -0,0 +1,234 @@
+// Copyright 2022 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zkinterface
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/consensys/gnark"
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/stretchr/testify/require"
+)
+
+type cubicCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+	Z frontend.Variable `gnark:",public"`
+}
+
+// Define declares x**3 + x + 5 == y and z == x / 3, with x < 2**8
+func (c *cubicCircuit) Define(api frontend.API) error {
+	x3 := api.Mul(c.X, c.X, c.X)
+	api.AssertIsEqual(c.Y, api.Add(x3, c.X, 5))
+	api.AssertIsEqual(c.Z, api.Div(c.X, 3))
+	api.ToBinary(c.X, 8)
+	return nil
+}
+
+func TestRoundTrip(t *testing.T) {
+	assert := require.New(t)
+
+	for _, curveID := range gnark.Curves() {
+		ccs, err := frontend.Compile(curveID, r1cs.NewBuilder, &cubicCircuit{})
+		assert.NoError(err)
+
+		var assignment cubicCircuit
+		assignment.X = 3
+		assignment.Y = 35
+		assignment.Z = 1
+		fullWitness, err := frontend.NewWitness(&assignment, curveID)
+		assert.NoError(err)
+
+		var circuitBuf, witnessBuf bytes.Buffer
+		assert.NoError(WriteCircuit(&circuitBuf, ccs))
+		assert.NoError(WriteWitness(&witnessBuf, ccs, fullWitness))
+
+		imported, err := ReadCircuit(&circuitBuf)
+		assert.NoError(err)
+		assert.Equal(curveID, imported.CurveID())
+		assert.Equal(ccs.GetNbConstraints(), imported.GetNbConstraints())
+
+		// internal wires of the exported circuit become secret wires
+		internal, secret, public := ccs.GetNbVariables()
+		_internal, _secret, _public := imported.GetNbVariables()
+		assert.Equal(public, _public)
+		assert.Equal(0, _internal)
+		assert.LessOrEqual(_secret, internal+secret)
+
+		importedWitness, err := ReadWitness(&witnessBuf, imported)
+		assert.NoError(err)
+		assert.NoError(imported.IsSolved(importedWitness))
+
+		publicWitness, err := importedWitness.Public()
+		assert.NoError(err)
+		expectedPublic, err := fullWitness.Public()
+		assert.NoError(err)
+		expected, err := expectedPublic.MarshalBinary()
+		assert.NoError(err)
+		got, err := publicWitness.MarshalBinary()
+		assert.NoError(err)
+		assert.Equal(expected, got, "public witness should be preserved")
+
+		if testing.Short() && curveID != ecc.BN254 {
+			continue
+		}
+		pk, vk, err := groth16.Setup(imported)
+		assert.NoError(err)
+		proof, err := groth16.Prove(imported, pk, importedWitness)
+		assert.NoError(err)
+		assert.NoError(groth16.Verify(proof, vk, publicWitness))
+	}
+}
+
+// testdata/example.zkif is the example of the reference implementation (the "zkif example" command,
+// zkinterface/rust/src/producers/examples.rs): x² + y² = zz with the instance x = 3, y = 4, zz = 25
+// and the witness xx = 9, yy = 16, as a single stream of CircuitHeader, ConstraintSystem and Witness
+// messages. The messages follow the layout of flatc's Rust builders (size-prefixed buffers with the
+// "zkif" identifier, fields stored in the order of the generated create functions, KeyValue
+// configuration, 4 bytes values and 1 byte coefficients). The only difference is the field: the
+// reference example uses the field of order 101, which is not the scalar field of a gnark curve, so the
+// field_maximum of the fixture is the one of BN254.
+func TestReferenceExample(t *testing.T) {
+	assert := require.New(t)
+
+	data, err := os.ReadFile("testdata/example.zkif")
+	assert.NoError(err)
+
+	ccs, err := ReadCircuit(bytes.NewReader(data))
+	assert.NoError(err)
+	assert.Equal(ecc.BN254, ccs.CurveID())
+	assert.Equal(3, ccs.GetNbConstraints())
+	internal, secret, public := ccs.GetNbVariables()
+	assert.Equal(0, internal)
+	assert.Equal(2, secret, "xx, yy")
+	assert.Equal(4, public, "one wire, x, y, zz")
+
+	// the stream carries the witness too: the instance values in the header, and a Witness message
+	w, err := ReadWitness(bytes.NewReader(data), ccs)
+	assert.NoError(err)
+	assert.NoError(ccs.IsSolved(w))
+
+	// zz = 26
+	i := bytes.Index(data, []byte{3, 0, 0, 0, 4, 0, 0, 0, 25, 0, 0, 0})
+	assert.NotEqual(-1, i, "instance values")
+	corrupted := append([]byte{}, data...)
+	corrupted[i+8] = 26
+	w, err = ReadWitness(bytes.NewReader(corrupted), ccs)
+	assert.NoError(err)
+	assert.Error(ccs.IsSolved(w))
+}
+
+func TestInvalidWitness(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254, r1cs.NewBuilder, &cubicCircuit{})
+	assert.NoError(err)
+	var assignment cubicCircuit
+	assignment.X = 3
+	assignment.Y = 35
+	assignment.Z = 1
+	fullWitness, err := frontend.NewWitness(&assignment, ecc.BN254)
+	assert.NoError(err)
+
+	var circuitBuf, witnessBuf bytes.Buffer
+	assert.NoError(WriteCircuit(&circuitBuf, ccs))
+	assert.NoError(WriteWitness(&witnessBuf, ccs, fullWitness))
+	imported, err := ReadCircuit(&circuitBuf)
+	assert.NoError(err)
+
+	// tamper with the value of the last variable
+	var tampered bytes.Buffer
+	mw := newMessageWriter(&tampered)
+	h, err := readMessage(&witnessBuf)
+	assert.NoError(err)
+	assert.NoError(mw.writeHeader(h.header))
+	m, err := readMessage(&witnessBuf)
+	assert.NoError(err)
+	m.witness.values[len(m.witness.values)-1] ^= 1
+	assert.NoError(mw.writeWitness(m.witness))
+	w, err := ReadWitness(bytes.NewReader(tampered.Bytes()), imported)
+	assert.NoError(err)
+	assert.Error(imported.IsSolved(w))
+
+	// drop the Witness message
+	var header bytes.Buffer
+	mw = newMessageWriter(&header)
+	assert.NoError(mw.writeHeader(h.header))
+	_, err = ReadWitness(&header, imported)
+	assert.Error(err)
+}
+
+func TestInvalidMessages(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254, r1cs.NewBuilder, &cubicCircuit{})
+	assert.NoError(err)
+	var buf bytes.Buffer
+	assert.NoError(WriteCircuit(&buf, ccs))
+	data := buf.Bytes()
+
+	// truncated stream
+	_, err = ReadCircuit(bytes.NewReader(data[:len(data)-3]))
+	assert.True(errors.Is(err, ErrInvalidMessage), err)
+
+	// wrong file identifier
+	corrupted := append([]byte{}, data...)
+	copy(corrupted[8:12], "abcd")
+	_, err = ReadCircuit(bytes.NewReader(corrupted))
+	assert.True(errors.Is(err, ErrInvalidMessage), err)
+
+	// corrupted offsets must not panic
+	for i := 12; i < 64 && i < len(data); i++ {
+		corrupted := append([]byte{}, data...)
+		corrupted[i] ^= 0xff
+		assert.NotPanics(func() { _, _ = ReadCircuit(bytes.NewReader(corrupted)) })
+	}
+
+	// empty stream
+	_, err = ReadCircuit(bytes.NewReader(nil))
+	assert.True(errors.Is(err, ErrInvalidMessage), err)
+
+	// unknown field
+	var header bytes.Buffer
+	assert.NoError(newMessageWriter(&header).writeHeader(&circuitHeader{freeVariableID: 1, fieldMaximum: []byte{6}}))
+	_, err = ReadCircuit(&header)
+	assert.True(errors.Is(err, ErrUnsupported), err)
+
+	// variable ids must be below the free variable id
+	modulus := ecc.BN254.Info().Fr.Modulus()
+	fieldMaximum := encodeValue(new(big.Int).Sub(modulus, big.NewInt(1)), modulus, 32)
+	header.Reset()
+	mw := newMessageWriter(&header)
+	assert.NoError(mw.writeHeader(&circuitHeader{freeVariableID: 2, fieldMaximum: fieldMaximum}))
+	assert.NoError(mw.writeConstraintSystem([]bilinearConstraint{{a: variables{ids: []uint64{5}}}}))
+	_, err = ReadCircuit(&header)
+	assert.True(errors.Is(err, ErrInvalidMessage), err)
+}
+
+func TestSparseR1CSUnsupported(t *testing.T) {
+	ccs, err := frontend.Compile(ecc.BN254, scs.NewBuilder, &cubicCircuit{})
+	require.NoError(t, err)
+	err = WriteCircuit(io.Discard, ccs)
+	require.True(t, errors.Is(err, ErrUnsupported), err)
+}
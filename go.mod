@@ -6,6 +6,7 @@ require (
 	github.com/consensys/bavard v0.1.10
 	github.com/consensys/gnark-crypto v0.7.0
 	github.com/fxamacker/cbor/v2 v2.2.0
+	github.com/google/flatbuffers v1.11.0
 	github.com/leanovate/gopter v0.2.9
 	github.com/rs/zerolog v1.26.1
 	github.com/stretchr/testify v1.7.1
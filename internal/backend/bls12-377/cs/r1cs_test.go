@@ -18,13 +18,22 @@ package cs_test
 
 import (
 	"bytes"
+	"encoding/json"
 	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/hint"
 	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/compiled"
 	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/frontend/schema"
 	"github.com/consensys/gnark/internal/backend/circuits"
+	"math/big"
 	"reflect"
 	"testing"
 
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+
 	"github.com/consensys/gnark/internal/backend/bls12-377/cs"
 )
 
@@ -73,6 +82,56 @@ func TestSerialization(t *testing.T) {
 				}
 			}
 
+			// json round trip
+			{
+				data, err := json.Marshal(r1cs1)
+				if err != nil {
+					t.Fatal(err)
+				}
+				var reconstructed cs.R1CS
+				if err := json.Unmarshal(data, &reconstructed); err != nil {
+					t.Fatal(err)
+				}
+				if !reflect.DeepEqual(r1cs1, &reconstructed) {
+					t.Fatal("round trip json serialization failed")
+				}
+
+				// each hint carries the name of its function
+				var named struct {
+					MHints map[int]struct {
+						ID   hint.ID
+						Name string
+					}
+				}
+				if err := json.Unmarshal(data, &named); err != nil {
+					t.Fatal(err)
+				}
+				if len(named.MHints) != len(reconstructed.MHints) {
+					t.Fatal("json encoding is missing hints")
+				}
+				for wireID, h := range named.MHints {
+					if h.Name == "" || h.Name != reconstructed.MHintsDependencies[h.ID] {
+						t.Fatalf("hint of wire %d: expected name %q, got %q", wireID, reconstructed.MHintsDependencies[h.ID], h.Name)
+					}
+				}
+
+				spr, err := frontend.Compile(ecc.BLS12_377, scs.NewBuilder, tc.Circuit)
+				if err != nil {
+					t.Fatal(err)
+				}
+				data, err = json.Marshal(spr)
+				if err != nil {
+					t.Fatal(err)
+				}
+				var reconstructedSparse cs.SparseR1CS
+				if err := json.Unmarshal(data, &reconstructedSparse); err != nil {
+					t.Fatal(err)
+				}
+				if !reflect.DeepEqual(spr, &reconstructedSparse) {
+					t.Fatal("round trip json serialization failed (sparse)")
+				}
+			}
+
 			// ensure determinism in compilation / serialization / reconstruction
 			{
 				buffer.Reset()
@@ -152,3 +211,41 @@ func BenchmarkSolve(b *testing.B) {
 		_ = ccs.IsSolved(witness)
 	}
 }
+
+func TestSolveSparseR1CSForR(t *testing.T) {
+	// X + Y - 5 == 0, and Z - Y == 0 with a zero-coefficient L term on the unsolved wire Z:
+	// both constraints are solved for their R wire
+	var ccs compiled.SparseR1CS
+	ccs.NbPublicVariables = 1
+	ccs.NbInternalVariables = 2
+	x := compiled.Pack(0, compiled.CoeffIdOne, schema.Public)
+	y := compiled.Pack(1, compiled.CoeffIdOne, schema.Internal)
+	z := compiled.Pack(2, compiled.CoeffIdOne, schema.Internal)
+	ccs.Constraints = []compiled.SparseR1C{
+		{L: x, R: y, K: 4},
+		{L: compiled.Pack(2, compiled.CoeffIdZero, schema.Internal), R: z, O: compiled.Pack(1, compiled.CoeffIdMinusOne, schema.Internal)},
+	}
+	ccs.Levels = [][]int{{0}, {1}}
+	coefficients := make([]big.Int, 5)
+	coefficients[1].SetInt64(1)
+	coefficients[2].SetInt64(2)
+	coefficients[3].SetInt64(-1)
+	coefficients[4].SetInt64(-5)
+	spr := cs.NewSparseR1CS(ccs, coefficients)
+
+	opt, err := backend.NewProverConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var w fr.Element
+	w.SetUint64(3)
+	solution, err := spr.Solve([]fr.Element{w}, opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var expected fr.Element
+	expected.SetUint64(2)
+	if !solution[0].Equal(&w) || !solution[1].Equal(&expected) || !solution[2].Equal(&expected) {
+		t.Fatal("unexpected solution", solution)
+	}
+}
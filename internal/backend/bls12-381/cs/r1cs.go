@@ -17,6 +17,7 @@
 package cs
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/fxamacker/cbor/v2"
@@ -67,6 +68,12 @@ func NewR1CS(cs compiled.R1CS, coefficients []big.Int) *R1CS {
 // witness = [publicWires | secretWires] (without the ONE_WIRE !)
 // returns  [publicWires | secretWires | internalWires ]
 func (cs *R1CS) Solve(witness, a, b, c []fr.Element, opt backend.ProverConfig) ([]fr.Element, error) {
+	return cs.solve(witness, a, b, c, opt, false)
+}
+
+// solve implements Solve; if completeOutputs is set, the values of the public outputs in the witness
+// are ignored and computed by the solver instead.
+func (cs *R1CS) solve(witness, a, b, c []fr.Element, opt backend.ProverConfig, completeOutputs bool) ([]fr.Element, error) {
 	log := logger.Logger().With().Str("curve", cs.CurveID().String()).Int("nbConstraints", len(cs.Constraints)).Str("backend", "groth16").Logger()
 
 	nbWires := cs.NbPublicVariables + cs.NbSecretVariables + cs.NbInternalVariables
@@ -100,6 +107,15 @@ func (cs *R1CS) Solve(witness, a, b, c []fr.Element, opt backend.ProverConfig) (
 	// we instantiated all wires
 	solution.nbSolved += uint64(len(witness) + 1)
 
+	if completeOutputs {
+		// public outputs are solved by the constraints tying them to the circuit computation
+		outputs := cs.OutputWires()
+		for _, wID := range outputs {
+			solution.solved[wID] = false
+		}
+		solution.nbSolved -= uint64(len(outputs))
+	}
+
 	// now that we know all inputs are set, defer log printing once all solution.values are computed
 	// (or sooner, if a constraint is not satisfied)
 	defer solution.printLogs(opt.CircuitLogger, cs.Logs)
@@ -253,6 +269,28 @@ func (cs *R1CS) IsSolved(witness *witness.Witness, opts ...backend.ProverOption)
 	return err
 }
 
+// CompleteWitness solves the R1CS with the given full witness and sets the values of the
+// public outputs (see frontend.Compile) in the witness vector
+func (cs *R1CS) CompleteWitness(witness *witness.Witness, opts ...backend.ProverOption) error {
+	opt, err := backend.NewProverConfig(opts...)
+	if err != nil {
+		return err
+	}
+
+	a := make([]fr.Element, len(cs.Constraints))
+	b := make([]fr.Element, len(cs.Constraints))
+	c := make([]fr.Element, len(cs.Constraints))
+	v := witness.Vector.(*bls12_381witness.Witness)
+	solution, err := cs.solve(*v, a, b, c, opt, true)
+	if err != nil {
+		return err
+	}
+	for _, wID := range cs.OutputWires() {
+		(*v)[wID-1] = solution[wID] // the witness doesn't contain the ONE_WIRE
+	}
+	return nil
+}
+
 // divByCoeff sets res = res / t.Coeff
 func (cs *R1CS) divByCoeff(res *fr.Element, t compiled.Term) {
 	cID := t.CoeffID()
@@ -493,3 +531,21 @@ func (cs *R1CS) ReadFrom(r io.Reader) (int64, error) {
 
 	return int64(decoder.NumBytesRead()), nil
 }
+
+// MarshalJSON returns a human-readable JSON encoding of R1CS: the constraints, whose terms are encoded as
+// "c<coeffID>*<visibility><wireID>" (see compiled.Term), the coefficients in decimal, the schema and the
+// hints, each one carrying the name of its function (see compiled.NamedHint).
+// The encoding is lossless, UnmarshalJSON reads it back.
+func (cs *R1CS) MarshalJSON() ([]byte, error) {
+	type r1cs R1CS // r1cs doesn't implement json.Marshaler
+	return json.Marshal(struct {
+		*r1cs
+		MHints map[int]compiled.NamedHint // shadows the embedded MHints
+	}{(*r1cs)(cs), cs.NamedHints()})
+}
+
+// UnmarshalJSON reads R1CS from its JSON encoding (see MarshalJSON)
+func (cs *R1CS) UnmarshalJSON(data []byte) error {
+	type r1cs R1CS // r1cs doesn't implement json.Unmarshaler
+	return json.Unmarshal(data, (*r1cs)(cs))
+}
@@ -0,0 +1,64 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package groth16
+
+import (
+	"io"
+
+	curve "github.com/consensys/gnark-crypto/ecc/bls12-381"
+)
+
+// The Proof and VerifyingKey encodings (WriteTo, WriteRawTo, ReadFrom) already follow bellman,
+// which uses the zcash encoding of BLS12-381 points: the proof is compressed and bellman writes
+// its VerifyingKey uncompressed (WriteRawTo).
+
+// WriteBellmanTo writes the key and its VerifyingKey vk as bellman Parameters:
+// https://github.com/zkcrypto/bellman/blob/fa9be45588227a8c6ec34957de3f68705f07bd92/src/groth16/mod.rs
+// vk,uint32(len(H)),[H(t)]1,uint32(len(Kpk)),[Kpk(t)]1,uint32(len(A)),[A(t)]1,uint32(len(B)),[B(t)]1,uint32(len(B)),[B(t)]2
+// with uncompressed points. As in bellman, the points at infinity are not part of [A(t)] and [B(t)],
+// and [H(t)]1 holds the n-1 first [tⁱ·Z(t)/δ]1 in natural order.
+//
+// bellman reduces the R1CS to a QAP with additional constraints for the public inputs, so a
+// bellman prover can't use a key generated by gnark.
+func (pk *ProvingKey) WriteBellmanTo(w io.Writer, vk *VerifyingKey) (int64, error) {
+	n, err := vk.WriteRawTo(w)
+	if err != nil {
+		return n, err
+	}
+
+	h := make([]curve.G1Affine, len(pk.G1.Z))
+	copy(h, pk.G1.Z)
+	bitReverse(h)
+	if len(h) > 0 {
+		h = h[:len(h)-1]
+	}
+
+	enc := curve.NewEncoder(w, curve.RawEncoding())
+	toEncode := []interface{}{
+		h,
+		pk.G1.K,
+		pk.G1.A,
+		pk.G1.B,
+		pk.G2.B,
+	}
+
+	for _, v := range toEncode {
+		if err := enc.Encode(v); err != nil {
+			return n + enc.BytesWritten(), err
+		}
+	}
+
+	return n + enc.BytesWritten(), nil
+}
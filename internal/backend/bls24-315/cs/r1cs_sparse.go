@@ -17,6 +17,7 @@
 package cs
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/consensys/gnark-crypto/ecc"
@@ -66,6 +67,12 @@ func NewSparseR1CS(ccs compiled.SparseR1CS, coefficients []big.Int) *SparseR1CS
 // witness: contains the input variables
 // it returns the full slice of wires
 func (cs *SparseR1CS) Solve(witness []fr.Element, opt backend.ProverConfig) ([]fr.Element, error) {
+	return cs.solve(witness, opt, false)
+}
+
+// solve implements Solve; if completeOutputs is set, the values of the public outputs in the witness
+// are ignored and computed by the solver instead.
+func (cs *SparseR1CS) solve(witness []fr.Element, opt backend.ProverConfig, completeOutputs bool) ([]fr.Element, error) {
 	log := logger.Logger().With().Str("curve", cs.CurveID().String()).Int("nbConstraints", len(cs.Constraints)).Str("backend", "plonk").Logger()
 
 	// set the slices holding the solution.values and monitoring which variables have been solved
@@ -100,6 +107,15 @@ func (cs *SparseR1CS) Solve(witness []fr.Element, opt backend.ProverConfig) ([]f
 	// we instantiated all wires
 	solution.nbSolved += uint64(len(witness))
 
+	if completeOutputs {
+		// public outputs are solved by the constraints tying them to the circuit computation
+		outputs := cs.OutputWires()
+		for _, wID := range outputs {
+			solution.solved[wID] = false
+		}
+		solution.nbSolved -= uint64(len(outputs))
+	}
+
 	// defer log printing once all solution.values are computed
 	defer solution.printLogs(opt.CircuitLogger, cs.Logs)
 
@@ -303,7 +319,7 @@ func (cs *SparseR1CS) solveConstraint(c compiled.SparseR1C, solution *solution,
 		return nil
 	}
 	if lro == 1 { // we solve for R: u1L+u2R+u3LR+u4O+k=0 => R(u2+u3L)+u1L+u4O+k = 0
-		if !solution.solved[c.L.WireID()] {
+		if (c.L.CoeffID() != 0 || c.M[0].CoeffID() != 0) && !solution.solved[c.L.WireID()] {
 			panic("L wire should be instantiated when we solve R")
 		}
 		var u1, u2, u3, den, num, v1, v2 fr.Element
@@ -318,12 +334,12 @@ func (cs *SparseR1CS) solveConstraint(c compiled.SparseR1C, solution *solution,
 
 		// TODO find a way to do lazy div (/ batch inversion)
 		num.Div(&num, &den).Neg(&num)
-		solution.set(c.L.WireID(), num)
+		solution.set(c.R.WireID(), num)
 		return nil
 	}
 
 	if lro == 0 { // we solve for L: u1L+u2R+u3LR+u4O+k=0 => L(u1+u3R)+u2R+u4O+k = 0
-		if !solution.solved[c.R.WireID()] {
+		if (c.R.CoeffID() != 0 || c.M[1].CoeffID() != 0) && !solution.solved[c.R.WireID()] {
 			panic("R wire should be instantiated when we solve L")
 		}
 		var u1, u2, u3, den, num, v1, v2 fr.Element
@@ -373,6 +389,25 @@ func (cs *SparseR1CS) IsSolved(witness *witness.Witness, opts ...backend.ProverO
 	return err
 }
 
+// CompleteWitness solves the SparseR1CS with the given full witness and sets the values of the
+// public outputs (see frontend.Compile) in the witness vector
+func (cs *SparseR1CS) CompleteWitness(witness *witness.Witness, opts ...backend.ProverOption) error {
+	opt, err := backend.NewProverConfig(opts...)
+	if err != nil {
+		return err
+	}
+
+	v := witness.Vector.(*bls24_315witness.Witness)
+	solution, err := cs.solve(*v, opt, true)
+	if err != nil {
+		return err
+	}
+	for _, wID := range cs.OutputWires() {
+		(*v)[wID] = solution[wID]
+	}
+	return nil
+}
+
 // GetConstraints return a list of constraint formatted as in the paper
 // https://eprint.iacr.org/2019/953.pdf section 6 such that
 // qL⋅xa + qR⋅xb + qO⋅xc + qM⋅(xaxb) + qC == 0
@@ -534,3 +569,21 @@ func (cs *SparseR1CS) ReadFrom(r io.Reader) (int64, error) {
 	err = decoder.Decode(cs)
 	return int64(decoder.NumBytesRead()), err
 }
+
+// MarshalJSON returns a human-readable JSON encoding of SparseR1CS: the constraints, whose terms are encoded as
+// "c<coeffID>*<visibility><wireID>" (see compiled.Term), the coefficients in decimal, the schema and the
+// hints, each one carrying the name of its function (see compiled.NamedHint).
+// The encoding is lossless, UnmarshalJSON reads it back.
+func (cs *SparseR1CS) MarshalJSON() ([]byte, error) {
+	type sparseR1CS SparseR1CS // sparseR1CS doesn't implement json.Marshaler
+	return json.Marshal(struct {
+		*sparseR1CS
+		MHints map[int]compiled.NamedHint // shadows the embedded MHints
+	}{(*sparseR1CS)(cs), cs.NamedHints()})
+}
+
+// UnmarshalJSON reads SparseR1CS from its JSON encoding (see MarshalJSON)
+func (cs *SparseR1CS) UnmarshalJSON(data []byte) error {
+	type sparseR1CS SparseR1CS // sparseR1CS doesn't implement json.Unmarshaler
+	return json.Unmarshal(data, (*sparseR1CS)(cs))
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr/fft"
 
 	"bytes"
+	"encoding/json"
 	"math/big"
 	"reflect"
 
@@ -39,7 +40,7 @@ func TestProofSerialization(t *testing.T) {
 
 	properties.Property("Proof -> writer -> reader -> Proof should stay constant", prop.ForAll(
 		func(ar, krs curve.G1Affine, bs curve.G2Affine) bool {
-			var proof, pCompressed, pRaw Proof
+			var proof, pCompressed, pRaw, pJSON Proof
 
 			// create a random proof
 			proof.Ar = ar
@@ -76,7 +77,15 @@ func TestProofSerialization(t *testing.T) {
 				return false
 			}
 
-			return reflect.DeepEqual(&proof, &pCompressed) && reflect.DeepEqual(&proof, &pRaw)
+			data, err := json.Marshal(&proof)
+			if err != nil {
+				return false
+			}
+			if err := json.Unmarshal(data, &pJSON); err != nil {
+				return false
+			}
+
+			return reflect.DeepEqual(&proof, &pCompressed) && reflect.DeepEqual(&proof, &pRaw) && reflect.DeepEqual(&proof, &pJSON)
 		},
 		GenG1(),
 		GenG1(),
@@ -94,7 +103,7 @@ func TestVerifyingKeySerialization(t *testing.T) {
 
 	properties.Property("VerifyingKey -> writer -> reader -> VerifyingKey should stay constant", prop.ForAll(
 		func(p1 curve.G1Affine, p2 curve.G2Affine) bool {
-			var vk, vkCompressed, vkRaw VerifyingKey
+			var vk, vkCompressed, vkRaw, vkJSON VerifyingKey
 
 			// create a random vk
 			nbWires := 6
@@ -157,7 +166,17 @@ func TestVerifyingKeySerialization(t *testing.T) {
 				return false
 			}
 
-			return reflect.DeepEqual(&vk, &vkCompressed) && reflect.DeepEqual(&vk, &vkRaw)
+			data, err := json.Marshal(&vk)
+			if err != nil {
+				t.Log(err)
+				return false
+			}
+			if err := json.Unmarshal(data, &vkJSON); err != nil {
+				t.Log(err)
+				return false
+			}
+
+			return reflect.DeepEqual(&vk, &vkCompressed) && reflect.DeepEqual(&vk, &vkRaw) && reflect.DeepEqual(&vk, &vkJSON)
 		},
 		GenG1(),
 		GenG2(),
@@ -238,7 +257,24 @@ func TestProvingKeySerialization(t *testing.T) {
 				return false
 			}
 
-			return reflect.DeepEqual(&pk, &pkCompressed) && reflect.DeepEqual(&pk, &pkRaw)
+			// raw layout, read back without copy
+			var bufDump bytes.Buffer
+			written, err = pk.WriteDump(&bufDump)
+			if err != nil {
+				t.Log(err)
+				return false
+			}
+			if written != int64(bufDump.Len()) {
+				t.Log("dump written != buffer length")
+				return false
+			}
+			var pkDump ProvingKey
+			if err := pkDump.ReadDump(bufDump.Bytes()); err != nil {
+				t.Log(err)
+				return false
+			}
+
+			return reflect.DeepEqual(&pk, &pkCompressed) && reflect.DeepEqual(&pk, &pkRaw) && reflect.DeepEqual(&pk, &pkDump)
 		},
 		GenG1(),
 		GenG2(),
@@ -19,14 +19,37 @@ package plonk
 import (
 	curve "github.com/consensys/gnark-crypto/ecc/bls24-315"
 
-	"errors"
 	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr"
+
+	"errors"
+	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr/fft"
 	"io"
 )
 
 // WriteTo writes binary encoding of Proof to w
+// points are stored in compressed form
+// use WriteRawTo(...) to encode the proof without point compression
 func (proof *Proof) WriteTo(w io.Writer) (int64, error) {
-	enc := curve.NewEncoder(w)
+	return proof.writeTo(w, false)
+}
+
+// WriteRawTo writes binary encoding of Proof to w
+// points are stored in uncompressed form
+// use WriteTo(...) to encode the proof with point compression
+func (proof *Proof) WriteRawTo(w io.Writer) (int64, error) {
+	return proof.writeTo(w, true)
+}
+
+// writeTo serialization format:
+// LRO | Z | H | BatchedProof.H | BatchedProof.ClaimedValues | ZShiftedOpening.H | ZShiftedOpening.ClaimedValue
+// the opening proofs are encoded field by field, since kzg only provides the compressed encoding
+func (proof *Proof) writeTo(w io.Writer, raw bool) (int64, error) {
+	var enc *curve.Encoder
+	if raw {
+		enc = curve.NewEncoder(w, curve.RawEncoding())
+	} else {
+		enc = curve.NewEncoder(w)
+	}
 
 	toEncode := []interface{}{
 		&proof.LRO[0],
@@ -36,6 +59,10 @@ func (proof *Proof) WriteTo(w io.Writer) (int64, error) {
 		&proof.H[0],
 		&proof.H[1],
 		&proof.H[2],
+		&proof.BatchedProof.H,
+		proof.BatchedProof.ClaimedValues,
+		&proof.ZShiftedOpening.H,
+		&proof.ZShiftedOpening.ClaimedValue,
 	}
 
 	for _, v := range toEncode {
@@ -44,18 +71,23 @@ func (proof *Proof) WriteTo(w io.Writer) (int64, error) {
 		}
 	}
 
-	n, err := proof.BatchedProof.WriteTo(w)
-	if err != nil {
-		return n + enc.BytesWritten(), err
-	}
-	n2, err := proof.ZShiftedOpening.WriteTo(w)
-
-	return n + n2 + enc.BytesWritten(), err
+	return enc.BytesWritten(), nil
 }
 
 // ReadFrom reads binary representation of Proof from r
+// Proof must be encoded through WriteTo (compressed) or WriteRawTo (uncompressed)
 func (proof *Proof) ReadFrom(r io.Reader) (int64, error) {
-	dec := curve.NewDecoder(r)
+	return proof.readFrom(r)
+}
+
+// UnsafeReadFrom has the same behavior as ReadFrom, except that it will not check that decode points
+// are on the curve and in the correct subgroup.
+func (proof *Proof) UnsafeReadFrom(r io.Reader) (int64, error) {
+	return proof.readFrom(r, curve.NoSubgroupChecks())
+}
+
+func (proof *Proof) readFrom(r io.Reader, decOptions ...func(*curve.Decoder)) (int64, error) {
+	dec := curve.NewDecoder(r, decOptions...)
 	toDecode := []interface{}{
 		&proof.LRO[0],
 		&proof.LRO[1],
@@ -64,6 +96,10 @@ func (proof *Proof) ReadFrom(r io.Reader) (int64, error) {
 		&proof.H[0],
 		&proof.H[1],
 		&proof.H[2],
+		&proof.BatchedProof.H,
+		&proof.BatchedProof.ClaimedValues,
+		&proof.ZShiftedOpening.H,
+		&proof.ZShiftedOpening.ClaimedValue,
 	}
 
 	for _, v := range toDecode {
@@ -72,18 +108,31 @@ func (proof *Proof) ReadFrom(r io.Reader) (int64, error) {
 		}
 	}
 
-	n, err := proof.BatchedProof.ReadFrom(r)
-	if err != nil {
-		return n + dec.BytesRead(), err
-	}
-	n2, err := proof.ZShiftedOpening.ReadFrom(r)
-	return n + n2 + dec.BytesRead(), err
+	return dec.BytesRead(), nil
 }
 
 // WriteTo writes binary encoding of ProvingKey to w
+// points are compressed, and the evaluations of the permutation on the big domain are
+// recomputed when reading the key
+// use WriteRawTo(...) to encode the key without point compression
 func (pk *ProvingKey) WriteTo(w io.Writer) (n int64, err error) {
+	return pk.writeTo(w, false)
+}
+
+// WriteRawTo writes binary encoding of ProvingKey to w
+// points are not compressed, and the evaluations of the permutation on the big domain are
+// serialized, so that reading the key doesn't need to recompute them
+// use WriteTo(...) to encode the key with point compression
+func (pk *ProvingKey) WriteRawTo(w io.Writer) (n int64, err error) {
+	return pk.writeTo(w, true)
+}
+
+// writeTo serialization format:
+// Vk | Domain[0] | Domain[1] | Ql | Qr | Qm | Qo | CQk | LQk | S1Canonical | S2Canonical | S3Canonical |
+// Permutation [| EvaluationPermutationBigDomainBitReversed (raw only)]
+func (pk *ProvingKey) writeTo(w io.Writer, raw bool) (n int64, err error) {
 	// encode the verifying key
-	n, err = pk.Vk.WriteTo(w)
+	n, err = pk.Vk.writeTo(w, raw)
 	if err != nil {
 		return
 	}
@@ -106,7 +155,13 @@ func (pk *ProvingKey) WriteTo(w io.Writer) (n int64, err error) {
 		return n, errors.New("invalid permutation size, expected 3*domain cardinality")
 	}
 
-	enc := curve.NewEncoder(w)
+	var enc *curve.Encoder
+	if raw {
+		enc = curve.NewEncoder(w, curve.RawEncoding())
+	} else {
+		enc = curve.NewEncoder(w)
+	}
+
 	// note: type Polynomial, which is handled by default binary.Write(...) op and doesn't
 	// encode the size (nor does it convert from Montgomery to Regular form)
 	// so we explicitly transmit []fr.Element
@@ -122,6 +177,9 @@ func (pk *ProvingKey) WriteTo(w io.Writer) (n int64, err error) {
 		([]fr.Element)(pk.S3Canonical),
 		pk.Permutation,
 	}
+	if raw {
+		toEncode = append(toEncode, pk.EvaluationPermutationBigDomainBitReversed)
+	}
 
 	for _, v := range toEncode {
 		if err := enc.Encode(v); err != nil {
@@ -133,12 +191,30 @@ func (pk *ProvingKey) WriteTo(w io.Writer) (n int64, err error) {
 }
 
 // ReadFrom reads from binary representation in r into ProvingKey
+// ProvingKey must be encoded through WriteTo (compressed) or WriteRawTo (uncompressed)
 func (pk *ProvingKey) ReadFrom(r io.Reader) (int64, error) {
+	return pk.readFrom(r)
+}
+
+// UnsafeReadFrom has the same behavior as ReadFrom, except that it will not check that decode points
+// are on the curve and in the correct subgroup.
+func (pk *ProvingKey) UnsafeReadFrom(r io.Reader) (int64, error) {
+	return pk.readFrom(r, curve.NoSubgroupChecks())
+}
+
+// rawVerifyingKeySize is the size of the encoding of a VerifyingKey through WriteRawTo:
+// Size | SizeInv | Generator | NbPublicVariables | S[0..2], Ql, Qr, Qm, Qo, Qk uncompressed
+const rawVerifyingKeySize = 8 + 2*fr.Bytes + 8 + 8*curve.SizeOfG1AffineUncompressed
+
+func (pk *ProvingKey) readFrom(r io.Reader, decOptions ...func(*curve.Decoder)) (int64, error) {
 	pk.Vk = &VerifyingKey{}
-	n, err := pk.Vk.ReadFrom(r)
+	n, err := pk.Vk.readFrom(r, decOptions...)
 	if err != nil {
 		return n, err
 	}
+	// the points of the verifying key are uncompressed if and only if the key was encoded through
+	// WriteRawTo, which is then followed by the evaluations of the permutation on the big domain
+	raw := n == rawVerifyingKeySize
 
 	n2, err := pk.Domain[0].ReadFrom(r)
 	n += n2
@@ -154,7 +230,7 @@ func (pk *ProvingKey) ReadFrom(r io.Reader) (int64, error) {
 
 	pk.Permutation = make([]int64, 3*pk.Domain[0].Cardinality)
 
-	dec := curve.NewDecoder(r)
+	dec := curve.NewDecoder(r, decOptions...)
 	toDecode := []interface{}{
 		(*[]fr.Element)(&pk.Ql),
 		(*[]fr.Element)(&pk.Qr),
@@ -167,6 +243,9 @@ func (pk *ProvingKey) ReadFrom(r io.Reader) (int64, error) {
 		(*[]fr.Element)(&pk.S3Canonical),
 		&pk.Permutation,
 	}
+	if raw {
+		toDecode = append(toDecode, &pk.EvaluationPermutationBigDomainBitReversed)
+	}
 
 	for _, v := range toDecode {
 		if err := dec.Decode(v); err != nil {
@@ -174,13 +253,37 @@ func (pk *ProvingKey) ReadFrom(r io.Reader) (int64, error) {
 		}
 	}
 
+	if !raw {
+		computePermutationBigDomain(pk)
+	} else if len(pk.EvaluationPermutationBigDomainBitReversed) != 3*int(pk.Domain[1].Cardinality) {
+		return n + dec.BytesRead(), errors.New("invalid permutation evaluations size, expected 3*big domain cardinality")
+	}
+
 	return n + dec.BytesRead(), nil
 
 }
 
 // WriteTo writes binary encoding of VerifyingKey to w
+// points are compressed
+// use WriteRawTo(...) to encode the key without point compression
 func (vk *VerifyingKey) WriteTo(w io.Writer) (n int64, err error) {
-	enc := curve.NewEncoder(w)
+	return vk.writeTo(w, false)
+}
+
+// WriteRawTo writes binary encoding of VerifyingKey to w
+// points are not compressed
+// use WriteTo(...) to encode the key with point compression
+func (vk *VerifyingKey) WriteRawTo(w io.Writer) (n int64, err error) {
+	return vk.writeTo(w, true)
+}
+
+func (vk *VerifyingKey) writeTo(w io.Writer, raw bool) (n int64, err error) {
+	var enc *curve.Encoder
+	if raw {
+		enc = curve.NewEncoder(w, curve.RawEncoding())
+	} else {
+		enc = curve.NewEncoder(w)
+	}
 
 	toEncode := []interface{}{
 		vk.Size,
@@ -207,8 +310,19 @@ func (vk *VerifyingKey) WriteTo(w io.Writer) (n int64, err error) {
 }
 
 // ReadFrom reads from binary representation in r into VerifyingKey
+// VerifyingKey must be encoded through WriteTo (compressed) or WriteRawTo (uncompressed)
 func (vk *VerifyingKey) ReadFrom(r io.Reader) (int64, error) {
-	dec := curve.NewDecoder(r)
+	return vk.readFrom(r)
+}
+
+// UnsafeReadFrom has the same behavior as ReadFrom, except that it will not check that decode points
+// are on the curve and in the correct subgroup.
+func (vk *VerifyingKey) UnsafeReadFrom(r io.Reader) (int64, error) {
+	return vk.readFrom(r, curve.NoSubgroupChecks())
+}
+
+func (vk *VerifyingKey) readFrom(r io.Reader, decOptions ...func(*curve.Decoder)) (int64, error) {
+	dec := curve.NewDecoder(r, decOptions...)
 	toDecode := []interface{}{
 		&vk.Size,
 		&vk.SizeInv,
@@ -230,5 +344,9 @@ func (vk *VerifyingKey) ReadFrom(r io.Reader) (int64, error) {
 		}
 	}
 
+	// the coset shift is not serialized: as in Setup, it is the generator of Fr* of the domain
+	// (fft.Domain.FrMultiplicativeGen), which doesn't depend on its size
+	vk.CosetShift.Set(&fft.NewDomain(1).FrMultiplicativeGen)
+
 	return dec.BytesRead(), nil
 }
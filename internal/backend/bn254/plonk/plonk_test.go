@@ -220,6 +220,64 @@ func BenchmarkSerialization(b *testing.B) {
 
 }
 
+func BenchmarkProvingKeySerialization(b *testing.B) {
+	ccs, _, srs := referenceCircuit()
+
+	pk, _, err := bn254plonk.Setup(ccs.(*cs.SparseR1CS), srs)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	pk.WriteTo(&buf)
+	compressedBytes := append([]byte{}, buf.Bytes()...)
+
+	buf.Reset()
+	pk.WriteRawTo(&buf)
+	rawBytes := append([]byte{}, buf.Bytes()...)
+
+	buf.Reset()
+	pk.WriteDump(&buf)
+	dumpBytes := append([]byte{}, buf.Bytes()...)
+
+	var reconstructed bn254plonk.ProvingKey
+
+	b.ResetTimer()
+	b.Run("pk_deserialize_compressed_safe", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			reconstructed.ReadFrom(bytes.NewReader(compressedBytes))
+		}
+	})
+
+	b.ResetTimer()
+	b.Run("pk_deserialize_compressed_unsafe", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			reconstructed.UnsafeReadFrom(bytes.NewReader(compressedBytes))
+		}
+	})
+
+	b.ResetTimer()
+	b.Run("pk_deserialize_raw_safe", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			reconstructed.ReadFrom(bytes.NewReader(rawBytes))
+		}
+	})
+
+	b.ResetTimer()
+	b.Run("pk_deserialize_raw_unsafe", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			reconstructed.UnsafeReadFrom(bytes.NewReader(rawBytes))
+		}
+	})
+
+	b.ResetTimer()
+	b.Run("pk_read_dump", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			reconstructed.ReadDump(dumpBytes)
+		}
+	})
+}
+
 var tVariable reflect.Type
 
 func init() {
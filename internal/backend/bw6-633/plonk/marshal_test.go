@@ -22,6 +22,7 @@ import (
 	"github.com/consensys/gnark-crypto/ecc/bw6-633/fr"
 
 	"bytes"
+	"encoding/json"
 	"github.com/consensys/gnark-crypto/ecc/bw6-633/fr/fft"
 	"reflect"
 	"testing"
@@ -32,6 +33,8 @@ func TestProvingKeySerialization(t *testing.T) {
 	var vk VerifyingKey
 	vk.Size = 42
 	vk.SizeInv = fr.One()
+	vk.Generator.SetUint64(5)
+	vk.CosetShift.Set(&fft.NewDomain(42).FrMultiplicativeGen)
 
 	_, _, g1gen, _ := curve.Generators()
 	vk.S[0] = g1gen
@@ -66,6 +69,13 @@ func TestProvingKeySerialization(t *testing.T) {
 	pk.Permutation[0] = -12
 	pk.Permutation[len(pk.Permutation)-1] = 8888
 
+	pk.S1Canonical = make([]fr.Element, pk.Domain[0].Cardinality)
+	pk.S2Canonical = make([]fr.Element, pk.Domain[0].Cardinality)
+	pk.S3Canonical = make([]fr.Element, pk.Domain[0].Cardinality)
+	pk.S1Canonical[0].SetUint64(7)
+	pk.S3Canonical[1].SetUint64(11)
+	computePermutationBigDomain(&pk)
+
 	var buf bytes.Buffer
 	written, err := pk.WriteTo(&buf)
 	if err != nil {
@@ -86,6 +96,93 @@ func TestProvingKeySerialization(t *testing.T) {
 	if written != read {
 		t.Fatal("bytes written / read don't match")
 	}
+
+	// the compressed encoding ends with the permutation, as it did before the raw encoding existed
+	buf.Reset()
+	if _, err := pk.WriteTo(&buf); err != nil {
+		t.Fatal("coudln't serialize", err)
+	}
+	var permutation bytes.Buffer
+	if err := curve.NewEncoder(&permutation).Encode(pk.Permutation); err != nil {
+		t.Fatal("coudln't serialize", err)
+	}
+	if !bytes.HasSuffix(buf.Bytes(), permutation.Bytes()) {
+		t.Fatal("compressed encoding should end with the permutation")
+	}
+
+	// both encodings are self-delimiting: the data following a key in r is left unread
+	for _, raw := range []bool{false, true} {
+		buf.Reset()
+		if raw {
+			written, err = pk.WriteRawTo(&buf)
+		} else {
+			written, err = pk.WriteTo(&buf)
+		}
+		if err != nil {
+			t.Fatal("coudln't serialize", err)
+		}
+		if _, err := pk.WriteTo(&buf); err != nil {
+			t.Fatal("coudln't serialize", err)
+		}
+
+		var first, second ProvingKey
+		read, err = first.ReadFrom(&buf)
+		if err != nil {
+			t.Fatal("coudln't deserialize", err)
+		}
+		if written != read {
+			t.Fatal("bytes written / read don't match")
+		}
+		if _, err := second.ReadFrom(&buf); err != nil {
+			t.Fatal("coudln't deserialize", err)
+		}
+		if !reflect.DeepEqual(&pk, &first) || !reflect.DeepEqual(&pk, &second) {
+			t.Fatal("reconstructed object don't match original")
+		}
+	}
+
+	// uncompressed encoding, carrying the permutation evaluations
+	buf.Reset()
+	written, err = pk.WriteRawTo(&buf)
+	if err != nil {
+		t.Fatal("coudln't serialize", err)
+	}
+
+	var reconstructedRaw ProvingKey
+
+	read, err = reconstructedRaw.UnsafeReadFrom(&buf)
+	if err != nil {
+		t.Fatal("coudln't deserialize", err)
+	}
+
+	if !reflect.DeepEqual(&pk, &reconstructedRaw) {
+		t.Fatal("reconstructed object don't match original")
+	}
+
+	if written != read {
+		t.Fatal("bytes written / read don't match")
+	}
+
+	// raw layout, read back without copy
+	buf.Reset()
+	written, err = pk.WriteDump(&buf)
+	if err != nil {
+		t.Fatal("couldn't dump", err)
+	}
+	if written != int64(buf.Len()) {
+		t.Fatal("bytes written don't match")
+	}
+
+	var dumped ProvingKey
+	if err := dumped.ReadDump(buf.Bytes()); err != nil {
+		t.Fatal("couldn't read dump", err)
+	}
+	if !reflect.DeepEqual(&pk, &dumped) {
+		t.Fatal("dumped object don't match original")
+	}
+	if err := dumped.ReadDump(buf.Bytes()[:buf.Len()/2]); err == nil {
+		t.Fatal("truncated dump should fail")
+	}
 }
 
 func TestVerifyingKeySerialization(t *testing.T) {
@@ -93,6 +190,8 @@ func TestVerifyingKeySerialization(t *testing.T) {
 	var vk VerifyingKey
 	vk.Size = 42
 	vk.SizeInv = fr.One()
+	vk.Generator.SetUint64(5)
+	vk.CosetShift.Set(&fft.NewDomain(42).FrMultiplicativeGen)
 
 	_, _, g1gen, _ := curve.Generators()
 	vk.S[0] = g1gen
@@ -124,4 +223,90 @@ func TestVerifyingKeySerialization(t *testing.T) {
 	if written != read {
 		t.Fatal("bytes written / read don't match")
 	}
+
+	buf.Reset()
+	written, err = vk.WriteRawTo(&buf)
+	if err != nil {
+		t.Fatal("coudln't serialize", err)
+	}
+
+	var reconstructedRaw VerifyingKey
+
+	read, err = reconstructedRaw.UnsafeReadFrom(&buf)
+	if err != nil {
+		t.Fatal("coudln't deserialize", err)
+	}
+
+	if !reflect.DeepEqual(&vk, &reconstructedRaw) {
+		t.Fatal("reconstructed object don't match original")
+	}
+
+	if written != read {
+		t.Fatal("bytes written / read don't match")
+	}
+
+	data, err := json.Marshal(&vk)
+	if err != nil {
+		t.Fatal("couldn't marshal", err)
+	}
+
+	var reconstructedJSON VerifyingKey
+	if err := json.Unmarshal(data, &reconstructedJSON); err != nil {
+		t.Fatal("couldn't unmarshal", err)
+	}
+
+	if !reflect.DeepEqual(&vk, &reconstructedJSON) {
+		t.Fatal("reconstructed object don't match original")
+	}
+}
+
+func TestProofSerialization(t *testing.T) {
+	_, _, g1gen, _ := curve.Generators()
+
+	var proof Proof
+	proof.LRO[0] = g1gen
+	proof.LRO[2].Neg(&g1gen)
+	proof.Z = g1gen
+	proof.H[1] = g1gen
+	proof.BatchedProof.H = g1gen
+	proof.BatchedProof.ClaimedValues = make([]fr.Element, 7)
+	proof.BatchedProof.ClaimedValues[0].SetUint64(42)
+	proof.BatchedProof.ClaimedValues[6].SetOne().Neg(&proof.BatchedProof.ClaimedValues[6])
+	proof.ZShiftedOpening.ClaimedValue.SetUint64(8000)
+
+	for _, raw := range []bool{false, true} {
+		var buf bytes.Buffer
+		written, err := proof.writeTo(&buf, raw)
+		if err != nil {
+			t.Fatal("coudln't serialize", err)
+		}
+
+		var reconstructed Proof
+		read, err := reconstructed.ReadFrom(&buf)
+		if err != nil {
+			t.Fatal("coudln't deserialize", err)
+		}
+
+		if !reflect.DeepEqual(&proof, &reconstructed) {
+			t.Fatal("reconstructed object don't match original")
+		}
+
+		if written != read {
+			t.Fatal("bytes written / read don't match")
+		}
+	}
+
+	data, err := json.Marshal(&proof)
+	if err != nil {
+		t.Fatal("couldn't marshal", err)
+	}
+
+	var reconstructedJSON Proof
+	if err := json.Unmarshal(data, &reconstructedJSON); err != nil {
+		t.Fatal("couldn't unmarshal", err)
+	}
+
+	if !reflect.DeepEqual(&proof, &reconstructedJSON) {
+		t.Fatal("reconstructed object don't match original")
+	}
 }
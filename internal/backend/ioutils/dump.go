@@ -0,0 +1,225 @@
+package ioutils
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"unsafe"
+)
+
+// A dump is the raw memory representation of a sequence of values, which DumpReader maps back without
+// copying or decoding the slices. Each value is padded to dumpAlign bytes, so that the slices mapped from
+// an aligned buffer (for example, a memory-mapped file) are aligned too.
+//
+// The layout is
+//
+//	[magic | endianness uint64 | tag | values...]
+//
+// where slices and byte strings are prefixed with their length as an uint64. Since the values are written
+// in native byte order, a dump is only readable on a machine of the same endianness and word size.
+
+const dumpAlign = 8
+
+var dumpMagic = [8]byte{'g', 'n', 'a', 'r', 'k', 'd', 'm', 'p'}
+
+// dumpEndianness is written in native byte order to detect a dump of another architecture
+const dumpEndianness uint64 = 0x0102030405060708
+
+// ErrInvalidDump is returned when reading a malformed dump, or a dump of another object or architecture
+var ErrInvalidDump = errors.New("invalid dump")
+
+// DumpWriter writes a dump to an io.Writer. The first error is recorded and returned by Close; the
+// subsequent writes are no-ops.
+type DumpWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+// NewDumpWriter writes the header of a dump of an object identified by tag to w
+func NewDumpWriter(w io.Writer, tag string) *DumpWriter {
+	d := &DumpWriter{w: w}
+	d.write(dumpMagic[:])
+	d.Uint64(dumpEndianness)
+	d.Bytes([]byte(tag))
+	return d
+}
+
+func (d *DumpWriter) write(b []byte) {
+	if d.err != nil {
+		return
+	}
+	n, err := d.w.Write(b)
+	d.n += int64(n)
+	d.err = err
+}
+
+func (d *DumpWriter) pad(size uintptr) {
+	if r := size % dumpAlign; r != 0 {
+		var zeroes [dumpAlign]byte
+		d.write(zeroes[:dumpAlign-r])
+	}
+}
+
+// Uint64 writes v
+func (d *DumpWriter) Uint64(v uint64) {
+	d.Value(unsafe.Pointer(&v), unsafe.Sizeof(v))
+}
+
+// Bytes writes the length of b followed by b
+func (d *DumpWriter) Bytes(b []byte) {
+	d.Uint64(uint64(len(b)))
+	d.write(b)
+	d.pad(uintptr(len(b)))
+}
+
+// Value writes the size bytes at p, the memory representation of a value without pointers
+func (d *DumpWriter) Value(p unsafe.Pointer, size uintptr) {
+	if size == 0 {
+		return
+	}
+	d.write(unsafe.Slice((*byte)(p), size))
+	d.pad(size)
+}
+
+// Slice writes the length of the slice at s followed by the memory representation of its elements,
+// of size elemSize and without pointers
+func (d *DumpWriter) Slice(s unsafe.Pointer, elemSize uintptr) {
+	h := (*reflect.SliceHeader)(s)
+	d.Uint64(uint64(h.Len))
+	if h.Len == 0 {
+		return
+	}
+	size := uintptr(h.Len) * elemSize
+	d.write(unsafe.Slice((*byte)(unsafe.Pointer(h.Data)), size))
+	d.pad(size)
+}
+
+// WriterTo writes the binary encoding of o, as a byte string
+func (d *DumpWriter) WriterTo(o io.WriterTo) {
+	if d.err != nil {
+		return
+	}
+	var buf bytes.Buffer
+	if _, d.err = o.WriteTo(&buf); d.err != nil {
+		return
+	}
+	d.Bytes(buf.Bytes())
+}
+
+// Close returns the number of bytes written and the first error
+func (d *DumpWriter) Close() (int64, error) {
+	return d.n, d.err
+}
+
+// DumpReader reads a dump from a buffer. The first error is recorded and returned by Err; the
+// subsequent reads return zero values.
+type DumpReader struct {
+	data []byte
+	off  int
+	err  error
+}
+
+// NewDumpReader reads the header of a dump of an object identified by tag from data
+func NewDumpReader(data []byte, tag string) *DumpReader {
+	d := &DumpReader{data: data}
+	magic := d.next(len(dumpMagic))
+	if d.err != nil || !bytes.Equal(magic, dumpMagic[:]) {
+		d.err = fmt.Errorf("%w: bad magic", ErrInvalidDump)
+		return d
+	}
+	if d.Uint64() != dumpEndianness {
+		d.err = fmt.Errorf("%w: written on a machine of another endianness", ErrInvalidDump)
+		return d
+	}
+	if t := string(d.Bytes()); d.err == nil && t != tag {
+		d.err = fmt.Errorf("%w: dump of %s, expected %s", ErrInvalidDump, t, tag)
+	}
+	return d
+}
+
+// next returns the next n bytes, and skips their padding
+func (d *DumpReader) next(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	padded := n
+	if r := n % dumpAlign; r != 0 {
+		padded += dumpAlign - r
+	}
+	if n < 0 || padded > len(d.data)-d.off {
+		d.err = fmt.Errorf("%w: unexpected end of data", ErrInvalidDump)
+		return nil
+	}
+	b := d.data[d.off : d.off+n : d.off+n]
+	d.off += padded
+	return b
+}
+
+// Uint64 reads an uint64
+func (d *DumpReader) Uint64() uint64 {
+	var v uint64
+	d.Value(unsafe.Pointer(&v), unsafe.Sizeof(v))
+	return v
+}
+
+// Bytes reads a byte string; the result is a sub-slice of the buffer
+func (d *DumpReader) Bytes() []byte {
+	n := d.length(1)
+	return d.next(n)
+}
+
+// length reads the length of a slice of elements of size elemSize, checking that they fit in the buffer
+func (d *DumpReader) length(elemSize uintptr) int {
+	n := d.Uint64()
+	if d.err != nil {
+		return 0
+	}
+	if n > uint64(len(d.data)-d.off)/uint64(elemSize) {
+		d.err = fmt.Errorf("%w: unexpected end of data", ErrInvalidDump)
+		return 0
+	}
+	return int(n)
+}
+
+// Value copies the next size bytes at p
+func (d *DumpReader) Value(p unsafe.Pointer, size uintptr) {
+	if size == 0 {
+		return
+	}
+	if b := d.next(int(size)); d.err == nil {
+		copy(unsafe.Slice((*byte)(p), size), b)
+	}
+}
+
+// Slice returns a pointer to the elements of the next slice, of size elemSize, and their number. The
+// pointer is into the buffer and is nil for an empty slice; unsafe.Slice((*T)(p), n) is the slice.
+func (d *DumpReader) Slice(elemSize uintptr) (p unsafe.Pointer, n int) {
+	n = d.length(elemSize)
+	b := d.next(n * int(elemSize))
+	if d.err != nil || n == 0 {
+		return nil, 0
+	}
+	p = unsafe.Pointer(&b[0])
+	if uintptr(p)%dumpAlign != 0 {
+		d.err = fmt.Errorf("%w: unaligned buffer", ErrInvalidDump)
+		return nil, 0
+	}
+	return p, n
+}
+
+// ReaderFrom decodes a byte string into o
+func (d *DumpReader) ReaderFrom(o io.ReaderFrom) {
+	b := d.Bytes()
+	if d.err != nil {
+		return
+	}
+	_, d.err = o.ReadFrom(bytes.NewReader(b))
+}
+
+// Err returns the first error
+func (d *DumpReader) Err() error {
+	return d.err
+}
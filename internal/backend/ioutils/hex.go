@@ -0,0 +1,17 @@
+package ioutils
+
+import (
+	"encoding/hex"
+	"strings"
+)
+
+// EncodeHex returns the hex encoding of b, prefixed with 0x
+func EncodeHex(b []byte) string {
+	return "0x" + hex.EncodeToString(b)
+}
+
+// DecodeHex decodes the hex string s, with or without the 0x prefix
+func DecodeHex(s string) ([]byte, error) {
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X")
+	return hex.DecodeString(s)
+}
@@ -0,0 +1,19 @@
+package ioutils
+
+// Mapping is a file mapped in memory (see Mmap)
+type Mapping struct {
+	// Data is the content of the file
+	Data []byte
+
+	unmap func() error
+}
+
+// Close unmaps the file; Data must not be accessed after
+func (m *Mapping) Close() error {
+	if m.unmap == nil {
+		return nil
+	}
+	err := m.unmap()
+	m.Data, m.unmap = nil, nil
+	return err
+}
@@ -0,0 +1,38 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+// +build darwin dragonfly freebsd linux netbsd openbsd
+
+package ioutils
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// Mmap maps the file at path in memory, as a private copy-on-write mapping: the pages are shared with
+// the page cache of the other processes mapping the file, until they are written to.
+func Mmap(path string) (*Mapping, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	size := info.Size()
+	if size == 0 {
+		return nil, errors.New("cannot map an empty file")
+	}
+	if int64(int(size)) != size {
+		return nil, errors.New("file too large to be mapped")
+	}
+
+	data, err := syscall.Mmap(int(f.Fd()), 0, int(size), syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_PRIVATE)
+	if err != nil {
+		return nil, err
+	}
+	return &Mapping{Data: data, unmap: func() error { return syscall.Munmap(data) }}, nil
+}
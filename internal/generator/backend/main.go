@@ -12,6 +12,7 @@ import (
 const copyrightHolder = "ConsenSys Software Inc."
 
 var bgen = bavard.NewBatchGenerator(copyrightHolder, 2020, "gnark")
+var blsgen = bavard.NewBatchGenerator(copyrightHolder, 2022, "gnark")
 
 //go:generate go run main.go
 func main() {
@@ -107,8 +108,14 @@ func main() {
 				{File: filepath.Join(groth16Dir, "prove.go"), Templates: []string{"groth16/groth16.prove.go.tmpl", importCurve}},
 				{File: filepath.Join(groth16Dir, "setup.go"), Templates: []string{"groth16/groth16.setup.go.tmpl", importCurve}},
 				{File: filepath.Join(groth16Dir, "marshal.go"), Templates: []string{"groth16/groth16.marshal.go.tmpl", importCurve}},
+				{File: filepath.Join(groth16Dir, "dump.go"), Templates: []string{"groth16/groth16.dump.go.tmpl", importCurve}},
+				{File: filepath.Join(groth16Dir, "json.go"), Templates: []string{"groth16/groth16.json.go.tmpl", importCurve}},
 				{File: filepath.Join(groth16Dir, "marshal_test.go"), Templates: []string{"groth16/tests/groth16.marshal.go.tmpl", importCurve}},
 			}
+			if d.Curve == "BN254" || d.Curve == "BLS12-381" {
+				// arkworks-rs/groth16 interoperability, for the curves it is used with
+				entries = append(entries, bavard.Entry{File: filepath.Join(groth16Dir, "arkworks.go"), Templates: []string{"groth16/groth16.arkworks.go.tmpl", importCurve}})
+			}
 			if err := bgen.Generate(d, "groth16", "./template/zkpschemes/", entries...); err != nil {
 				panic(err) // TODO handle
 			}
@@ -126,6 +133,8 @@ func main() {
 				{File: filepath.Join(plonkDir, "prove.go"), Templates: []string{"plonk/plonk.prove.go.tmpl", importCurve}},
 				{File: filepath.Join(plonkDir, "setup.go"), Templates: []string{"plonk/plonk.setup.go.tmpl", importCurve}},
 				{File: filepath.Join(plonkDir, "marshal.go"), Templates: []string{"plonk/plonk.marshal.go.tmpl", importCurve}},
+				{File: filepath.Join(plonkDir, "dump.go"), Templates: []string{"plonk/plonk.dump.go.tmpl", importCurve}},
+				{File: filepath.Join(plonkDir, "json.go"), Templates: []string{"plonk/plonk.json.go.tmpl", importCurve}},
 				{File: filepath.Join(plonkDir, "marshal_test.go"), Templates: []string{"plonk/tests/marshal.go.tmpl", importCurve}},
 			}
 			if err := bgen.Generate(d, "plonk", "./template/zkpschemes/", entries...); err != nil {
@@ -145,6 +154,44 @@ func main() {
 
 	wg.Wait()
 
+	// BLS signatures, verified on the curves whose scalar field is the base field of the signature curve
+	blsDatas := []blsTemplateData{
+		{
+			RootPath:      "../../../std/signature/bls/bls12377/",
+			Curve:         "BLS12-377",
+			Package:       "bls12377",
+			SnarkCurveID:  "BW6_761",
+			SwPackage:     "sw_bls12377",
+			FieldsPackage: "fields_bls12377",
+			G2Field:       "E2",
+			DST:           "BLS_SIG_BLS12377G1_SVDW_RO_NUL_",
+		},
+		{
+			RootPath:      "../../../std/signature/bls/bls24315/",
+			Curve:         "BLS24-315",
+			Package:       "bls24315",
+			SnarkCurveID:  "BW6_633",
+			SwPackage:     "sw_bls24315",
+			FieldsPackage: "fields_bls24315",
+			G2Field:       "E4",
+			DST:           "BLS_SIG_BLS24315G1_SVDW_RO_NUL_",
+		},
+	}
+	for _, d := range blsDatas {
+		entries := []bavard.Entry{
+			{File: filepath.Join(d.RootPath, "bls.go"), Templates: []string{"bls.go.tmpl"}},
+		}
+		if err := blsgen.Generate(d, d.Package, "./template/signature/bls/", entries...); err != nil {
+			panic(err)
+		}
+		entries = []bavard.Entry{
+			{File: filepath.Join(d.RootPath, "bls_test.go"), Templates: []string{"tests/bls.go.tmpl"}},
+		}
+		if err := blsgen.Generate(d, d.Package, "./template/signature/bls/", entries...); err != nil {
+			panic(err)
+		}
+	}
+
 	// run go fmt on whole directory
 	cmd := exec.Command("gofmt", "-s", "-w", "../../../")
 	cmd.Stdout = os.Stdout
@@ -161,3 +208,14 @@ type templateData struct {
 	Package  string
 	CurveID  string
 }
+
+type blsTemplateData struct {
+	RootPath      string
+	Curve         string // BLS12-377, BLS24-315
+	Package       string
+	SnarkCurveID  string // curve whose scalar field is the base field of Curve
+	SwPackage     string
+	FieldsPackage string
+	G2Field       string // extension field of the coordinates of G2
+	DST           string
+}
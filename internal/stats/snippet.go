@@ -6,11 +6,18 @@ import (
 
 	"github.com/consensys/gnark"
 	"github.com/consensys/gnark-crypto/ecc"
+	tedwards "github.com/consensys/gnark-crypto/ecc/twistededwards"
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/std/algebra/sw_bls12377"
 	"github.com/consensys/gnark/std/algebra/sw_bls24315"
+	"github.com/consensys/gnark/std/algebra/twistededwards"
+	"github.com/consensys/gnark/std/commitments/pedersen"
 	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/std/hash/sha256"
 	"github.com/consensys/gnark/std/math/bits"
+	"github.com/consensys/gnark/std/signature/bls/bls12377"
+	"github.com/consensys/gnark/std/signature/bls/bls24315"
+	"github.com/consensys/gnark/std/signature/eddsa"
 )
 
 var (
@@ -79,6 +86,64 @@ func initSnippets() {
 		_ = mimc.Sum()
 	})
 
+	registerSnippet("hash/sha256", func(api frontend.API, newVariable func() frontend.Variable) {
+		_ = sha256.Sum(api, newBytes(newVariable, 32)...)
+	}, ecc.BN254)
+
+	registerSnippet("pedersen.Hash/2", func(api frontend.API, newVariable func() frontend.Variable) {
+		params, _ := pedersen.NewParams(tedwards.BN254, 3)
+		p, _ := pedersen.New(api, params)
+		_, _ = p.Hash(newVariable(), newVariable())
+	}, ecc.BN254)
+
+	// n signatures verified one by one, and in batch
+	newSignatures := func(newVariable func() frontend.Variable, n int) ([]eddsa.Signature, []frontend.Variable, []eddsa.PublicKey) {
+		sigs := make([]eddsa.Signature, n)
+		msgs := make([]frontend.Variable, n)
+		pubKeys := make([]eddsa.PublicKey, n)
+		for i := 0; i < n; i++ {
+			sigs[i].R.X, sigs[i].R.Y, sigs[i].S = newVariable(), newVariable(), newVariable()
+			msgs[i] = newVariable()
+			pubKeys[i].A.X, pubKeys[i].A.Y = newVariable(), newVariable()
+		}
+		return sigs, msgs, pubKeys
+	}
+	registerSnippet("eddsa.Verify/4", func(api frontend.API, newVariable func() frontend.Variable) {
+		curve, _ := twistededwards.NewEdCurve(api, tedwards.BN254)
+		mimc, _ := mimc.NewMiMC(api)
+		sigs, msgs, pubKeys := newSignatures(newVariable, 4)
+		for i := range sigs {
+			mimc.Reset()
+			_ = eddsa.Verify(curve, sigs[i], msgs[i], pubKeys[i], &mimc)
+		}
+	}, ecc.BN254)
+	registerSnippet("eddsa.BatchVerify/4", func(api frontend.API, newVariable func() frontend.Variable) {
+		curve, _ := twistededwards.NewEdCurve(api, tedwards.BN254)
+		mimc, _ := mimc.NewMiMC(api)
+		sigs, msgs, pubKeys := newSignatures(newVariable, 4)
+		_ = eddsa.BatchVerify(curve, sigs, msgs, pubKeys, &mimc)
+	}, ecc.BN254)
+	registerSnippet("eddsa.Verify/16", func(api frontend.API, newVariable func() frontend.Variable) {
+		curve, _ := twistededwards.NewEdCurve(api, tedwards.BN254)
+		mimc, _ := mimc.NewMiMC(api)
+		sigs, msgs, pubKeys := newSignatures(newVariable, 16)
+		for i := range sigs {
+			mimc.Reset()
+			_ = eddsa.Verify(curve, sigs[i], msgs[i], pubKeys[i], &mimc)
+		}
+	}, ecc.BN254)
+	registerSnippet("eddsa.BatchVerify/16", func(api frontend.API, newVariable func() frontend.Variable) {
+		curve, _ := twistededwards.NewEdCurve(api, tedwards.BN254)
+		mimc, _ := mimc.NewMiMC(api)
+		sigs, msgs, pubKeys := newSignatures(newVariable, 16)
+		_ = eddsa.BatchVerify(curve, sigs, msgs, pubKeys, &mimc)
+	}, ecc.BN254)
+	registerSnippet("twistededwards.HashToCurve", func(api frontend.API, newVariable func() frontend.Variable) {
+		curve, _ := twistededwards.NewEdCurve(api, tedwards.BN254)
+		mimc, _ := mimc.NewMiMC(api)
+		_, _ = curve.HashToCurve(&mimc, []byte("snippet"), newVariable())
+	}, ecc.BN254)
+
 	registerSnippet("pairing_bls12377", func(api frontend.API, newVariable func() frontend.Variable) {
 
 		var dummyG1 sw_bls12377.G1Affine
@@ -97,6 +162,21 @@ func initSnippets() {
 		_ = sw_bls12377.FinalExponentiation(api, resMillerLoop)
 	}, ecc.BW6_761)
 
+	registerSnippet("sw_bls12377.AssertPairingCheck", func(api frontend.API, newVariable func() frontend.Variable) {
+
+		var dummyG1 sw_bls12377.G1Affine
+		var dummyG2 sw_bls12377.G2Affine
+		dummyG1.X = newVariable()
+		dummyG1.Y = newVariable()
+		dummyG2.X.A0 = newVariable()
+		dummyG2.X.A1 = newVariable()
+		dummyG2.Y.A0 = newVariable()
+		dummyG2.Y.A1 = newVariable()
+
+		// same Miller loop as pairing_bls12377, checked against a residue witness instead of the final expo
+		_ = sw_bls12377.AssertPairingCheck(api, []sw_bls12377.G1Affine{dummyG1}, []sw_bls12377.G2Affine{dummyG2})
+	}, ecc.BW6_761)
+
 	registerSnippet("pairing_bls24315", func(api frontend.API, newVariable func() frontend.Variable) {
 
 		var dummyG1 sw_bls24315.G1Affine
@@ -119,6 +199,69 @@ func initSnippets() {
 		_ = sw_bls24315.FinalExponentiation(api, resMillerLoop)
 	}, ecc.BW6_633)
 
+	registerSnippet("sw_bls24315.AssertPairingCheck", func(api frontend.API, newVariable func() frontend.Variable) {
+
+		var dummyG1 sw_bls24315.G1Affine
+		var dummyG2 sw_bls24315.G2Affine
+		dummyG1.X = newVariable()
+		dummyG1.Y = newVariable()
+		dummyG2.X.B0.A0 = newVariable()
+		dummyG2.X.B0.A1 = newVariable()
+		dummyG2.X.B1.A0 = newVariable()
+		dummyG2.X.B1.A1 = newVariable()
+		dummyG2.Y.B0.A0 = newVariable()
+		dummyG2.Y.B0.A1 = newVariable()
+		dummyG2.Y.B1.A0 = newVariable()
+		dummyG2.Y.B1.A1 = newVariable()
+
+		// same Miller loop as pairing_bls24315, checked against a residue witness instead of the final expo
+		_ = sw_bls24315.AssertPairingCheck(api, []sw_bls24315.G1Affine{dummyG1}, []sw_bls24315.G2Affine{dummyG2})
+	}, ecc.BW6_633)
+
+	registerSnippet("sw_bls12377.HashToG1", func(api frontend.API, newVariable func() frontend.Variable) {
+		_, _ = sw_bls12377.HashToG1(api, []byte("snippet"), newBytes(newVariable, 32)...)
+	}, ecc.BW6_761)
+	registerSnippet("sw_bls12377.HashToG2", func(api frontend.API, newVariable func() frontend.Variable) {
+		_, _ = sw_bls12377.HashToG2(api, []byte("snippet"), newBytes(newVariable, 32)...)
+	}, ecc.BW6_761)
+
+	registerSnippet("bls12377.Verify", func(api frontend.API, newVariable func() frontend.Variable) {
+		var sig bls12377.Signature
+		var pk bls12377.PublicKey
+		sig.S.X = newVariable()
+		sig.S.Y = newVariable()
+		pk.A.X.A0 = newVariable()
+		pk.A.X.A1 = newVariable()
+		pk.A.Y.A0 = newVariable()
+		pk.A.Y.A1 = newVariable()
+		_ = bls12377.Verify(api, sig, newBytes(newVariable, 32), pk)
+	}, ecc.BW6_761)
+
+	registerSnippet("bls24315.Verify", func(api frontend.API, newVariable func() frontend.Variable) {
+		var sig bls24315.Signature
+		var pk bls24315.PublicKey
+		sig.S.X = newVariable()
+		sig.S.Y = newVariable()
+		pk.A.X.B0.A0 = newVariable()
+		pk.A.X.B0.A1 = newVariable()
+		pk.A.X.B1.A0 = newVariable()
+		pk.A.X.B1.A1 = newVariable()
+		pk.A.Y.B0.A0 = newVariable()
+		pk.A.Y.B0.A1 = newVariable()
+		pk.A.Y.B1.A0 = newVariable()
+		pk.A.Y.B1.A1 = newVariable()
+		_ = bls24315.Verify(api, sig, newBytes(newVariable, 32), pk)
+	}, ecc.BW6_633)
+
+}
+
+// newBytes returns n variables, hashed as bytes by the snippets
+func newBytes(newVariable func() frontend.Variable, n int) []frontend.Variable {
+	res := make([]frontend.Variable, n)
+	for i := range res {
+		res[i] = newVariable()
+	}
+	return res
 }
 
 type snippetCircuit struct {
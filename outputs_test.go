@@ -0,0 +1,110 @@
+package gnark_test
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/test"
+	"github.com/stretchr/testify/require"
+)
+
+// -------------------------------------------------------------------------------------------------
+// public outputs computed by the solver
+type outputCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",output"`
+	Z frontend.Variable `gnark:",output"`
+}
+
+func (circuit *outputCircuit) Define(api frontend.API) error {
+	x3 := api.Mul(circuit.X, circuit.X, circuit.X)
+	circuit.Y = api.Add(x3, circuit.X, 5)
+	circuit.Z = 42
+	return nil
+}
+
+type unassignedOutputCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",output"`
+}
+
+func (circuit *unassignedOutputCircuit) Define(api frontend.API) error {
+	api.AssertIsDifferent(circuit.X, 0)
+	return nil
+}
+
+// a circuit whose only public variable is named as the public inputs hash of WithPublicInputsHash
+type publicInputsHashNameCircuit struct {
+	X                frontend.Variable
+	PublicInputsHash frontend.Variable `gnark:",output"`
+}
+
+func (circuit *publicInputsHashNameCircuit) Define(api frontend.API) error {
+	circuit.PublicInputsHash = api.Mul(circuit.X, circuit.X)
+	return nil
+}
+
+func TestOutputs(t *testing.T) {
+	assert := require.New(t)
+
+	for _, b := range []backend.ID{backend.GROTH16, backend.PLONK} {
+		var ccs frontend.CompiledConstraintSystem
+		var err error
+		if b == backend.GROTH16 {
+			ccs, err = frontend.Compile(ecc.BN254, r1cs.NewBuilder, &outputCircuit{})
+		} else {
+			ccs, err = frontend.Compile(ecc.BN254, scs.NewBuilder, &outputCircuit{})
+		}
+		assert.NoError(err)
+
+		full, public, err := frontend.CompleteWitness(ccs, &outputCircuit{X: 3})
+		assert.NoError(err)
+
+		expected, err := frontend.NewWitness(&outputCircuit{X: 3, Y: 35, Z: 42}, ecc.BN254, frontend.PublicOnly())
+		assert.NoError(err)
+		assert.Equal(expected.Vector, public.Vector, b.String())
+
+		assert.NoError(ccs.IsSolved(full), b.String())
+
+		wrong, err := frontend.NewWitness(&outputCircuit{X: 3, Y: 36, Z: 42}, ecc.BN254)
+		assert.NoError(err)
+		assert.Error(ccs.IsSolved(wrong), b.String())
+
+		if b == backend.GROTH16 {
+			pk, vk, err := groth16.Setup(ccs)
+			assert.NoError(err)
+			proof, err := groth16.Prove(ccs, pk, full)
+			assert.NoError(err)
+			assert.NoError(groth16.Verify(proof, vk, public))
+		} else {
+			srs, err := test.NewKZGSRS(ccs)
+			assert.NoError(err)
+			pk, vk, err := plonk.Setup(ccs, srs)
+			assert.NoError(err)
+			proof, err := plonk.Prove(ccs, pk, full)
+			assert.NoError(err)
+			assert.NoError(plonk.Verify(proof, vk, public))
+		}
+	}
+
+	// test engine
+	assert.NoError(test.IsSolved(&outputCircuit{}, &outputCircuit{X: 3, Y: 35, Z: 42}, ecc.BN254, backend.GROTH16))
+	assert.Error(test.IsSolved(&outputCircuit{}, &outputCircuit{X: 3, Y: 36, Z: 42}, ecc.BN254, backend.GROTH16))
+
+	// outputs must be assigned in Define
+	_, err := frontend.Compile(ecc.BN254, r1cs.NewBuilder, &unassignedOutputCircuit{})
+	assert.Error(err)
+
+	// the compile option is recorded in the schema, not guessed from the name of the public variable
+	ccs, err := frontend.Compile(ecc.BN254, r1cs.NewBuilder, &publicInputsHashNameCircuit{})
+	assert.NoError(err)
+	full, _, err := frontend.CompleteWitness(ccs, &publicInputsHashNameCircuit{X: 3})
+	assert.NoError(err)
+	assert.NoError(ccs.IsSolved(full))
+}
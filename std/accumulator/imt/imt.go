@@ -0,0 +1,130 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package imt provides ZKP-circuit functions to verify appends to an incremental Merkle tree.
+//
+// An incremental Merkle tree of depth d is an append-only Merkle tree with 2ᵈ leaves, filled from
+// left to right; the leaves which are not yet appended are 0. A node is H(left, right).
+//
+// The state of the tree is summarized by its frontier: the number of leaves and, for each level,
+// the left sibling of the path of the next leaf. Appending a leaf only updates the nodes of its path,
+// so verifying the append of k leaves costs O(k⋅d) hashes.
+//
+// The native Tree generates the frontier witnesses.
+package imt
+
+import (
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+)
+
+// Frontier is the witness of the state of an incremental Merkle tree of depth len(Nodes).
+type Frontier struct {
+	// Size is the number of leaves in the tree, that is the index of the next leaf
+	Size frontend.Variable
+
+	// Nodes[i] is the left sibling at level i of the path of the next leaf, if the i-th bit of
+	// Size is set. Otherwise the next leaf is a left child at level i and Nodes[i] is ignored.
+	Nodes []frontend.Variable
+}
+
+// NewFrontier returns a Frontier for a tree of given depth, with unassigned variables.
+// It is meant to be used to declare a circuit.
+func NewFrontier(depth int) Frontier {
+	return Frontier{Nodes: make([]frontend.Variable, depth)}
+}
+
+// VerifyAppend asserts that appending the leaves to the tree of root oldRoot and given frontier
+// results in the tree of root newRoot. It returns the frontier of the new tree.
+//
+// The hash function is reset before each node hash.
+func VerifyAppend(api frontend.API, h hash.Hash, oldRoot, newRoot frontend.Variable, frontier Frontier, leaves []frontend.Variable) Frontier {
+	depth := len(frontier.Nodes)
+	zeros := zeroHashes(h, depth)
+
+	// bits of the index of the next leaf; this also ensures the tree is not full
+	bits := api.ToBinary(frontier.Size, depth)
+
+	nodes := make([]frontend.Variable, depth)
+	copy(nodes, frontier.Nodes)
+
+	// the root of the tree is the root of the path of the next leaf, which is empty
+	api.AssertIsEqual(pathRoot(api, h, 0, bits, nodes, zeros), oldRoot)
+
+	root := oldRoot
+	for i, leaf := range leaves {
+		// carry is 1 iff the bits of the index below the current level are all set:
+		// it marks the level of the frontier to update, and the bits to flip to increment the index
+		var carry frontend.Variable = 1
+		node := leaf
+		for l := 0; l < depth; l++ {
+			b := bits[l]
+
+			// the node of the path is the left sibling of the path of the next leaf
+			update := api.Mul(carry, api.Sub(1, b))
+			nodes[l] = api.Select(update, node, nodes[l])
+
+			left := api.Select(b, nodes[l], node)
+			right := api.Select(b, node, zeros[l])
+			node = nodeSum(h, left, right)
+
+			bits[l] = api.Xor(b, carry)
+			carry = api.Mul(carry, b)
+		}
+		if i != len(leaves)-1 {
+			// the next leaf must fit in the tree
+			api.AssertIsEqual(carry, 0)
+		}
+		root = node
+	}
+	api.AssertIsEqual(root, newRoot)
+
+	return Frontier{
+		Size:  api.Add(frontier.Size, len(leaves)),
+		Nodes: nodes,
+	}
+}
+
+// pathRoot returns the root of the tree with leaf at the position given by bits
+func pathRoot(api frontend.API, h hash.Hash, leaf frontend.Variable, bits, nodes, zeros []frontend.Variable) frontend.Variable {
+	node := leaf
+	for l := range bits {
+		left := api.Select(bits[l], nodes[l], node)
+		right := api.Select(bits[l], node, zeros[l])
+		node = nodeSum(h, left, right)
+	}
+	return node
+}
+
+// zeroHashes returns the roots of the empty subtrees of height 0 to depth-1
+func zeroHashes(h hash.Hash, depth int) []frontend.Variable {
+	zeros := make([]frontend.Variable, depth)
+	if depth == 0 {
+		return zeros
+	}
+	zeros[0] = 0
+	for l := 1; l < depth; l++ {
+		zeros[l] = nodeSum(h, zeros[l-1], zeros[l-1])
+	}
+	return zeros
+}
+
+// nodeSum returns the hash of a node from its children
+func nodeSum(h hash.Hash, left, right frontend.Variable) frontend.Variable {
+	h.Reset()
+	h.Write(left, right)
+	return h.Sum()
+}
@@ -0,0 +1,185 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imt
+
+import (
+	"bytes"
+	"errors"
+	"hash"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	bn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/test"
+)
+
+const testDepth = 4
+
+type appendCircuit struct {
+	OldRoot, NewRoot frontend.Variable `gnark:",public"`
+	NewSize          frontend.Variable `gnark:",public"`
+	Frontier         Frontier
+	Leaves           []frontend.Variable
+}
+
+func (circuit *appendCircuit) Define(api frontend.API) error {
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	f := VerifyAppend(api, &h, circuit.OldRoot, circuit.NewRoot, circuit.Frontier, circuit.Leaves)
+	api.AssertIsEqual(f.Size, circuit.NewSize)
+	return nil
+}
+
+func randomLeaves(t *testing.T, n int) [][]byte {
+	leaves := make([][]byte, n)
+	for i := range leaves {
+		var leaf fr.Element
+		if _, err := leaf.SetRandom(); err != nil {
+			t.Fatal(err)
+		}
+		b := leaf.Bytes()
+		leaves[i] = b[:]
+	}
+	return leaves
+}
+
+func TestTree(t *testing.T) {
+	assert := test.NewAssert(t)
+	leaves := randomLeaves(t, 1<<testDepth)
+
+	tree, err := New(bn254.NewMiMC(), testDepth)
+	assert.NoError(err)
+	for i := 0; i <= len(leaves); i++ {
+		// the root matches the one of the complete tree, padded with zero leaves
+		level := make([][]byte, len(leaves))
+		copy(level, leaves[:i])
+		for j := i; j < len(level); j++ {
+			level[j] = make([]byte, fr.Bytes)
+		}
+		for len(level) > 1 {
+			next := make([][]byte, len(level)/2)
+			for j := range next {
+				h := bn254.NewMiMC()
+				_, _ = h.Write(level[2*j])
+				_, _ = h.Write(level[2*j+1])
+				next[j] = h.Sum(nil)
+			}
+			level = next
+		}
+		assert.Equal(level[0], tree.Root(), "size %d", i)
+
+		if i < len(leaves) {
+			assert.NoError(tree.Append(leaves[i]))
+		}
+	}
+	assert.Error(tree.Append(leaves[0]))
+}
+
+// failingHash is a hash function rejecting the inputs starting with 0xff
+type failingHash struct {
+	hash.Hash
+}
+
+var errFailingHash = errors.New("failing hash")
+
+func (h failingHash) Write(p []byte) (int, error) {
+	if len(p) != 0 && p[0] == 0xff {
+		return 0, errFailingHash
+	}
+	return h.Hash.Write(p)
+}
+
+func TestTreeHashError(t *testing.T) {
+	assert := test.NewAssert(t)
+	leaves := randomLeaves(t, 3)
+	invalid := bytes.Repeat([]byte{0xff}, fr.Bytes)
+
+	tree, err := New(failingHash{bn254.NewMiMC()}, testDepth)
+	assert.NoError(err)
+	for _, leaf := range leaves {
+		assert.NoError(tree.Append(leaf))
+	}
+
+	// the leaf is rejected whether it is hashed with the frontier or with an empty subtree,
+	// and the tree is left unchanged
+	root, frontier := tree.Root(), tree.Frontier()
+	assert.ErrorIs(tree.Append(invalid), errFailingHash)
+	assert.Equal(root, tree.Root())
+	assert.Equal(frontier, tree.Frontier())
+
+	assert.NoError(tree.Append(leaves[0]))
+	assert.ErrorIs(tree.Append(invalid), errFailingHash)
+	assert.Equal(uint64(4), tree.Size())
+}
+
+func TestVerifyAppend(t *testing.T) {
+	assert := test.NewAssert(t)
+	leaves := randomLeaves(t, 1<<testDepth)
+
+	for _, tc := range []struct{ size, batch int }{{0, 3}, {5, 3}, {7, 1}, {13, 3}} {
+		tree, err := New(bn254.NewMiMC(), testDepth)
+		assert.NoError(err)
+		for i := 0; i < tc.size; i++ {
+			assert.NoError(tree.Append(leaves[i]))
+		}
+		oldRoot, frontier := tree.Root(), tree.Frontier()
+
+		witness := appendCircuit{
+			OldRoot:  oldRoot,
+			NewSize:  tc.size + tc.batch,
+			Frontier: frontier,
+			Leaves:   make([]frontend.Variable, tc.batch),
+		}
+		for i := 0; i < tc.batch; i++ {
+			witness.Leaves[i] = leaves[tc.size+i]
+			assert.NoError(tree.Append(leaves[tc.size+i]))
+		}
+		witness.NewRoot = tree.Root()
+
+		circuit := appendCircuit{Frontier: NewFrontier(testDepth), Leaves: make([]frontend.Variable, tc.batch)}
+		assert.ProverSucceeded(&circuit, &witness, test.WithCurves(ecc.BN254), test.WithBackends(backend.GROTH16))
+
+		wrong := witness
+		wrong.Leaves = append([]frontend.Variable{}, witness.Leaves...)
+		wrong.Leaves[0] = randomLeaves(t, 1)[0]
+		assert.ProverFailed(&circuit, &wrong, test.WithCurves(ecc.BN254), test.WithBackends(backend.GROTH16))
+	}
+
+	// appending to a full tree
+	{
+		tree, err := New(bn254.NewMiMC(), testDepth)
+		assert.NoError(err)
+		for i := 0; i < len(leaves)-1; i++ {
+			assert.NoError(tree.Append(leaves[i]))
+		}
+		witness := appendCircuit{
+			OldRoot:  tree.Root(),
+			NewRoot:  tree.Root(),
+			NewSize:  len(leaves) + 1,
+			Frontier: tree.Frontier(),
+			Leaves:   []frontend.Variable{leaves[0], leaves[1]},
+		}
+		circuit := appendCircuit{Frontier: NewFrontier(testDepth), Leaves: make([]frontend.Variable, 2)}
+		assert.ProverFailed(&circuit, &witness, test.WithCurves(ecc.BN254), test.WithBackends(backend.GROTH16))
+	}
+}
@@ -0,0 +1,153 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imt
+
+import (
+	"errors"
+	"hash"
+
+	"github.com/consensys/gnark/frontend"
+)
+
+var errTreeFull = errors.New("tree is full")
+
+// Tree is a native incremental Merkle tree, matching the circuit definition.
+//
+// It only stores its frontier and its root. The hash function must be the native counterpart of
+// the hash gadget used in the circuit (for example gnark-crypto's MiMC for std/hash/mimc).
+// Leaves must be field elements, encoded as big-endian byte slices of size h.BlockSize().
+type Tree struct {
+	h     hash.Hash
+	depth int
+	size  uint64
+	full  bool
+	root  []byte
+
+	// zeros[i] is the root of an empty subtree of height i
+	zeros [][]byte
+
+	// frontier[i] is the left sibling at level i of the path of the next leaf, if the i-th bit of size is set
+	frontier [][]byte
+}
+
+// New returns an empty incremental Merkle tree of given depth (with 2^depth leaves),
+// or the error returned by the hash function.
+func New(h hash.Hash, depth int) (*Tree, error) {
+	t := &Tree{
+		h:        h,
+		depth:    depth,
+		zeros:    make([][]byte, depth),
+		frontier: make([][]byte, depth),
+	}
+	zero := make([]byte, h.BlockSize())
+	for i := 0; i < depth; i++ {
+		t.zeros[i] = zero
+		t.frontier[i] = zero
+		var err error
+		if zero, err = t.hash(zero, zero); err != nil {
+			return nil, err
+		}
+	}
+	t.root = zero
+	return t, nil
+}
+
+// Size returns the number of leaves in the tree
+func (t *Tree) Size() uint64 {
+	return t.size
+}
+
+// Root returns the root of the tree
+func (t *Tree) Root() []byte {
+	return t.root
+}
+
+// Append appends a leaf to the tree and updates the frontier and the root.
+// The tree is left unchanged if an error is returned, for example if the hash function rejects
+// the leaf.
+func (t *Tree) Append(leaf []byte) error {
+	if t.full {
+		return errTreeFull
+	}
+	frontier := make([][]byte, t.depth)
+	copy(frontier, t.frontier)
+
+	node := leaf
+	for l := 0; l < t.depth; l++ {
+		if (t.size>>uint(l))&1 == 0 {
+			// first level where the leaf is a left child: the node is the left sibling
+			// of the path of the next leaf
+			frontier[l] = node
+			root, err := t.rootOf(frontier, t.size+1)
+			if err != nil {
+				return err
+			}
+			t.frontier, t.root = frontier, root
+			t.size++
+			return nil
+		}
+		var err error
+		if node, err = t.hash(frontier[l], node); err != nil {
+			return err
+		}
+	}
+	// the leaf was the last one
+	t.root = node
+	t.full = true
+	t.size++
+	return nil
+}
+
+// rootOf returns the root of a (non full) tree of given size and frontier
+func (t *Tree) rootOf(frontier [][]byte, size uint64) ([]byte, error) {
+	node := make([]byte, t.h.BlockSize())
+	for l := 0; l < t.depth; l++ {
+		var err error
+		if (size>>uint(l))&1 == 1 {
+			node, err = t.hash(frontier[l], node)
+		} else {
+			node, err = t.hash(node, t.zeros[l])
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return node, nil
+}
+
+// Frontier returns the witness of the current state of the tree, see VerifyAppend.
+func (t *Tree) Frontier() Frontier {
+	f := Frontier{
+		Size:  t.size,
+		Nodes: make([]frontend.Variable, t.depth),
+	}
+	for l := range f.Nodes {
+		f.Nodes[l] = t.frontier[l]
+	}
+	return f
+}
+
+func (t *Tree) hash(left, right []byte) ([]byte, error) {
+	t.h.Reset()
+	if _, err := t.h.Write(left); err != nil {
+		return nil, err
+	}
+	if _, err := t.h.Write(right); err != nil {
+		return nil, err
+	}
+	return t.h.Sum(nil), nil
+}
@@ -0,0 +1,170 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package merkle
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+)
+
+// MultiProof is a proof that several leaves belong to a complete binary Merkle tree of depth Depth.
+//
+// The internal nodes shared by the paths of the leaves are computed once: verifying a multiproof
+// of k leaves costs less than k hashes per level of the tree, depending on how close the leaves are.
+// Since the indices of the leaves determine which nodes are shared, they are part of the circuit
+// definition and not of the witness.
+//
+// Proof contains the nodes which are needed to compute the root and can't be computed from the leaves,
+// from the bottom level to the top and from left to right in each level. BuildMultiProof computes it natively.
+type MultiProof struct {
+	Depth   int      `gnark:"-"`
+	Indices []uint64 `gnark:"-"` // strictly increasing indices of the leaves
+
+	Leaves []frontend.Variable // data of the leaves at Indices
+	Proof  []frontend.Variable
+}
+
+// NewMultiProof returns a MultiProof of the leaves at given indices in a tree of given depth,
+// with unassigned variables. It is meant to be used to declare a circuit.
+func NewMultiProof(depth int, indices []uint64) (MultiProof, error) {
+	_, proofNodes, err := multiProofSteps(depth, indices)
+	if err != nil {
+		return MultiProof{}, err
+	}
+	return MultiProof{
+		Depth:   depth,
+		Indices: append([]uint64(nil), indices...),
+		Leaves:  make([]frontend.Variable, len(indices)),
+		Proof:   make([]frontend.Variable, len(proofNodes)),
+	}, nil
+}
+
+// VerifyMultiProof asserts that the leaves of the multiproof belong to the Merkle tree of given root.
+//
+// The hash function is reset before each leaf or node hash.
+func VerifyMultiProof(api frontend.API, h hash.Hash, merkleRoot frontend.Variable, p MultiProof, opts ...Option) error {
+	opt := newConfig(opts...)
+
+	steps, proofNodes, err := multiProofSteps(p.Depth, p.Indices)
+	if err != nil {
+		return err
+	}
+	if len(p.Leaves) != len(p.Indices) {
+		return fmt.Errorf("got %d leaves, expected %d", len(p.Leaves), len(p.Indices))
+	}
+	if len(p.Proof) != len(proofNodes) {
+		return fmt.Errorf("got %d proof nodes, expected %d", len(p.Proof), len(proofNodes))
+	}
+
+	nodes := make([]frontend.Variable, 0, len(p.Leaves)+len(steps))
+	for _, leaf := range p.Leaves {
+		nodes = append(nodes, leafSum(h, opt, leaf))
+	}
+	get := func(r nodeRef) frontend.Variable {
+		if r.fromProof {
+			return p.Proof[r.idx]
+		}
+		return nodes[r.idx]
+	}
+	for _, s := range steps {
+		nodes = append(nodes, nodeSum(h, opt, get(s.left), get(s.right)))
+	}
+
+	api.AssertIsEqual(nodes[len(nodes)-1], merkleRoot)
+	return nil
+}
+
+// nodeRef references a node during the verification of a multiproof: either a computed node
+// (the leaf hashes first, then the results of the steps), or a node of the proof.
+type nodeRef struct {
+	fromProof bool
+	idx       int
+}
+
+// step is a node hash computed during the verification of a multiproof
+type step struct {
+	left, right nodeRef
+}
+
+// position of a node in the tree; level 0 is the leaves
+type position struct {
+	level int
+	index uint64
+}
+
+// multiProofSteps returns the node hashes computed to verify a multiproof of the leaves at
+// given indices in a complete tree of given depth, and the positions of the nodes of the proof.
+func multiProofSteps(depth int, indices []uint64) ([]step, []position, error) {
+	if depth < 0 || depth > 63 {
+		return nil, nil, errors.New("invalid depth")
+	}
+	if len(indices) == 0 {
+		return nil, nil, errors.New("no leaf to prove")
+	}
+	for i := range indices {
+		if indices[i] >= (1 << uint(depth)) {
+			return nil, nil, fmt.Errorf("leaf index %d out of range", indices[i])
+		}
+		if i > 0 && indices[i] <= indices[i-1] {
+			return nil, nil, errors.New("leaf indices must be strictly increasing")
+		}
+	}
+
+	type node struct {
+		index uint64
+		ref   int
+	}
+	level := make([]node, len(indices))
+	for i := range indices {
+		level[i] = node{index: indices[i], ref: i}
+	}
+	nbNodes := len(indices)
+
+	var steps []step
+	var proofNodes []position
+	fromProof := func(l int, index uint64) nodeRef {
+		proofNodes = append(proofNodes, position{level: l, index: index})
+		return nodeRef{fromProof: true, idx: len(proofNodes) - 1}
+	}
+
+	for l := 0; l < depth; l++ {
+		next := make([]node, 0, len(level))
+		for i := 0; i < len(level); i++ {
+			n := level[i]
+			var s step
+			if n.index&1 == 1 {
+				// the left sibling is not computed, else it would have been processed with n
+				s = step{left: fromProof(l, n.index-1), right: nodeRef{idx: n.ref}}
+			} else if i+1 < len(level) && level[i+1].index == n.index+1 {
+				// shared parent
+				s = step{left: nodeRef{idx: n.ref}, right: nodeRef{idx: level[i+1].ref}}
+				i++
+			} else {
+				s = step{left: nodeRef{idx: n.ref}, right: fromProof(l, n.index+1)}
+			}
+			steps = append(steps, s)
+			next = append(next, node{index: n.index >> 1, ref: nbNodes})
+			nbNodes++
+		}
+		level = next
+	}
+
+	return steps, proofNodes, nil
+}
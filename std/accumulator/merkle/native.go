@@ -0,0 +1,117 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package merkle
+
+import (
+	"errors"
+	"hash"
+	"math/bits"
+)
+
+// The native functions below build proofs for complete binary trees (the number of leaves is a
+// power of 2), with or without domain separation. The hash function must be the native counterpart
+// of the hash gadget used in the circuit (for example gnark-crypto's MiMC for std/hash/mimc):
+// the prefixes are written as big-endian byte slices of size h.BlockSize().
+
+// BuildProof returns the root of the complete binary tree with given leaves, and the proof set
+// and helper of the leaf at index, as expected by VerifyProof.
+func BuildProof(h hash.Hash, leaves [][]byte, index uint64, opts ...Option) (root []byte, proofSet [][]byte, helper []int, err error) {
+	tree, err := buildTree(h, newConfig(opts...), leaves)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if index >= uint64(len(leaves)) {
+		return nil, nil, nil, errors.New("leaf index out of range")
+	}
+
+	depth := len(tree) - 1
+	proofSet = make([][]byte, 0, depth+1)
+	proofSet = append(proofSet, leaves[index])
+	helper = make([]int, depth)
+	for l := 0; l < depth; l++ {
+		proofSet = append(proofSet, tree[l][index^1])
+		helper[l] = int(1 - index&1)
+		index >>= 1
+	}
+	return tree[depth][0], proofSet, helper, nil
+}
+
+// BuildMultiProof returns the root of the complete binary tree with given leaves, and the proof
+// nodes of the multiproof of the leaves at given (strictly increasing) indices, see MultiProof.
+func BuildMultiProof(h hash.Hash, leaves [][]byte, indices []uint64, opts ...Option) (root []byte, proof [][]byte, err error) {
+	tree, err := buildTree(h, newConfig(opts...), leaves)
+	if err != nil {
+		return nil, nil, err
+	}
+	depth := len(tree) - 1
+	_, proofNodes, err := multiProofSteps(depth, indices)
+	if err != nil {
+		return nil, nil, err
+	}
+	proof = make([][]byte, len(proofNodes))
+	for i, p := range proofNodes {
+		proof[i] = tree[p.level][p.index]
+	}
+	return tree[depth][0], proof, nil
+}
+
+// buildTree returns the nodes of the complete binary tree with given leaves, level by level
+// (tree[0] are the leaf hashes, tree[depth] is the root)
+func buildTree(h hash.Hash, opt config, leaves [][]byte) ([][][]byte, error) {
+	if len(leaves) == 0 || bits.OnesCount(uint(len(leaves))) != 1 {
+		return nil, errors.New("the number of leaves must be a power of 2")
+	}
+	level := make([][]byte, len(leaves))
+	for i := range leaves {
+		var err error
+		if level[i], err = nativeSum(h, opt, LeafPrefix, leaves[i]); err != nil {
+			return nil, err
+		}
+	}
+	tree := [][][]byte{level}
+	for len(level) > 1 {
+		next := make([][]byte, len(level)/2)
+		for i := range next {
+			var err error
+			if next[i], err = nativeSum(h, opt, NodePrefix, level[2*i], level[2*i+1]); err != nil {
+				return nil, err
+			}
+		}
+		tree = append(tree, next)
+		level = next
+	}
+	return tree, nil
+}
+
+// nativeSum returns H([prefix] ∥ data...), or the error returned by h.Write
+// (for example if data isn't a canonical field element)
+func nativeSum(h hash.Hash, opt config, prefix byte, data ...[]byte) ([]byte, error) {
+	h.Reset()
+	if opt.domainSeparation {
+		p := make([]byte, h.BlockSize())
+		p[len(p)-1] = prefix
+		if _, err := h.Write(p); err != nil {
+			return nil, err
+		}
+	}
+	for _, d := range data {
+		if _, err := h.Write(d); err != nil {
+			return nil, err
+		}
+	}
+	return h.Sum(nil), nil
+}
@@ -43,32 +43,69 @@ limitations under the License.
 */
 
 // Package merkle provides a ZKP-circuit function to verify merkle proofs.
+//
+// By default, leaves and nodes are hashed without domain separation, as in
+// gnark-crypto/accumulator/merkletree:
+// 		leaf = H(data)
+// 		node = H(left, right)
+// With the WithDomainSeparation option, a prefix is written to the hash function first:
+// 		leaf = H(LeafPrefix, data)
+// 		node = H(NodePrefix, left, right)
 package merkle
 
 import (
 	"github.com/consensys/gnark/frontend"
-	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/std/hash"
 )
 
-// leafSum returns the hash created from data inserted to form a leaf.
-// Without domain separation.
-func leafSum(api frontend.API, h mimc.MiMC, data frontend.Variable) frontend.Variable {
+// Domain separation prefixes, see WithDomainSeparation
+const (
+	LeafPrefix = 0
+	NodePrefix = 1
+)
 
-	h.Write(data)
-	res := h.Sum()
+// Option defines option for altering the hashing of the Merkle tree nodes.
+type Option func(opt *config)
 
-	return res
+type config struct {
+	domainSeparation bool
 }
 
-// nodeSum returns the hash created from data inserted to form a leaf.
-// Without domain separation.
-func nodeSum(api frontend.API, h mimc.MiMC, a, b frontend.Variable) frontend.Variable {
+// WithDomainSeparation is an option that writes LeafPrefix to the hash function before
+// the data of a leaf, and NodePrefix before the children of a node. This prevents a node
+// from being presented as a leaf (second preimage attack).
+func WithDomainSeparation() Option {
+	return func(opt *config) {
+		opt.domainSeparation = true
+	}
+}
 
-	h.Write(a, b)
-	//res := h.Sum(a, b)
-	res := h.Sum()
+func newConfig(opts ...Option) config {
+	var opt config
+	for _, o := range opts {
+		o(&opt)
+	}
+	return opt
+}
 
-	return res
+// leafSum returns the hash created from data inserted to form a leaf.
+func leafSum(h hash.Hash, opt config, data frontend.Variable) frontend.Variable {
+	h.Reset()
+	if opt.domainSeparation {
+		h.Write(LeafPrefix)
+	}
+	h.Write(data)
+	return h.Sum()
+}
+
+// nodeSum returns the hash created from two sibling nodes being combined into a parent node.
+func nodeSum(h hash.Hash, opt config, a, b frontend.Variable) frontend.Variable {
+	h.Reset()
+	if opt.domainSeparation {
+		h.Write(NodePrefix)
+	}
+	h.Write(a, b)
+	return h.Sum()
 }
 
 // GenerateProofHelper generates an array of 1 or 0 telling if during the proof verification
@@ -132,15 +169,18 @@ func GenerateProofHelper(proofSet [][]byte, proofIndex, numLeaves uint64) []int
 // true if the first element of the proof set is a leaf of data in the Merkle
 // root. False is returned if the proof set or Merkle root is nil, and if
 // 'numLeaves' equals 0.
-func VerifyProof(api frontend.API, h mimc.MiMC, merkleRoot frontend.Variable, proofSet, helper []frontend.Variable) {
+//
+// The hash function is reset before each leaf or node hash.
+func VerifyProof(api frontend.API, h hash.Hash, merkleRoot frontend.Variable, proofSet, helper []frontend.Variable, opts ...Option) {
+	opt := newConfig(opts...)
 
-	sum := leafSum(api, h, proofSet[0])
+	sum := leafSum(h, opt, proofSet[0])
 
 	for i := 1; i < len(proofSet); i++ {
 		api.AssertIsBoolean(helper[i-1])
 		d1 := api.Select(helper[i-1], sum, proofSet[i])
 		d2 := api.Select(helper[i-1], proofSet[i], sum)
-		sum = nodeSum(api, h, d1, d2)
+		sum = nodeSum(h, opt, d1, d2)
 	}
 
 	// Compare our calculated Merkle root to the desired Merkle root.
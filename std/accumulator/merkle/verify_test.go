@@ -18,6 +18,8 @@ package merkle
 
 import (
 	"bytes"
+	"errors"
+	"hash"
 	"os"
 	"testing"
 
@@ -40,7 +42,7 @@ func (circuit *merkleCircuit) Define(api frontend.API) error {
 	if err != nil {
 		return err
 	}
-	VerifyProof(api, hFunc, circuit.RootHash, circuit.Path, circuit.Helper)
+	VerifyProof(api, &hFunc, circuit.RootHash, circuit.Path, circuit.Helper)
 	return nil
 }
 
@@ -96,3 +98,175 @@ func TestVerify(t *testing.T) {
 	assert := test.NewAssert(t)
 	assert.ProverSucceeded(&circuit, &witness, test.WithCurves(ecc.BN254))
 }
+
+type merkleDomainSeparationCircuit struct {
+	RootHash     frontend.Variable `gnark:",public"`
+	Path, Helper []frontend.Variable
+}
+
+func (circuit *merkleDomainSeparationCircuit) Define(api frontend.API) error {
+	hFunc, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	VerifyProof(api, &hFunc, circuit.RootHash, circuit.Path, circuit.Helper, WithDomainSeparation())
+	return nil
+}
+
+func randomLeaves(t *testing.T, n int) [][]byte {
+	leaves := make([][]byte, n)
+	for i := range leaves {
+		var leaf fr.Element
+		if _, err := leaf.SetRandom(); err != nil {
+			t.Fatal(err)
+		}
+		b := leaf.Bytes()
+		leaves[i] = b[:]
+	}
+	return leaves
+}
+
+func TestVerifyDomainSeparation(t *testing.T) {
+	assert := test.NewAssert(t)
+	leaves := randomLeaves(t, 16)
+
+	root, proofSet, helper, err := BuildProof(bn254.NewMiMC(), leaves, 5, WithDomainSeparation())
+	assert.NoError(err)
+
+	// without domain separation, the tree matches gnark-crypto's
+	{
+		var buf bytes.Buffer
+		for _, l := range leaves {
+			buf.Write(l)
+		}
+		expectedRoot, expectedProof, _, err := merkletree.BuildReaderProof(&buf, bn254.NewMiMC(), fr.Bytes, 5)
+		assert.NoError(err)
+		plainRoot, plainProof, _, err := BuildProof(bn254.NewMiMC(), leaves, 5)
+		assert.NoError(err)
+		assert.Equal(expectedRoot, plainRoot)
+		assert.Equal(expectedProof, plainProof)
+		assert.NotEqual(plainRoot, root)
+	}
+
+	circuit := merkleDomainSeparationCircuit{
+		Path:   make([]frontend.Variable, len(proofSet)),
+		Helper: make([]frontend.Variable, len(helper)),
+	}
+	witness := merkleDomainSeparationCircuit{
+		RootHash: root,
+		Path:     make([]frontend.Variable, len(proofSet)),
+		Helper:   make([]frontend.Variable, len(helper)),
+	}
+	for i := range proofSet {
+		witness.Path[i] = proofSet[i]
+	}
+	for i := range helper {
+		witness.Helper[i] = helper[i]
+	}
+	assert.ProverSucceeded(&circuit, &witness, test.WithCurves(ecc.BN254))
+
+	// a node can't be presented as a leaf
+	_, nodeProofSet, nodeHelper, err := BuildProof(bn254.NewMiMC(), leaves, 4, WithDomainSeparation())
+	assert.NoError(err)
+	var forged merkleDomainSeparationCircuit
+	forged.RootHash = root
+	forged.Path = []frontend.Variable{forgedLeaf(t, bn254.NewMiMC(), leaves, nodeProofSet), nodeProofSet[2], nodeProofSet[3], nodeProofSet[4]}
+	forged.Helper = []frontend.Variable{nodeHelper[1], nodeHelper[2], nodeHelper[3]}
+	forgedCircuit := merkleDomainSeparationCircuit{
+		Path:   make([]frontend.Variable, 4),
+		Helper: make([]frontend.Variable, 3),
+	}
+	assert.ProverFailed(&forgedCircuit, &forged, test.WithCurves(ecc.BN254))
+}
+
+// forgedLeaf returns the (domain separated) parent of the leaves 4 and 5
+func forgedLeaf(t *testing.T, h hash.Hash, leaves [][]byte, proofSet [][]byte) []byte {
+	opt := newConfig(WithDomainSeparation())
+	leaf, err := nativeSum(h, opt, LeafPrefix, leaves[4])
+	if err != nil {
+		t.Fatal(err)
+	}
+	node, err := nativeSum(h, opt, NodePrefix, leaf, proofSet[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return node
+}
+
+// failingHash is a hash function rejecting the inputs starting with 0xff
+type failingHash struct {
+	hash.Hash
+}
+
+var errFailingHash = errors.New("failing hash")
+
+func (h failingHash) Write(p []byte) (int, error) {
+	if len(p) != 0 && p[0] == 0xff {
+		return 0, errFailingHash
+	}
+	return h.Hash.Write(p)
+}
+
+func TestBuildProofHashError(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	leaves := randomLeaves(t, 4)
+	leaves[3] = bytes.Repeat([]byte{0xff}, len(leaves[3]))
+	h := failingHash{bn254.NewMiMC()}
+
+	_, _, _, err := BuildProof(h, leaves, 0)
+	assert.ErrorIs(err, errFailingHash)
+	_, _, _, err = BuildProof(h, leaves, 0, WithDomainSeparation())
+	assert.ErrorIs(err, errFailingHash)
+	_, _, err = BuildMultiProof(h, leaves, []uint64{0, 1})
+	assert.ErrorIs(err, errFailingHash)
+}
+
+type multiProofCircuit struct {
+	RootHash frontend.Variable `gnark:",public"`
+	Proof    MultiProof
+}
+
+func (circuit *multiProofCircuit) Define(api frontend.API) error {
+	hFunc, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	return VerifyMultiProof(api, &hFunc, circuit.RootHash, circuit.Proof, WithDomainSeparation())
+}
+
+func TestVerifyMultiProof(t *testing.T) {
+	assert := test.NewAssert(t)
+	leaves := randomLeaves(t, 8)
+	indices := []uint64{1, 2, 3, 6}
+
+	root, proof, err := BuildMultiProof(bn254.NewMiMC(), leaves, indices, WithDomainSeparation())
+	assert.NoError(err)
+	// leaf 0, leaf 7 and node (1, 2)
+	assert.Equal(3, len(proof))
+
+	circuit := multiProofCircuit{}
+	circuit.Proof, err = NewMultiProof(3, indices)
+	assert.NoError(err)
+
+	witness := multiProofCircuit{RootHash: root}
+	witness.Proof, err = NewMultiProof(3, indices)
+	assert.NoError(err)
+	for i, idx := range indices {
+		witness.Proof.Leaves[i] = leaves[idx]
+	}
+	for i := range proof {
+		witness.Proof.Proof[i] = proof[i]
+	}
+	assert.ProverSucceeded(&circuit, &witness, test.WithCurves(ecc.BN254))
+
+	wrong := witness
+	wrong.Proof.Leaves = append([]frontend.Variable(nil), witness.Proof.Leaves...)
+	wrong.Proof.Leaves[1], wrong.Proof.Leaves[2] = wrong.Proof.Leaves[2], wrong.Proof.Leaves[1]
+	assert.ProverFailed(&circuit, &wrong, test.WithCurves(ecc.BN254))
+
+	_, err = NewMultiProof(3, []uint64{2, 1})
+	assert.Error(err)
+	_, err = NewMultiProof(3, []uint64{8})
+	assert.Error(err)
+}
@@ -0,0 +1,107 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package smt provides ZKP-circuit functions to verify proofs on a sparse Merkle tree.
+//
+// A sparse Merkle tree of depth d has 2ᵈ leaves, indexed by the key of the value they store.
+// An empty leaf is 0 and a leaf storing value at key is H(key, value); a node is H(left, right).
+// The bits of the key, from the least significant one, give the path from the leaf to the root:
+// a 1 bit means that the node is a right child.
+//
+// The native Tree generates the proof witnesses.
+package smt
+
+import (
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+)
+
+// Proof is the witness of the leaf stored at Key in a sparse Merkle tree of depth len(Siblings).
+//
+// If Empty is 1, no value is stored at Key (and Value is ignored), otherwise Value is stored at Key.
+type Proof struct {
+	Key      frontend.Variable
+	Value    frontend.Variable
+	Empty    frontend.Variable
+	Siblings []frontend.Variable // siblings of the path, from the leaf to the root
+}
+
+// NewProof returns a Proof for a tree of given depth, with unassigned variables.
+// It is meant to be used to declare a circuit.
+func NewProof(depth int) Proof {
+	return Proof{Siblings: make([]frontend.Variable, depth)}
+}
+
+// leafSum returns the hash of a leaf, 0 if the leaf is empty
+func leafSum(api frontend.API, h hash.Hash, key, value, empty frontend.Variable) frontend.Variable {
+	h.Reset()
+	h.Write(key, value)
+	return api.Select(empty, 0, h.Sum())
+}
+
+// nodeSum returns the hash of a node from its children
+func nodeSum(h hash.Hash, left, right frontend.Variable) frontend.Variable {
+	h.Reset()
+	h.Write(left, right)
+	return h.Sum()
+}
+
+// root returns the root of the tree obtained by storing the leaf in the path of the proof
+func (p *Proof) root(api frontend.API, h hash.Hash, path []frontend.Variable, value, empty frontend.Variable) frontend.Variable {
+	sum := leafSum(api, h, p.Key, value, empty)
+	for i := 0; i < len(p.Siblings); i++ {
+		left := api.Select(path[i], p.Siblings[i], sum)
+		right := api.Select(path[i], sum, p.Siblings[i])
+		sum = nodeSum(h, left, right)
+	}
+	return sum
+}
+
+// path decomposes the key in len(p.Siblings) bits; this ensures the key is in the tree
+func (p *Proof) path(api frontend.API) []frontend.Variable {
+	api.AssertIsBoolean(p.Empty)
+	return api.ToBinary(p.Key, len(p.Siblings))
+}
+
+// Root returns the root of the tree from the proof.
+func (p *Proof) Root(api frontend.API, h hash.Hash) frontend.Variable {
+	return p.root(api, h, p.path(api), p.Value, p.Empty)
+}
+
+// VerifyInclusion asserts that the proof is a valid proof that Value is stored at Key
+// in the tree of given root.
+func (p *Proof) VerifyInclusion(api frontend.API, h hash.Hash, root frontend.Variable) {
+	api.AssertIsEqual(p.Empty, 0)
+	api.AssertIsEqual(p.Root(api, h), root)
+}
+
+// VerifyExclusion asserts that the proof is a valid proof that no value is stored at Key
+// in the tree of given root.
+func (p *Proof) VerifyExclusion(api frontend.API, h hash.Hash, root frontend.Variable) {
+	api.AssertIsEqual(p.Empty, 1)
+	api.AssertIsEqual(p.Root(api, h), root)
+}
+
+// VerifyUpdate asserts that storing newValue at Key in the tree of root oldRoot
+// results in the tree of root newRoot.
+//
+// The proof is the proof of the leaf before the update: an inclusion proof if
+// the value at Key is replaced, an exclusion proof if newValue is inserted.
+func (p *Proof) VerifyUpdate(api frontend.API, h hash.Hash, oldRoot, newRoot, newValue frontend.Variable) {
+	path := p.path(api)
+	api.AssertIsEqual(p.root(api, h, path, p.Value, p.Empty), oldRoot)
+	api.AssertIsEqual(p.root(api, h, path, newValue, 0), newRoot)
+}
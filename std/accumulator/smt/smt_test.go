@@ -0,0 +1,189 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package smt
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/test"
+)
+
+const testDepth = 8
+
+type inclusionCircuit struct {
+	Root  frontend.Variable `gnark:",public"`
+	Proof Proof
+}
+
+func (circuit *inclusionCircuit) Define(api frontend.API) error {
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	circuit.Proof.VerifyInclusion(api, &h, circuit.Root)
+	return nil
+}
+
+type exclusionCircuit struct {
+	Root  frontend.Variable `gnark:",public"`
+	Proof Proof
+}
+
+func (circuit *exclusionCircuit) Define(api frontend.API) error {
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	circuit.Proof.VerifyExclusion(api, &h, circuit.Root)
+	return nil
+}
+
+type updateCircuit struct {
+	OldRoot, NewRoot frontend.Variable `gnark:",public"`
+	NewValue         frontend.Variable
+	Proof            Proof
+}
+
+func (circuit *updateCircuit) Define(api frontend.API) error {
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	circuit.Proof.VerifyUpdate(api, &h, circuit.OldRoot, circuit.NewRoot, circuit.NewValue)
+	return nil
+}
+
+func testTree(t *testing.T) *Tree {
+	tree, err := New(ecc.BN254, bn254.NewMiMC(), testDepth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, kv := range [][2]int64{{3, 42}, {4, 7}, {200, 1}} {
+		if err := tree.Set(big.NewInt(kv[0]), big.NewInt(kv[1])); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return tree
+}
+
+func TestInclusion(t *testing.T) {
+	assert := test.NewAssert(t)
+	tree := testTree(t)
+
+	proof, err := tree.Prove(big.NewInt(4))
+	assert.NoError(err)
+
+	circuit := inclusionCircuit{Proof: NewProof(testDepth)}
+	assert.ProverSucceeded(&circuit, &inclusionCircuit{Root: tree.Root(), Proof: proof}, test.WithCurves(ecc.BN254))
+
+	wrongValue := proof
+	wrongValue.Value = 8
+	assert.ProverFailed(&circuit, &inclusionCircuit{Root: tree.Root(), Proof: wrongValue}, test.WithCurves(ecc.BN254), test.WithBackends(backend.GROTH16))
+
+	// a key which is not in the tree
+	exclusion, err := tree.Prove(big.NewInt(5))
+	assert.NoError(err)
+	assert.ProverFailed(&circuit, &inclusionCircuit{Root: tree.Root(), Proof: exclusion}, test.WithCurves(ecc.BN254), test.WithBackends(backend.GROTH16))
+}
+
+func TestExclusion(t *testing.T) {
+	assert := test.NewAssert(t)
+	tree := testTree(t)
+
+	proof, err := tree.Prove(big.NewInt(5))
+	assert.NoError(err)
+
+	circuit := exclusionCircuit{Proof: NewProof(testDepth)}
+	assert.ProverSucceeded(&circuit, &exclusionCircuit{Root: tree.Root(), Proof: proof}, test.WithCurves(ecc.BN254))
+
+	// claiming that a stored key is empty
+	inclusion, err := tree.Prove(big.NewInt(3))
+	assert.NoError(err)
+	inclusion.Empty = 1
+	assert.ProverFailed(&circuit, &exclusionCircuit{Root: tree.Root(), Proof: inclusion}, test.WithCurves(ecc.BN254), test.WithBackends(backend.GROTH16))
+
+	// key out of the tree
+	outOfRange := proof
+	outOfRange.Key = 5 + (1 << testDepth)
+	assert.ProverFailed(&circuit, &exclusionCircuit{Root: tree.Root(), Proof: outOfRange}, test.WithCurves(ecc.BN254), test.WithBackends(backend.GROTH16))
+}
+
+func TestUpdate(t *testing.T) {
+	assert := test.NewAssert(t)
+	circuit := updateCircuit{Proof: NewProof(testDepth)}
+
+	for _, key := range []int64{3, 5} { // update, insertion
+		tree := testTree(t)
+		oldRoot := tree.Root()
+		proof, err := tree.Prove(big.NewInt(key))
+		assert.NoError(err)
+		assert.NoError(tree.Set(big.NewInt(key), big.NewInt(99)))
+
+		assert.ProverSucceeded(&circuit, &updateCircuit{
+			OldRoot:  oldRoot,
+			NewRoot:  tree.Root(),
+			NewValue: 99,
+			Proof:    proof,
+		}, test.WithCurves(ecc.BN254))
+
+		assert.ProverFailed(&circuit, &updateCircuit{
+			OldRoot:  oldRoot,
+			NewRoot:  tree.Root(),
+			NewValue: 98,
+			Proof:    proof,
+		}, test.WithCurves(ecc.BN254), test.WithBackends(backend.GROTH16))
+	}
+}
+
+func TestTree(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	tree, err := New(ecc.BN254, bn254.NewMiMC(), testDepth)
+	assert.NoError(err)
+	emptyRoot := tree.Root()
+	assert.Error(tree.Set(big.NewInt(1<<testDepth), big.NewInt(1)))
+
+	// values must be canonical field elements
+	assert.Error(tree.Set(big.NewInt(1), ecc.BN254.Info().Fr.Modulus()))
+	assert.Error(tree.Set(big.NewInt(1), big.NewInt(-1)))
+	assert.Equal(emptyRoot, tree.Root())
+	_, ok := tree.Get(big.NewInt(1))
+	assert.False(ok)
+
+	assert.NoError(tree.Set(big.NewInt(12), big.NewInt(1)))
+	assert.NotEqual(emptyRoot, tree.Root())
+
+	v, ok := tree.Get(big.NewInt(12))
+	assert.True(ok)
+	assert.Equal(int64(1), v.Int64())
+	_, ok = tree.Get(big.NewInt(13))
+	assert.False(ok)
+
+	// the root doesn't depend on the insertion order
+	other, err := New(ecc.BN254, bn254.NewMiMC(), testDepth)
+	assert.NoError(err)
+	assert.NoError(other.Set(big.NewInt(200), big.NewInt(1)))
+	assert.NoError(other.Set(big.NewInt(4), big.NewInt(7)))
+	assert.NoError(other.Set(big.NewInt(3), big.NewInt(42)))
+	assert.Equal(testTree(t).Root(), other.Root())
+}
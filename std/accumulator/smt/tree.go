@@ -0,0 +1,192 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package smt
+
+import (
+	"errors"
+	"hash"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+)
+
+var (
+	errKeyOutOfRange   = errors.New("key out of range")
+	errNotFieldElement = errors.New("not a canonical field element")
+)
+
+// Tree is a native sparse Merkle tree, matching the circuit definition.
+//
+// The hash function must be the native counterpart of the hash gadget used in the circuit
+// (for example gnark-crypto's MiMC for std/hash/mimc): the circuit writes field elements
+// to the gadget, and the tree writes them as big-endian byte slices of size h.BlockSize().
+// Keys and values must be canonical elements of the scalar field of the curve, in [0, r).
+type Tree struct {
+	h       hash.Hash
+	modulus *big.Int
+	depth   int
+
+	// empty[i] is the root of an empty subtree of height i
+	empty [][]byte
+
+	// nodes[i] maps the index of the non-empty nodes at height i to their hash
+	nodes []map[string][]byte
+
+	// values maps the keys to the stored values
+	values map[string]*big.Int
+}
+
+// New returns an empty sparse Merkle tree of given depth (with 2^depth leaves), for circuits
+// over the scalar field of curveID.
+func New(curveID ecc.ID, h hash.Hash, depth int) (*Tree, error) {
+	t := &Tree{
+		h:       h,
+		modulus: curveID.Info().Fr.Modulus(),
+		depth:   depth,
+		empty:   make([][]byte, depth+1),
+		nodes:   make([]map[string][]byte, depth+1),
+		values:  make(map[string]*big.Int),
+	}
+	t.empty[0] = make([]byte, h.BlockSize())
+	for i := 0; i < depth; i++ {
+		var err error
+		if t.empty[i+1], err = t.hash(t.empty[i], t.empty[i]); err != nil {
+			return nil, err
+		}
+	}
+	for i := range t.nodes {
+		t.nodes[i] = make(map[string][]byte)
+	}
+	return t, nil
+}
+
+// Depth returns the depth of the tree
+func (t *Tree) Depth() int {
+	return t.depth
+}
+
+// Root returns the root of the tree
+func (t *Tree) Root() []byte {
+	return t.node(t.depth, new(big.Int))
+}
+
+// Get returns the value stored at key and true, or nil and false if no value is stored at key
+func (t *Tree) Get(key *big.Int) (*big.Int, bool) {
+	v, ok := t.values[key.String()]
+	if !ok {
+		return nil, false
+	}
+	return new(big.Int).Set(v), true
+}
+
+// Set stores value at key and updates the nodes of the path
+//
+// The tree is left unchanged if an error is returned.
+func (t *Tree) Set(key, value *big.Int) error {
+	if key.Sign() < 0 || key.BitLen() > t.depth {
+		return errKeyOutOfRange
+	}
+	k, err := t.element(key)
+	if err != nil {
+		return err
+	}
+	v, err := t.element(value)
+	if err != nil {
+		return err
+	}
+
+	// compute the nodes of the path before updating the tree
+	path := make([][]byte, t.depth+1)
+	if path[0], err = t.hash(k, v); err != nil {
+		return err
+	}
+	index := new(big.Int).Set(key)
+	for i := 0; i < t.depth; i++ {
+		sibling := t.node(i, new(big.Int).Xor(index, big.NewInt(1)))
+		if index.Bit(0) == 1 {
+			path[i+1], err = t.hash(sibling, path[i])
+		} else {
+			path[i+1], err = t.hash(path[i], sibling)
+		}
+		if err != nil {
+			return err
+		}
+		index.Rsh(index, 1)
+	}
+
+	t.values[key.String()] = new(big.Int).Set(value)
+	index.Set(key)
+	for i := range path {
+		t.nodes[i][index.String()] = path[i]
+		index.Rsh(index, 1)
+	}
+	return nil
+}
+
+// Prove returns the witness of the leaf at key: an inclusion proof if a value is stored at key,
+// and an exclusion proof otherwise.
+func (t *Tree) Prove(key *big.Int) (Proof, error) {
+	if key.Sign() < 0 || key.BitLen() > t.depth {
+		return Proof{}, errKeyOutOfRange
+	}
+	p := Proof{
+		Key:      new(big.Int).Set(key),
+		Value:    0,
+		Empty:    1,
+		Siblings: make([]frontend.Variable, t.depth),
+	}
+	if v, ok := t.Get(key); ok {
+		p.Value = v
+		p.Empty = 0
+	}
+
+	index := new(big.Int).Set(key)
+	for i := 0; i < t.depth; i++ {
+		p.Siblings[i] = t.node(i, new(big.Int).Xor(index, big.NewInt(1)))
+		index.Rsh(index, 1)
+	}
+	return p, nil
+}
+
+// node returns the hash of the node at given height and index
+func (t *Tree) node(height int, index *big.Int) []byte {
+	if n, ok := t.nodes[height][index.String()]; ok {
+		return n
+	}
+	return t.empty[height]
+}
+
+// element returns the big-endian encoding of v on BlockSize bytes, or an error if v isn't a
+// canonical field element or doesn't fit in BlockSize bytes
+func (t *Tree) element(v *big.Int) ([]byte, error) {
+	if v.Sign() < 0 || v.Cmp(t.modulus) >= 0 || (v.BitLen()+7)/8 > t.h.BlockSize() {
+		return nil, errNotFieldElement
+	}
+	return v.FillBytes(make([]byte, t.h.BlockSize())), nil
+}
+
+func (t *Tree) hash(left, right []byte) ([]byte, error) {
+	t.h.Reset()
+	if _, err := t.h.Write(left); err != nil {
+		return nil, err
+	}
+	if _, err := t.h.Write(right); err != nil {
+		return nil, err
+	}
+	return t.h.Sum(nil), nil
+}
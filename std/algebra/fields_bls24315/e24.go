@@ -582,6 +582,37 @@ func (e *E24) DivUnchecked(api frontend.API, e1, e2 E24) *E24 {
 	return e
 }
 
+// Select sets e to r1 if b=1, r2 otherwise
+func (e *E24) Select(api frontend.API, b frontend.Variable, r1, r2 E24) *E24 {
+
+	e.D0.C0.B0.A0 = api.Select(b, r1.D0.C0.B0.A0, r2.D0.C0.B0.A0)
+	e.D0.C0.B0.A1 = api.Select(b, r1.D0.C0.B0.A1, r2.D0.C0.B0.A1)
+	e.D0.C0.B1.A0 = api.Select(b, r1.D0.C0.B1.A0, r2.D0.C0.B1.A0)
+	e.D0.C0.B1.A1 = api.Select(b, r1.D0.C0.B1.A1, r2.D0.C0.B1.A1)
+	e.D0.C1.B0.A0 = api.Select(b, r1.D0.C1.B0.A0, r2.D0.C1.B0.A0)
+	e.D0.C1.B0.A1 = api.Select(b, r1.D0.C1.B0.A1, r2.D0.C1.B0.A1)
+	e.D0.C1.B1.A0 = api.Select(b, r1.D0.C1.B1.A0, r2.D0.C1.B1.A0)
+	e.D0.C1.B1.A1 = api.Select(b, r1.D0.C1.B1.A1, r2.D0.C1.B1.A1)
+	e.D0.C2.B0.A0 = api.Select(b, r1.D0.C2.B0.A0, r2.D0.C2.B0.A0)
+	e.D0.C2.B0.A1 = api.Select(b, r1.D0.C2.B0.A1, r2.D0.C2.B0.A1)
+	e.D0.C2.B1.A0 = api.Select(b, r1.D0.C2.B1.A0, r2.D0.C2.B1.A0)
+	e.D0.C2.B1.A1 = api.Select(b, r1.D0.C2.B1.A1, r2.D0.C2.B1.A1)
+	e.D1.C0.B0.A0 = api.Select(b, r1.D1.C0.B0.A0, r2.D1.C0.B0.A0)
+	e.D1.C0.B0.A1 = api.Select(b, r1.D1.C0.B0.A1, r2.D1.C0.B0.A1)
+	e.D1.C0.B1.A0 = api.Select(b, r1.D1.C0.B1.A0, r2.D1.C0.B1.A0)
+	e.D1.C0.B1.A1 = api.Select(b, r1.D1.C0.B1.A1, r2.D1.C0.B1.A1)
+	e.D1.C1.B0.A0 = api.Select(b, r1.D1.C1.B0.A0, r2.D1.C1.B0.A0)
+	e.D1.C1.B0.A1 = api.Select(b, r1.D1.C1.B0.A1, r2.D1.C1.B0.A1)
+	e.D1.C1.B1.A0 = api.Select(b, r1.D1.C1.B1.A0, r2.D1.C1.B1.A0)
+	e.D1.C1.B1.A1 = api.Select(b, r1.D1.C1.B1.A1, r2.D1.C1.B1.A1)
+	e.D1.C2.B0.A0 = api.Select(b, r1.D1.C2.B0.A0, r2.D1.C2.B0.A0)
+	e.D1.C2.B0.A1 = api.Select(b, r1.D1.C2.B0.A1, r2.D1.C2.B0.A1)
+	e.D1.C2.B1.A0 = api.Select(b, r1.D1.C2.B1.A0, r2.D1.C2.B1.A0)
+	e.D1.C2.B1.A1 = api.Select(b, r1.D1.C2.B1.A1, r2.D1.C2.B1.A1)
+
+	return e
+}
+
 // nSquareCompressed repeated compressed cyclotmic square
 func (e *E24) nSquareCompressed(api frontend.API, n int) {
 	for i := 0; i < n; i++ {
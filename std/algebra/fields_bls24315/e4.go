@@ -143,6 +143,13 @@ func (e *E4) Conjugate(api frontend.API, e1 E4) *E4 {
 	return e
 }
 
+// Frobenius applies frob to an fp4 elmt
+func (e *E4) Frobenius(api frontend.API, e1 E4) *E4 {
+	e.B0.Conjugate(api, e1.B0)
+	e.B1.Conjugate(api, e1.B1).MulByFp(api, e.B1, ext.frobCoeff0)
+	return e
+}
+
 var DivE4Hint = func(curve ecc.ID, inputs []*big.Int, res []*big.Int) error {
 	var a, b, c bls24315.E4
 
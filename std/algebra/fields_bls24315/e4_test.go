@@ -208,6 +208,36 @@ func TestConjugateFp4(t *testing.T) {
 	assert.SolvingSucceeded(&circuit, &witness, test.WithCurves(ecc.BW6_633))
 }
 
+type fp4Frobenius struct {
+	A E4
+	C E4 `gnark:",public"`
+}
+
+func (circuit *fp4Frobenius) Define(api frontend.API) error {
+	expected := E4{}
+	expected.Frobenius(api, circuit.A)
+
+	expected.AssertIsEqual(api, circuit.C)
+	return nil
+}
+
+func TestFrobeniusFp4(t *testing.T) {
+
+	var circuit, witness fp4Frobenius
+
+	// witness values
+	var a, c bls24315.E4
+	a.SetRandom()
+	c.Frobenius(&a)
+
+	witness.A.Assign(&a)
+
+	witness.C.Assign(&c)
+
+	assert := test.NewAssert(t)
+	assert.SolvingSucceeded(&circuit, &witness, test.WithCurves(ecc.BW6_633))
+}
+
 type e4Div struct {
 	A, B, C E4
 }
@@ -0,0 +1,543 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sw_bls12377
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fp"
+	"github.com/consensys/gnark/backend/hint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/fields_bls12377"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/math/bits"
+)
+
+// hashToFpLen is the number of bytes L hashed to an element of Fp, as in gnark-crypto
+// (L = ceil((ceil(log2(p)) + k) / 8) with the security parameter k = 128)
+const hashToFpLen = 64
+
+// Hashing to G1 and G2 follows RFC 9380, with the Shallue-van de Woestijne map (section 6.6.1)
+// and the constants of gnark-crypto: MapToG1 and MapToG2 are exactly gnark-crypto's
+// MapToCurveG1Svdw and MapToCurveG2Svdw.
+//
+// gnark-crypto doesn't use the sign rule of the RFC: it negates the square root y it computes
+// unless sign0(u) and sign0(y), where sign0(v) holds iff v ≤ -v. The result depends on the square
+// root returned by its Tonelli-Shanks algorithm, which the circuits check: for q - 1 = 2ˢ⋅t with t
+// odd, it returns y = a^((t+1)/2) ⋅ gᵉ where g = nonResidue^t and e < 2ˢ⁻¹. The square root in E2
+// is computed as in gnark-crypto, with a square root in Fp.
+//
+// The quadratic residuosity of the candidate abscissas, the square roots and their exponents e are
+// provided by hints and checked in the circuit.
+//
+// EncodeToG1, HashToG1, EncodeToG2 and HashToG2 hash bytes to field elements with hash_to_field
+// (RFC 9380, section 5) and expand_message_xmd with SHA-256 (hash.ExpandMsgXmd), as gnark-crypto's
+// EncodeToCurveG1Svdw, HashToCurveG1Svdw, EncodeToCurveG2Svdw and HashToCurveG2Svdw. The field
+// elements are integers of hashToFpLen bytes, reduced for free since Fp is the field of the circuit;
+// the cost is mostly the one of SHA-256 (about 26000 R1CS constraints per block of 64 bytes), see
+// hash.ExpandMsgXmd.
+
+// constants of the Shallue-van de Woestijne map to G1, with Z = 1
+// (c1 = g(Z), c2 = -Z/2, c3 = sqrt(-g(Z)⋅3Z²), c4 = -4g(Z)/3Z²)
+var (
+	svdwC1 = big.NewInt(2)
+	svdwC2 = newInt("129332213006484547005326366847446766768196756377457330269942131333360234174170411387484444069786680062220160729088")
+	svdwC3 = newInt("97648839010665214827241242728596775338087731732850880761532715038339062821120154619091300503722809961039397351015")
+	svdwC4 = newInt("172442950675312729340435155796595689024262341836609773693256175111146978898893881849979258759715573416293547638782")
+)
+
+// constants of the Shallue-van de Woestijne map to G2
+var (
+	svdwZG2 = fields_bls12377.E2{
+		A0: newInt("258664426012969094010652733694893533536393512754914660539884262666720468348340822774968888139573360124440321458176"),
+		A1: newInt("258664426012969094010652733694893533536393512754914660539884262666720468348340822774968888139573360124440321458176"),
+	}
+	svdwC1G2 = fields_bls12377.E2{
+		A0: newInt("14"),
+		A1: newInt("155198655607781456406391640216936120121836107652948796323930557600032281009004493664981332883744016074664192874908"),
+	}
+	svdwC2G2 = fields_bls12377.E2{
+		A0: newInt("129332213006484547005326366847446766768196756377457330269942131333360234174170411387484444069786680062220160729089"),
+		A1: newInt("129332213006484547005326366847446766768196756377457330269942131333360234174170411387484444069786680062220160729089"),
+	}
+	svdwC3G2 = fields_bls12377.E2{
+		A0: newInt("136095077907295642446400609387283897242604839345760820213771221369353216046335543369711291750177599683819073207865"),
+		A1: newInt("219542929730243627071993942291259566263609334675640614684215562523644237982906136121780647979889503975135681203128"),
+	}
+	svdwC4G2 = fields_bls12377.E2{
+		A0: newInt("182023114601718992081570442229739893970054694160865872231770407061766255504387986397200328690810883050532078063163"),
+		A1: newInt("13412229496968767837589401006401886924109293253958537953919924730866987247691746366109497903533433487933942594129"),
+	}
+)
+
+var (
+	// nonResidue is the smallest quadratic non-residue of Fp
+	nonResidue *big.Int
+
+	// nonResidueE2 is a quadratic non-residue of E2
+	nonResidueE2 bls12377.E2
+
+	// bTwist is the constant of the twist y² = x³ + bTwist on which G2 is defined
+	bTwist bls12377.E2
+
+	// q - 1 = 2^sqrtS ⋅ sqrtT with sqrtT odd; sqrtExp = (sqrtT+1)/2 and sqrtRoots[j] = g^(2ʲ) for
+	// g = nonResidue^sqrtT, the parameters of the Tonelli-Shanks square root of gnark-crypto
+	sqrtS     int
+	sqrtExp   *big.Int
+	sqrtRoots []*big.Int
+
+	// sqrtExpE2 = (q-1)/4, sqrtE2E and sqrtE2F are the constants of the square root in E2 of
+	// gnark-crypto (https://eprint.iacr.org/2012/685.pdf, algorithm 10)
+	sqrtExpE2        *big.Int
+	sqrtE2E, sqrtE2F bls12377.E2
+)
+
+func init() {
+	nonResidue = big.NewInt(2)
+	for big.Jacobi(nonResidue, fp.Modulus()) != -1 {
+		nonResidue.Add(nonResidue, big.NewInt(1))
+	}
+
+	nonResidueE2.A1.SetOne()
+	for nonResidueE2.Legendre() != -1 {
+		nonResidueE2.A0.Add(&nonResidueE2.A0, &nonResidueE2.A1)
+	}
+
+	// bTwist = y² - x³ on the generator of G2
+	var x3 bls12377.E2
+	_, _, _, g2 := bls12377.Generators()
+	bTwist.Square(&g2.Y)
+	x3.Square(&g2.X).Mul(&x3, &g2.X)
+	bTwist.Sub(&bTwist, &x3)
+
+	q := fp.Modulus()
+	t := new(big.Int).Sub(q, big.NewInt(1))
+	for t.Bit(0) == 0 {
+		t.Rsh(t, 1)
+		sqrtS++
+	}
+	sqrtExp = new(big.Int).Add(t, big.NewInt(1))
+	sqrtExp.Rsh(sqrtExp, 1)
+	g := new(big.Int).Exp(nonResidue, t, q)
+	sqrtRoots = make([]*big.Int, sqrtS-1)
+	for j := range sqrtRoots {
+		sqrtRoots[j] = new(big.Int).Set(g)
+		g.Mul(g, g).Mod(g, q)
+	}
+
+	// c = (0, 1) is a non-square, d = c^((q-1)/2), e = (d⋅c)⁻¹ and f = (d⋅c)²
+	var c, dc bls12377.E2
+	c.A1.SetOne()
+	exp := new(big.Int).Sub(q, big.NewInt(1))
+	exp.Rsh(exp, 1)
+	dc.Exp(c, exp).Mul(&dc, &c)
+	sqrtE2E.Inverse(&dc)
+	sqrtE2F.Square(&dc)
+	sqrtExpE2 = exp.Rsh(exp, 1)
+}
+
+// MapToCurve sets p to the image of u by the Shallue-van de Woestijne map, a point of the curve
+// which is not necessarily in G1, and returns p.
+//
+// The map fails on the (negligible) set of u such that 1 - 4u⁴ = 0.
+func (p *G1Affine) MapToCurve(api frontend.API, u frontend.Variable) *G1Affine {
+	tv1 := api.Mul(u, u, svdwC1)
+	tv2 := api.Add(1, tv1)
+	tv1 = api.Sub(1, tv1)
+	tv3 := api.Inverse(api.Mul(tv1, tv2))
+	tv4 := api.Mul(u, tv1, tv3, svdwC3)
+	x1 := api.Sub(svdwC2, tv4)
+	x2 := api.Add(svdwC2, tv4)
+	x3 := api.Mul(tv2, tv2, tv3)
+	x3 = api.Add(api.Mul(x3, x3, svdwC4), 1)
+
+	// x = x1 if g(x1) is a square, else x2 if g(x2) is a square, else x3
+	e1 := isSquare(api, g1(api, x1))
+	e2 := isSquare(api, g1(api, x2))
+	x := api.Select(e1, x1, api.Select(e2, x2, x3))
+
+	// y = ±sqrt(g(x)), with the sign rule of gnark-crypto
+	y := sqrt(api, g1(api, x))
+	y = api.Select(api.And(sign0(api, u), sign0(api, y)), y, api.Neg(y))
+
+	p.X, p.Y = x, y
+	return p
+}
+
+// ClearCofactor sets p to [1-x]q, which is in G1 for q on the curve, and returns p
+func (p *G1Affine) ClearCofactor(api frontend.API, q G1Affine) *G1Affine {
+	*p = subG1(api, q, scalarMulSeedG1(api, q))
+	return p
+}
+
+// MapToG1 maps the field element u to G1 (the encoding of RFC 9380 with the field element already
+// hashed), as gnark-crypto's MapToCurveG1Svdw.
+func MapToG1(api frontend.API, u frontend.Variable) G1Affine {
+	var res G1Affine
+	res.MapToCurve(api, u).ClearCofactor(api, res)
+	return res
+}
+
+// EncodeToG1 hashes the bytes msg to G1, with a non-uniform distribution (encode_to_curve, RFC 9380
+// section 3) and the domain separation tag dst, as gnark-crypto's EncodeToCurveG1Svdw.
+func EncodeToG1(api frontend.API, dst []byte, msg ...frontend.Variable) (G1Affine, error) {
+	u, err := hashToFp(api, msg, dst, 1)
+	if err != nil {
+		return G1Affine{}, err
+	}
+	return MapToG1(api, u[0]), nil
+}
+
+// HashToG1 hashes the bytes msg to G1, with a uniform distribution (hash_to_curve, RFC 9380
+// section 3) and the domain separation tag dst, as gnark-crypto's HashToCurveG1Svdw.
+func HashToG1(api frontend.API, dst []byte, msg ...frontend.Variable) (G1Affine, error) {
+	u, err := hashToFp(api, msg, dst, 2)
+	if err != nil {
+		return G1Affine{}, err
+	}
+	var q0, q1 G1Affine
+	q0.MapToCurve(api, u[0])
+	q1.MapToCurve(api, u[1])
+	var res G1Affine
+	res.ClearCofactor(api, addG1(api, q0, q1))
+	return res, nil
+}
+
+// MapToCurve sets p to the image of u by the Shallue-van de Woestijne map, a point of the twist
+// which is not necessarily in G2, and returns p.
+//
+// The map fails on the (negligible) set of u such that (1 - c1⋅u²)(1 + c1⋅u²) = 0.
+func (p *G2Affine) MapToCurve(api frontend.API, u fields_bls12377.E2) *G2Affine {
+	var tv1, tv2, tv3, tv4, x1, x2, x3 fields_bls12377.E2
+	tv1.Square(api, u).Mul(api, tv1, svdwC1G2)
+	tv2.Add(api, one(), tv1)
+	tv1.Sub(api, one(), tv1)
+	tv3.Mul(api, tv1, tv2).Inverse(api, tv3)
+	tv4.Mul(api, u, tv1).Mul(api, tv4, tv3).Mul(api, tv4, svdwC3G2)
+	x1.Sub(api, svdwC2G2, tv4)
+	x2.Add(api, svdwC2G2, tv4)
+	x3.Square(api, tv2).Mul(api, x3, tv3).Square(api, x3).Mul(api, x3, svdwC4G2).Add(api, x3, svdwZG2)
+
+	// x = x1 if g(x1) is a square, else x2 if g(x2) is a square, else x3
+	e1 := isSquareE2(api, g2(api, x1))
+	e2 := isSquareE2(api, g2(api, x2))
+	var x fields_bls12377.E2
+	x.Select(api, e2, x2, x3)
+	x.Select(api, e1, x1, x)
+
+	// y = ±sqrt(g(x)), with the sign rule of gnark-crypto
+	y := sqrtE2(api, g2(api, x))
+	var negY fields_bls12377.E2
+	negY.Neg(api, y)
+	y.Select(api, api.And(sign0(api, u.A0), sign0(api, y.A0)), y, negY)
+
+	p.X, p.Y = x, y
+	return p
+}
+
+// ClearCofactor sets p to [h_eff]q, which is in G2 for q on the twist, and returns p.
+// It uses the method of Budroni-Pintore (https://eprint.iacr.org/2017/419.pdf, 4.1), as gnark-crypto:
+// [h_eff]q = [x²-x-1]q + ψ([x-1]q) - (ω⋅x₂, y₂) where (x₂, y₂) = 2q.
+func (p *G2Affine) ClearCofactor(api frontend.API, q G2Affine) *G2Affine {
+	xq := scalarMulSeedG2(api, q)
+	xxq := scalarMulSeedG2(api, xq)
+	res := subG2(api, subG2(api, xxq, xq), q)
+	res = addG2(api, res, psi(api, subG2(api, xq, q)))
+
+	t := doubleG2(api, q)
+	t.X.MulByFp(api, t.X, innerCurve(api.Compiler().Curve()).thirdRootOne1)
+	*p = subG2(api, res, t)
+	return p
+}
+
+// MapToG2 maps the E2 element u to G2 (the encoding of RFC 9380 with the field element already
+// hashed), as gnark-crypto's MapToCurveG2Svdw.
+func MapToG2(api frontend.API, u fields_bls12377.E2) G2Affine {
+	var res G2Affine
+	res.MapToCurve(api, u).ClearCofactor(api, res)
+	return res
+}
+
+// EncodeToG2 hashes the bytes msg to G2, with a non-uniform distribution (encode_to_curve, RFC 9380
+// section 3) and the domain separation tag dst, as gnark-crypto's EncodeToCurveG2Svdw.
+func EncodeToG2(api frontend.API, dst []byte, msg ...frontend.Variable) (G2Affine, error) {
+	u, err := hashToFp(api, msg, dst, 2)
+	if err != nil {
+		return G2Affine{}, err
+	}
+	return MapToG2(api, fields_bls12377.E2{A0: u[0], A1: u[1]}), nil
+}
+
+// HashToG2 hashes the bytes msg to G2, with a uniform distribution (hash_to_curve, RFC 9380
+// section 3) and the domain separation tag dst, as gnark-crypto's HashToCurveG2Svdw.
+func HashToG2(api frontend.API, dst []byte, msg ...frontend.Variable) (G2Affine, error) {
+	u, err := hashToFp(api, msg, dst, 4)
+	if err != nil {
+		return G2Affine{}, err
+	}
+	var q0, q1 G2Affine
+	q0.MapToCurve(api, fields_bls12377.E2{A0: u[0], A1: u[1]})
+	q1.MapToCurve(api, fields_bls12377.E2{A0: u[2], A1: u[3]})
+	var res G2Affine
+	res.ClearCofactor(api, addG2(api, q0, q1))
+	return res, nil
+}
+
+// NativeHashToG1 is HashToG1 out of a circuit, that is gnark-crypto's HashToCurveG1Svdw
+func NativeHashToG1(msg, dst []byte) (bls12377.G1Affine, error) {
+	return bls12377.HashToCurveG1Svdw(msg, dst)
+}
+
+// hashToFp hashes the bytes msg to count elements of Fp (hash_to_field, RFC 9380 section 5), which
+// is the scalar field of the circuit: the big-endian integers of hashToFpLen bytes are reduced
+// modulo p by the linear combinations.
+func hashToFp(api frontend.API, msg []frontend.Variable, dst []byte, count int) ([]frontend.Variable, error) {
+	b, err := hash.ExpandMsgXmd(api, msg, dst, count*hashToFpLen)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]frontend.Variable, count)
+	for i := range res {
+		res[i] = 0
+		for _, c := range b[i*hashToFpLen : (i+1)*hashToFpLen] {
+			res[i] = api.Add(api.Mul(res[i], 256), c)
+		}
+	}
+	return res, nil
+}
+
+// one returns 1 in E2
+func one() fields_bls12377.E2 {
+	var res fields_bls12377.E2
+	res.SetOne()
+	return res
+}
+
+// g1 returns x³ + 1
+func g1(api frontend.API, x frontend.Variable) frontend.Variable {
+	return api.Add(api.Mul(x, x, x), 1)
+}
+
+// g2 returns x³ + bTwist
+func g2(api frontend.API, x fields_bls12377.E2) fields_bls12377.E2 {
+	var res, b fields_bls12377.E2
+	b.Assign(&bTwist)
+	res.Square(api, x).Mul(api, res, x).Add(api, res, b)
+	return res
+}
+
+// isSquare returns 1 if a is a square, 0 otherwise: a (resp. nonResidue⋅a) is a square, whose
+// square root is provided by a hint.
+func isSquare(api frontend.API, a frontend.Variable) frontend.Variable {
+	res, err := api.Compiler().NewHint(IsSquareHint, 2, a)
+	if err != nil {
+		// err is non-nil only for invalid number of inputs
+		panic(err)
+	}
+	e, s := res[0], res[1]
+	api.AssertIsBoolean(e)
+	api.AssertIsEqual(api.Mul(s, s), api.Select(e, a, api.Mul(a, nonResidue)))
+	return e
+}
+
+// isSquareE2 returns 1 if a is a square in E2, 0 otherwise, as isSquare
+func isSquareE2(api frontend.API, a fields_bls12377.E2) frontend.Variable {
+	res, err := api.Compiler().NewHint(IsSquareE2Hint, 3, a.A0, a.A1)
+	if err != nil {
+		// err is non-nil only for invalid number of inputs
+		panic(err)
+	}
+	e := res[0]
+	api.AssertIsBoolean(e)
+	var s2, na, nr fields_bls12377.E2
+	s2.Square(api, fields_bls12377.E2{A0: res[1], A1: res[2]})
+	nr.Assign(&nonResidueE2)
+	na.Mul(api, a, nr).Select(api, e, a, na)
+	s2.AssertIsEqual(api, na)
+	return e
+}
+
+// sign0 returns 1 if v ≤ -v as integers, 0 otherwise, as gnark-crypto's sign0: v = w if
+// w ≤ (q-1)/2, else v = -w with w ≠ 0.
+func sign0(api frontend.API, v frontend.Variable) frontend.Variable {
+	res, err := api.Compiler().NewHint(Sign0Hint, 2, v)
+	if err != nil {
+		// err is non-nil only for invalid number of inputs
+		panic(err)
+	}
+	b, w := res[0], res[1]
+	api.AssertIsBoolean(b)
+	api.AssertIsEqual(w, api.Select(b, v, api.Neg(v)))
+	api.AssertIsLessOrEqual(w, new(big.Int).Rsh(fp.Modulus(), 1))
+	api.AssertIsEqual(api.Mul(api.Sub(1, b), api.IsZero(w)), 0)
+	return b
+}
+
+// sqrt returns the square root of the square a computed by gnark-crypto's fp.Element.Sqrt:
+// y = a^sqrtExp ⋅ gᵉ with y² = a and e < 2^(sqrtS-1), where gᵉ = Π sqrtRoots[j]^eⱼ.
+func sqrt(api frontend.API, a frontend.Variable) frontend.Variable {
+	res, err := api.Compiler().NewHint(SqrtHint, 2, a)
+	if err != nil {
+		// err is non-nil only for invalid number of inputs
+		panic(err)
+	}
+	y, e := res[0], res[1]
+	api.AssertIsEqual(api.Mul(y, y), a)
+
+	ge := frontend.Variable(1)
+	for j, b := range bits.ToBinary(api, e, bits.WithNbDigits(sqrtS-1)) {
+		ge = api.Mul(ge, api.Select(b, sqrtRoots[j], 1))
+	}
+	api.AssertIsEqual(y, api.Mul(exp(api, a, sqrtExp), ge))
+	return y
+}
+
+// sqrtE2 returns the square root of the square a computed by gnark-crypto's E2.Sqrt
+func sqrtE2(api frontend.API, a fields_bls12377.E2) fields_bls12377.E2 {
+	// b = a^((q-1)/4), and norm(b) = b.A0² + 5⋅b.A1² is ±1
+	b := expE2(api, a, sqrtExpE2)
+	isOne := api.IsZero(api.Sub(api.Add(api.Mul(b.A0, b.A0), api.Mul(b.A1, b.A1, 5)), 1))
+	var one, e, f fields_bls12377.E2
+	one.SetOne()
+	e.Assign(&sqrtE2E)
+	f.Assign(&sqrtE2F)
+	e.Select(api, isOne, one, e)
+	f.Select(api, isOne, one, f)
+
+	// x0 = b²⋅a (⋅f) is in Fp, and the root is conj(b)⋅sqrt(x0) (⋅e)
+	var x0, res fields_bls12377.E2
+	x0.Square(api, b).Mul(api, x0, a).Mul(api, x0, f)
+	res.Conjugate(api, b).MulByFp(api, res, sqrt(api, x0.A0)).Mul(api, res, e)
+
+	var res2 fields_bls12377.E2
+	res2.Square(api, res)
+	res2.AssertIsEqual(api, a)
+	return res
+}
+
+// exp returns a^k, for a constant k > 0
+func exp(api frontend.API, a frontend.Variable, k *big.Int) frontend.Variable {
+	res := a
+	for i := k.BitLen() - 2; i >= 0; i-- {
+		res = api.Mul(res, res)
+		if k.Bit(i) == 1 {
+			res = api.Mul(res, a)
+		}
+	}
+	return res
+}
+
+// expE2 returns a^k in E2, for a constant k > 0
+func expE2(api frontend.API, a fields_bls12377.E2, k *big.Int) fields_bls12377.E2 {
+	res := a
+	for i := k.BitLen() - 2; i >= 0; i-- {
+		res.Square(api, res)
+		if k.Bit(i) == 1 {
+			res.Mul(api, res, a)
+		}
+	}
+	return res
+}
+
+// IsSquareHint returns (1, sqrt(a)) if a is a square in Fp, and (0, sqrt(nonResidue⋅a)) otherwise
+var IsSquareHint = func(_ ecc.ID, inputs []*big.Int, results []*big.Int) error {
+	q := fp.Modulus()
+	a := new(big.Int).Mod(inputs[0], q)
+	if big.Jacobi(a, q) >= 0 {
+		results[0].SetUint64(1)
+	} else {
+		results[0].SetUint64(0)
+		a.Mul(a, nonResidue).Mod(a, q)
+	}
+	results[1].ModSqrt(a, q)
+	return nil
+}
+
+// SqrtHint returns the square root y of inputs[0] in Fp computed by gnark-crypto, and the exponent
+// e < 2^(sqrtS-1) such that y = inputs[0]^sqrtExp ⋅ gᵉ, see sqrt
+var SqrtHint = func(_ ecc.ID, inputs []*big.Int, results []*big.Int) error {
+	q := fp.Modulus()
+	var a fp.Element
+	a.SetBigInt(inputs[0])
+	if a.Sqrt(&a) == nil {
+		return errors.New("no square root")
+	}
+	y := a.ToBigIntRegular(results[0])
+	results[1].SetUint64(0)
+	if y.Sign() == 0 {
+		return nil
+	}
+
+	// gᵉ = y / inputs[0]^sqrtExp, whose discrete logarithm is computed bit by bit in the subgroup
+	// of order 2^sqrtS
+	ge := new(big.Int).Exp(inputs[0], sqrtExp, q)
+	ge.ModInverse(ge, q).Mul(ge, y).Mod(ge, q)
+	t := new(big.Int)
+	for j := 0; j < sqrtS; j++ {
+		t.Exp(ge, new(big.Int).Lsh(big.NewInt(1), uint(sqrtS-1-j)), q)
+		if t.Cmp(big.NewInt(1)) == 0 {
+			continue
+		}
+		if j == sqrtS-1 {
+			return errors.New("unexpected square root")
+		}
+		results[1].SetBit(results[1], j, 1)
+		ge.Mul(ge, new(big.Int).ModInverse(sqrtRoots[j], q)).Mod(ge, q)
+	}
+	return nil
+}
+
+// Sign0Hint returns (1, v) if v = inputs[0] ≤ -v as integers, (0, -v) otherwise
+var Sign0Hint = func(_ ecc.ID, inputs []*big.Int, results []*big.Int) error {
+	q := fp.Modulus()
+	v := new(big.Int).Mod(inputs[0], q)
+	if v.Cmp(new(big.Int).Rsh(q, 1)) <= 0 {
+		results[0].SetUint64(1)
+		results[1].Set(v)
+	} else {
+		results[0].SetUint64(0)
+		results[1].Sub(q, v)
+	}
+	return nil
+}
+
+// IsSquareE2Hint returns (1, sqrt(a)) if a is a square in E2, and (0, sqrt(nonResidueE2⋅a)) otherwise
+var IsSquareE2Hint = func(_ ecc.ID, inputs []*big.Int, results []*big.Int) error {
+	var a bls12377.E2
+	a.A0.SetBigInt(inputs[0])
+	a.A1.SetBigInt(inputs[1])
+	if a.Legendre() >= 0 {
+		results[0].SetUint64(1)
+	} else {
+		results[0].SetUint64(0)
+		a.Mul(&a, &nonResidueE2)
+	}
+	a.Sqrt(&a)
+	a.A0.ToBigIntRegular(results[1])
+	a.A1.ToBigIntRegular(results[2])
+	return nil
+}
+
+func init() {
+	hint.Register(IsSquareHint)
+	hint.Register(SqrtHint)
+	hint.Register(Sign0Hint)
+	hint.Register(IsSquareE2Hint)
+}
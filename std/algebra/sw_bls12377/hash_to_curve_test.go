@@ -0,0 +1,160 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sw_bls12377
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fp"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/fields_bls12377"
+	"github.com/consensys/gnark/test"
+)
+
+var testDST = []byte("gnark-sw_bls12377-hash-to-curve-test")
+
+const testMsg = "abc"
+
+type mapToG1Circuit struct {
+	U        frontend.Variable
+	Expected G1Affine
+}
+
+func (circuit *mapToG1Circuit) Define(api frontend.API) error {
+	p := MapToG1(api, circuit.U)
+	api.AssertIsEqual(p.X, circuit.Expected.X)
+	api.AssertIsEqual(p.Y, circuit.Expected.Y)
+	return nil
+}
+
+type hashToG1Circuit struct {
+	Msg      [len(testMsg)]frontend.Variable
+	Expected G1Affine
+}
+
+func (circuit *hashToG1Circuit) Define(api frontend.API) error {
+	p, err := HashToG1(api, testDST, circuit.Msg[:]...)
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(p.X, circuit.Expected.X)
+	api.AssertIsEqual(p.Y, circuit.Expected.Y)
+	return nil
+}
+
+func TestMapToG1(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	// u and -u cover both cases of the sign rule
+	var u fp.Element
+	_, _ = u.SetRandom()
+	for _, u := range []fp.Element{u, *new(fp.Element).Neg(&u)} {
+		q := bls12377.MapToCurveG1Svdw(u)
+
+		var witness mapToG1Circuit
+		witness.U = u.ToBigIntRegular(new(big.Int))
+		witness.Expected.Assign(&q)
+		assert.SolvingSucceeded(&mapToG1Circuit{}, &witness, test.WithCurves(ecc.BW6_761))
+
+		q.Neg(&q)
+		witness.Expected.Assign(&q)
+		assert.SolvingFailed(&mapToG1Circuit{}, &witness, test.WithCurves(ecc.BW6_761))
+	}
+}
+
+func TestHashToG1(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	q, err := bls12377.HashToCurveG1Svdw([]byte(testMsg), testDST)
+	assert.NoError(err)
+
+	var witness hashToG1Circuit
+	for i := range testMsg {
+		witness.Msg[i] = testMsg[i]
+	}
+	witness.Expected.Assign(&q)
+	assert.SolvingSucceeded(&hashToG1Circuit{}, &witness, test.WithCurves(ecc.BW6_761))
+
+	witness.Msg[0] = testMsg[0] ^ 1
+	assert.SolvingFailed(&hashToG1Circuit{}, &witness, test.WithCurves(ecc.BW6_761))
+}
+
+type mapToG2Circuit struct {
+	U        fields_bls12377.E2
+	Expected G2Affine
+}
+
+func (circuit *mapToG2Circuit) Define(api frontend.API) error {
+	p := MapToG2(api, circuit.U)
+	p.X.AssertIsEqual(api, circuit.Expected.X)
+	p.Y.AssertIsEqual(api, circuit.Expected.Y)
+	return nil
+}
+
+type hashToG2Circuit struct {
+	Msg      [len(testMsg)]frontend.Variable
+	Expected G2Affine
+}
+
+func (circuit *hashToG2Circuit) Define(api frontend.API) error {
+	p, err := HashToG2(api, testDST, circuit.Msg[:]...)
+	if err != nil {
+		return err
+	}
+	p.X.AssertIsEqual(api, circuit.Expected.X)
+	p.Y.AssertIsEqual(api, circuit.Expected.Y)
+	return nil
+}
+
+func TestMapToG2(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	// u and -u cover both cases of the sign rule
+	var u bls12377.E2
+	_, _ = u.A0.SetRandom()
+	_, _ = u.A1.SetRandom()
+	for _, u := range []bls12377.E2{u, *new(bls12377.E2).Neg(&u)} {
+		q := bls12377.MapToCurveG2Svdw(u)
+		assert.True(q.IsInSubGroup())
+
+		var witness mapToG2Circuit
+		witness.U.Assign(&u)
+		witness.Expected.Assign(&q)
+		assert.SolvingSucceeded(&mapToG2Circuit{}, &witness, test.WithCurves(ecc.BW6_761))
+
+		q.Neg(&q)
+		witness.Expected.Assign(&q)
+		assert.SolvingFailed(&mapToG2Circuit{}, &witness, test.WithCurves(ecc.BW6_761))
+	}
+}
+
+func TestHashToG2(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	q, err := bls12377.HashToCurveG2Svdw([]byte(testMsg), testDST)
+	assert.NoError(err)
+
+	var witness hashToG2Circuit
+	for i := range testMsg {
+		witness.Msg[i] = testMsg[i]
+	}
+	witness.Expected.Assign(&q)
+	assert.SolvingSucceeded(&hashToG2Circuit{}, &witness, test.WithCurves(ecc.BW6_761))
+}
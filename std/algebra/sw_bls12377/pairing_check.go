@@ -0,0 +1,234 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sw_bls12377
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fp"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	"github.com/consensys/gnark/backend/hint"
+	"github.com/consensys/gnark/frontend"
+)
+
+// The pairing check follows https://eprint.iacr.org/2024/640.pdf (Novakovic, Eagen): instead of
+// computing the final exponentiation of the Miller loop output f, the prover shows that f is an
+// r-th power, up to a factor which the final exponentiation would cancel.
+//
+// λ = p - x is a multiple of r, and the prover provides c and s such that f⋅s = c^λ. Then
+// f^((p¹²-1)/r) = s^(-(p¹²-1)/r), which is 1 for s in Fp6 (of order p⁶-1, which divides (p¹²-1)/r)
+// or in the subgroup of order 2^48 of Fp12. Conversely, gcd(λ, p¹²-1) = 2^48⋅3⋅7⋅13⋅499⋅r, and the
+// components of f of order 3ᵏ, 7, 13 or 499 are in Fp6, as is the square of its component of order
+// 2ᵏ: s = s'⋅wᵇ, where s' is in Fp6 and w is a fixed element of order 2^48, makes f⋅s a λ-th power.
+//
+// There is no easy part: the check costs an exponentiation by x with full squarings, instead of
+// the easy part and five exponentiations by x with cyclotomic squarings of FinalExponentiation.
+
+var (
+	// residueComponentExponent raises an element of order dividing (p¹²-1)/r to its component of
+	// order dividing residueSmooth, the part of (p¹²-1)/r made of the primes of gcd(λ, p¹²-1)/r
+	residueComponentExponent *big.Int
+
+	// residueExponent is λ⁻¹ mod residueCofactor, where residueCofactor = (p¹²-1)/(r⋅residueSmooth)
+	residueExponent *big.Int
+
+	// residueScaling is an element of order 2^48, the 2-adic valuation of p¹²-1
+	residueScaling bls12377.E12
+
+	// p⁶-1
+	fp6Order *big.Int
+)
+
+func init() {
+	p, r := fp.Modulus(), fr.Modulus()
+
+	p6 := new(big.Int).Exp(p, big.NewInt(6), nil)
+	fp6Order = new(big.Int).Sub(p6, big.NewInt(1))
+	n := new(big.Int).Mul(p6, p6)
+	n.Sub(n, big.NewInt(1))
+	order := new(big.Int).Set(n)
+	n.Div(n, r)
+
+	lambda := new(big.Int).Sub(p, xGen)
+	d := new(big.Int).GCD(nil, nil, lambda, order)
+	d.Div(d, r)
+
+	// (p¹²-1)/r = residueSmooth ⋅ residueCofactor, with gcd(residueCofactor, λ) = 1
+	smooth, cofactor := big.NewInt(1), new(big.Int).Set(n)
+	for a := new(big.Int); a.GCD(nil, nil, cofactor, d).Cmp(big.NewInt(1)) != 0; {
+		smooth.Mul(smooth, a)
+		cofactor.Div(cofactor, a)
+	}
+	residueComponentExponent = new(big.Int).ModInverse(cofactor, smooth)
+	residueComponentExponent.Mul(residueComponentExponent, cofactor)
+	residueExponent = new(big.Int).ModInverse(lambda, cofactor)
+
+	// w = z^((p¹²-1)/2^48), for z a non-square
+	v := order.TrailingZeroBits()
+	half := new(big.Int).Rsh(order, 1)
+	var z, t, one bls12377.E12
+	one.SetOne()
+	z.C1.B0.A0.SetOne()
+	for {
+		z.C0.B0.A0.Add(&z.C0.B0.A0, &one.C0.B0.A0)
+		if !t.Exp(&z, *half).Equal(&one) {
+			break
+		}
+	}
+	residueScaling.Exp(&z, *new(big.Int).Rsh(order, v))
+
+	hint.Register(ResidueWitnessHint)
+}
+
+// AssertPairingCheck checks that the product of pairings ∏e(P[i], Q[i]) is 1.
+//
+// The Miller loops are shared as in Pair, and the final exponentiation is replaced by a check
+// that the Miller loop output f is an r-th power up to a factor in Fp6: the residue witness c and
+// the scaling factor s = s'⋅wᵇ are provided by a hint, and c^p = f⋅s⋅c^x.
+func AssertPairingCheck(api frontend.API, P []G1Affine, Q []G2Affine) error {
+	f, err := MillerLoop(api, P, Q)
+	if err != nil {
+		return err
+	}
+
+	res, err := api.Compiler().NewHint(ResidueWitnessHint, 19, f.C0.B0.A0, f.C0.B0.A1, f.C0.B1.A0, f.C0.B1.A1, f.C0.B2.A0, f.C0.B2.A1, f.C1.B0.A0, f.C1.B0.A1, f.C1.B1.A0, f.C1.B1.A1, f.C1.B2.A0, f.C1.B2.A1)
+	if err != nil {
+		return err
+	}
+	var c, s, sw, w GT
+	c.C0.B0.A0, c.C0.B0.A1 = res[0], res[1]
+	c.C0.B1.A0, c.C0.B1.A1 = res[2], res[3]
+	c.C0.B2.A0, c.C0.B2.A1 = res[4], res[5]
+	c.C1.B0.A0, c.C1.B0.A1 = res[6], res[7]
+	c.C1.B1.A0, c.C1.B1.A1 = res[8], res[9]
+	c.C1.B2.A0, c.C1.B2.A1 = res[10], res[11]
+
+	// s = s'⋅wᵇ, with s' in Fp6
+	s.C0.B0.A0, s.C0.B0.A1 = res[12], res[13]
+	s.C0.B1.A0, s.C0.B1.A1 = res[14], res[15]
+	s.C0.B2.A0, s.C0.B2.A1 = res[16], res[17]
+	s.C1.B0.A0, s.C1.B0.A1 = 0, 0
+	s.C1.B1.A0, s.C1.B1.A1 = 0, 0
+	s.C1.B2.A0, s.C1.B2.A1 = 0, 0
+	api.AssertIsBoolean(res[18])
+	w.Assign(&residueScaling)
+	sw.Mul(api, s, w)
+	s.Select(api, res[18], sw, s)
+
+	// c is invertible: c = 0 would satisfy the check below
+	var cInv GT
+	cInv.Inverse(api, c)
+
+	var lhs, rhs GT
+	lhs.Frobenius(api, c)
+	rhs = expt(api, c, (*GT).Square)
+	rhs.Mul(api, rhs, f)
+	rhs.Mul(api, rhs, s)
+	lhs.AssertIsEqual(api, rhs)
+
+	return nil
+}
+
+// exptCyclotomic returns e^x for e in the cyclotomic subgroup. It uses Granger-Scott squarings
+// instead of compressed squarings, whose decompression is an unchecked division which a prover
+// choosing e could exploit.
+func exptCyclotomic(api frontend.API, e GT) GT {
+	return expt(api, e, (*GT).CyclotomicSquare)
+}
+
+// expt returns e^x, with the addition chain of fields_bls12377.E12.Expt and the given squaring
+func expt(api frontend.API, e GT, square func(z *GT, api frontend.API, x GT) *GT) GT {
+	nSquare := func(res *GT, n int) {
+		for i := 0; i < n; i++ {
+			square(res, api, *res)
+		}
+	}
+
+	res := e
+	nSquare(&res, 5)
+	res.Mul(api, res, e)
+	x33 := res
+	nSquare(&res, 7)
+	res.Mul(api, res, x33)
+	nSquare(&res, 4)
+	res.Mul(api, res, e)
+	nSquare(&res, 1)
+	res.Mul(api, res, e)
+	nSquare(&res, 46)
+	res.Mul(api, res, e)
+
+	return res
+}
+
+// ResidueWitnessHint returns the residue witness c and the scaling factor s = s'⋅wᵇ of the Miller
+// loop output f, such that f⋅s = c^λ when the product of pairings is 1: s⁻¹ is the component of f
+// of order dividing residueSmooth (times w⁻¹ if it isn't in Fp6), and c = (f⋅s)^(λ⁻¹ mod
+// residueCofactor). The results are c (12 elements), s' (6 elements, in Fp6) and b.
+var ResidueWitnessHint = func(_ ecc.ID, inputs []*big.Int, results []*big.Int) error {
+	var f, c, fs, s, t, one bls12377.E12
+
+	f.C0.B0.A0.SetBigInt(inputs[0])
+	f.C0.B0.A1.SetBigInt(inputs[1])
+	f.C0.B1.A0.SetBigInt(inputs[2])
+	f.C0.B1.A1.SetBigInt(inputs[3])
+	f.C0.B2.A0.SetBigInt(inputs[4])
+	f.C0.B2.A1.SetBigInt(inputs[5])
+	f.C1.B0.A0.SetBigInt(inputs[6])
+	f.C1.B0.A1.SetBigInt(inputs[7])
+	f.C1.B1.A0.SetBigInt(inputs[8])
+	f.C1.B1.A1.SetBigInt(inputs[9])
+	f.C1.B2.A0.SetBigInt(inputs[10])
+	f.C1.B2.A1.SetBigInt(inputs[11])
+
+	one.SetOne()
+	fs.Exp(&f, *residueComponentExponent)
+	results[18].SetUint64(0)
+	if !t.Exp(&fs, *fp6Order).Equal(&one) {
+		fs.Mul(&fs, &residueScaling)
+		results[18].SetUint64(1)
+	}
+	s.Inverse(&fs)
+
+	fs.Mul(&f, &s)
+	if results[18].Sign() != 0 {
+		fs.Mul(&fs, &residueScaling)
+	}
+	c.Exp(&fs, *residueExponent)
+
+	c.C0.B0.A0.ToBigIntRegular(results[0])
+	c.C0.B0.A1.ToBigIntRegular(results[1])
+	c.C0.B1.A0.ToBigIntRegular(results[2])
+	c.C0.B1.A1.ToBigIntRegular(results[3])
+	c.C0.B2.A0.ToBigIntRegular(results[4])
+	c.C0.B2.A1.ToBigIntRegular(results[5])
+	c.C1.B0.A0.ToBigIntRegular(results[6])
+	c.C1.B0.A1.ToBigIntRegular(results[7])
+	c.C1.B1.A0.ToBigIntRegular(results[8])
+	c.C1.B1.A1.ToBigIntRegular(results[9])
+	c.C1.B2.A0.ToBigIntRegular(results[10])
+	c.C1.B2.A1.ToBigIntRegular(results[11])
+	s.C0.B0.A0.ToBigIntRegular(results[12])
+	s.C0.B0.A1.ToBigIntRegular(results[13])
+	s.C0.B1.A0.ToBigIntRegular(results[14])
+	s.C0.B1.A1.ToBigIntRegular(results[15])
+	s.C0.B2.A0.ToBigIntRegular(results[16])
+	s.C0.B2.A1.ToBigIntRegular(results[17])
+
+	return nil
+}
@@ -22,6 +22,7 @@ import (
 
 	"github.com/consensys/gnark-crypto/ecc"
 	bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fp"
 	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
 	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/frontend"
@@ -127,6 +128,104 @@ func TestTriplePairingBLS377(t *testing.T) {
 
 }
 
+type pairingCheckBLS377 struct {
+	P1, P2 G1Affine `gnark:",public"`
+	Q1, Q2 G2Affine
+}
+
+func (circuit *pairingCheckBLS377) Define(api frontend.API) error {
+	return AssertPairingCheck(api, []G1Affine{circuit.P1, circuit.P2}, []G2Affine{circuit.Q1, circuit.Q2})
+}
+
+func TestPairingCheckBLS377(t *testing.T) {
+
+	// e(P, Q)⋅e(-[u]P, [1/u]Q) = 1
+	P, Q, _, _ := pairingData()
+	var u, uInv fr.Element
+	var _u, _uInv big.Int
+	u.SetRandom()
+	uInv.Inverse(&u)
+	u.ToBigIntRegular(&_u)
+	uInv.ToBigIntRegular(&_uInv)
+	var P2 bls12377.G1Affine
+	var Q2 bls12377.G2Affine
+	P2.ScalarMultiplication(&P, &_u)
+	P2.Neg(&P2)
+	Q2.ScalarMultiplication(&Q, &_uInv)
+
+	var witness pairingCheckBLS377
+	witness.P1.Assign(&P)
+	witness.P2.Assign(&P2)
+	witness.Q1.Assign(&Q)
+	witness.Q2.Assign(&Q2)
+
+	assert := test.NewAssert(t)
+	assert.SolvingSucceeded(&pairingCheckBLS377{}, &witness, test.WithCurves(ecc.BW6_761))
+
+	// e(P, Q)⋅e([u]P, [1/u]Q) = e(P, Q)² ≠ 1
+	P2.Neg(&P2)
+	witness.P2.Assign(&P2)
+	assert.SolvingFailed(&pairingCheckBLS377{}, &witness, test.WithCurves(ecc.BW6_761))
+}
+
+func TestResidueWitnessHint(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	// e(P, Q)⋅e(-P, Q) = 1; f is a square, f⋅w isn't and needs the scaling by w
+	P, Q, _, _ := pairingData()
+	var mP bls12377.G1Affine
+	mP.Neg(&P)
+	f, err := bls12377.MillerLoop([]bls12377.G1Affine{P, mP}, []bls12377.G2Affine{Q, Q})
+	assert.NoError(err)
+	var fw bls12377.E12
+	fw.Mul(&f, &residueScaling)
+
+	lambda := new(big.Int).Sub(fp.Modulus(), xGen)
+	for i, f := range []bls12377.E12{f, fw} {
+		inputs := []*big.Int{
+			f.C0.B0.A0.ToBigIntRegular(new(big.Int)), f.C0.B0.A1.ToBigIntRegular(new(big.Int)),
+			f.C0.B1.A0.ToBigIntRegular(new(big.Int)), f.C0.B1.A1.ToBigIntRegular(new(big.Int)),
+			f.C0.B2.A0.ToBigIntRegular(new(big.Int)), f.C0.B2.A1.ToBigIntRegular(new(big.Int)),
+			f.C1.B0.A0.ToBigIntRegular(new(big.Int)), f.C1.B0.A1.ToBigIntRegular(new(big.Int)),
+			f.C1.B1.A0.ToBigIntRegular(new(big.Int)), f.C1.B1.A1.ToBigIntRegular(new(big.Int)),
+			f.C1.B2.A0.ToBigIntRegular(new(big.Int)), f.C1.B2.A1.ToBigIntRegular(new(big.Int)),
+		}
+		results := make([]*big.Int, 19)
+		for j := range results {
+			results[j] = new(big.Int)
+		}
+		assert.NoError(ResidueWitnessHint(ecc.BW6_761, inputs, results))
+		assert.Equal(uint64(i), results[18].Uint64(), "scaling by w")
+
+		var c, s, lhs, rhs bls12377.E12
+		c.C0.B0.A0.SetBigInt(results[0])
+		c.C0.B0.A1.SetBigInt(results[1])
+		c.C0.B1.A0.SetBigInt(results[2])
+		c.C0.B1.A1.SetBigInt(results[3])
+		c.C0.B2.A0.SetBigInt(results[4])
+		c.C0.B2.A1.SetBigInt(results[5])
+		c.C1.B0.A0.SetBigInt(results[6])
+		c.C1.B0.A1.SetBigInt(results[7])
+		c.C1.B1.A0.SetBigInt(results[8])
+		c.C1.B1.A1.SetBigInt(results[9])
+		c.C1.B2.A0.SetBigInt(results[10])
+		c.C1.B2.A1.SetBigInt(results[11])
+		s.C0.B0.A0.SetBigInt(results[12])
+		s.C0.B0.A1.SetBigInt(results[13])
+		s.C0.B1.A0.SetBigInt(results[14])
+		s.C0.B1.A1.SetBigInt(results[15])
+		s.C0.B2.A0.SetBigInt(results[16])
+		s.C0.B2.A1.SetBigInt(results[17])
+		if i == 1 {
+			s.Mul(&s, &residueScaling)
+		}
+
+		lhs.Exp(&c, *lambda)
+		rhs.Mul(&f, &s)
+		assert.True(lhs.Equal(&rhs), "f⋅s = c^λ")
+	}
+}
+
 // utils
 func pairingData() (P bls12377.G1Affine, Q bls12377.G2Affine, milRes, pairingRes bls12377.GT) {
 	_, _, P, Q = bls12377.Generators()
@@ -186,3 +285,12 @@ func BenchmarkTriplePairing(b *testing.B) {
 	}
 	b.Log("groth16", ccsBench.GetNbConstraints())
 }
+
+func BenchmarkPairingCheck(b *testing.B) {
+	var c pairingCheckBLS377
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ccsBench, _ = frontend.Compile(ecc.BW6_761, r1cs.NewBuilder, &c)
+	}
+	b.Log("groth16", ccsBench.GetNbConstraints())
+}
@@ -0,0 +1,162 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sw_bls12377
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/fields_bls12377"
+)
+
+// The functions below operate on points provided by the prover (e.g. hints or witnesses), hence they
+// use divisions which fail on a zero denominator: with an unchecked division, 0/0 would let the
+// prover choose the slope of an exceptional addition.
+//
+// The scalar multiplications are by the seed x of the curve, which is smaller than r, so that the
+// affine formulas never reach an exceptional case on points of the prime order subgroups.
+
+var (
+	// seed of the curve
+	xGen = new(big.Int).SetUint64(ateLoop)
+
+	// endoU, endoV define the endomorphism ψ(x, y) = (endoU⋅x̄, endoV⋅ȳ) of G2 (x̄ is the conjugate of x), which is [x] on G2
+	endoU = newInt("80949648264912719408558363140637477264845294720710499478137287262712535938301461879813459410946")
+	endoV = newInt("216465761340224619389371505802605247630151569547285782856803747159100223055385581585702401816380679166954762214499")
+)
+
+// scalarMulSeedG1 returns [x]p with the double-and-add algorithm
+func scalarMulSeedG1(api frontend.API, p G1Affine) G1Affine {
+	n := xGen.BitLen()
+	res := doubleG1(api, p)
+	if xGen.Bit(n-2) == 1 {
+		res = addG1(api, res, p)
+	}
+	for i := n - 3; i >= 0; i-- {
+		if xGen.Bit(i) == 1 {
+			res = doubleAndAddG1(api, res, p)
+		} else {
+			res = doubleG1(api, res)
+		}
+	}
+	return res
+}
+
+// addG1 returns p + q
+func addG1(api frontend.API, p, q G1Affine) G1Affine {
+	l := api.Div(api.Sub(q.Y, p.Y), api.Sub(q.X, p.X))
+	x := api.Sub(api.Mul(l, l), api.Add(p.X, q.X))
+	y := api.Sub(api.Mul(l, api.Sub(p.X, x)), p.Y)
+	return G1Affine{X: x, Y: y}
+}
+
+// subG1 returns p - q
+func subG1(api frontend.API, p, q G1Affine) G1Affine {
+	q.Y = api.Neg(q.Y)
+	return addG1(api, p, q)
+}
+
+// doubleG1 returns 2p
+func doubleG1(api frontend.API, p G1Affine) G1Affine {
+	l := api.Div(api.Mul(p.X, p.X, 3), api.Mul(p.Y, 2))
+	x := api.Sub(api.Mul(l, l), api.Mul(p.X, 2))
+	y := api.Sub(api.Mul(l, api.Sub(p.X, x)), p.Y)
+	return G1Affine{X: x, Y: y}
+}
+
+// doubleAndAddG1 returns 2p + q, computed as (p + q) + p without the ordinate of p + q
+func doubleAndAddG1(api frontend.API, p, q G1Affine) G1Affine {
+	l1 := api.Div(api.Sub(q.Y, p.Y), api.Sub(q.X, p.X))
+	x3 := api.Sub(api.Mul(l1, l1), api.Add(p.X, q.X))
+	l2 := api.Neg(api.Add(l1, api.Div(api.Mul(p.Y, 2), api.Sub(x3, p.X))))
+	x4 := api.Sub(api.Mul(l2, l2), api.Add(p.X, x3))
+	y4 := api.Sub(api.Mul(l2, api.Sub(p.X, x4)), p.Y)
+	return G1Affine{X: x4, Y: y4}
+}
+
+// scalarMulSeedG2 returns [x]p with the double-and-add algorithm
+func scalarMulSeedG2(api frontend.API, p G2Affine) G2Affine {
+	n := xGen.BitLen()
+	res := doubleG2(api, p)
+	if xGen.Bit(n-2) == 1 {
+		res = addG2(api, res, p)
+	}
+	for i := n - 3; i >= 0; i-- {
+		res = doubleG2(api, res)
+		if xGen.Bit(i) == 1 {
+			res = addG2(api, res, p)
+		}
+	}
+	return res
+}
+
+// psi returns ψ(p)
+func psi(api frontend.API, p G2Affine) G2Affine {
+	var res G2Affine
+	res.X.Conjugate(api, p.X).MulByFp(api, res.X, endoU)
+	res.Y.Conjugate(api, p.Y).MulByFp(api, res.Y, endoV)
+	return res
+}
+
+// addG2 returns p + q
+func addG2(api frontend.API, p, q G2Affine) G2Affine {
+	var n, d, l fields_bls12377.E2
+	n.Sub(api, q.Y, p.Y)
+	d.Sub(api, q.X, p.X)
+	l = divE2(api, n, d)
+
+	var res G2Affine
+	res.X.Square(api, l).Sub(api, res.X, p.X).Sub(api, res.X, q.X)
+	res.Y.Sub(api, p.X, res.X).Mul(api, l, res.Y).Sub(api, res.Y, p.Y)
+	return res
+}
+
+// subG2 returns p - q
+func subG2(api frontend.API, p, q G2Affine) G2Affine {
+	q.Y.Neg(api, q.Y)
+	return addG2(api, p, q)
+}
+
+// doubleG2 returns 2p
+func doubleG2(api frontend.API, p G2Affine) G2Affine {
+	var n, d, l fields_bls12377.E2
+	n.Square(api, p.X).MulByFp(api, n, 3)
+	d.Double(api, p.Y)
+	l = divE2(api, n, d)
+
+	var res G2Affine
+	res.X.Square(api, l).Sub(api, res.X, p.X).Sub(api, res.X, p.X)
+	res.Y.Sub(api, p.X, res.X).Mul(api, l, res.Y).Sub(api, res.Y, p.Y)
+	return res
+}
+
+// divE2 returns n/d and fails if d = 0
+func divE2(api frontend.API, n, d fields_bls12377.E2) fields_bls12377.E2 {
+	var res fields_bls12377.E2
+	res.Inverse(api, d).Mul(api, res, n)
+	return res
+}
+
+// return big.Int from base10 input
+func newInt(in string) *big.Int {
+	r := new(big.Int)
+	_, ok := r.SetString(in, 10)
+	if !ok {
+		panic("invalid base10 big.Int: " + in)
+	}
+	return r
+}
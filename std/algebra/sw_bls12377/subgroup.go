@@ -0,0 +1,84 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sw_bls12377
+
+import (
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/fields_bls12377"
+)
+
+// The membership tests follow https://eprint.iacr.org/2021/1130.pdf (Scott): they use the
+// endomorphisms φ on G1 (see inner.go) and ψ on G2, whose eigenvalues are small polynomials in the
+// seed x, so that they cost a few scalar multiplications by x.
+
+// AssertIsOnCurve checks that p is on the curve y² = x³ + 1
+func (p *G1Affine) AssertIsOnCurve(api frontend.API) {
+	api.AssertIsEqual(api.Mul(p.Y, p.Y), g1(api, p.X))
+}
+
+// AssertIsInSubGroup checks that p is on the curve and in the prime order subgroup G1,
+// that is p + [x²]φ(p) = 0, where φ(x, y) = (ω⋅x, y) is [x²-1] on G1.
+//
+// The point at infinity, which has no affine representation, is not accepted.
+func (p *G1Affine) AssertIsInSubGroup(api frontend.API) {
+	p.AssertIsOnCurve(api)
+
+	var q G1Affine
+	innerCurve(api.Compiler().Curve()).phi1(api, &q, p)
+	q = scalarMulSeedG1(api, scalarMulSeedG1(api, q))
+	api.AssertIsEqual(q.X, p.X)
+	api.AssertIsEqual(q.Y, api.Neg(p.Y))
+}
+
+// AssertIsOnCurve checks that p is on the twist y² = x³ + b' on which G2 is defined
+func (p *G2Affine) AssertIsOnCurve(api frontend.API) {
+	var y2 fields_bls12377.E2
+	y2.Square(api, p.Y)
+	y2.AssertIsEqual(api, g2(api, p.X))
+}
+
+// AssertIsInSubGroup checks that p is on the twist and in the prime order subgroup G2,
+// that is [x]p = ψ(p), where ψ is the untwist-Frobenius-twist endomorphism.
+//
+// The point at infinity, which has no affine representation, is not accepted.
+func (p *G2Affine) AssertIsInSubGroup(api frontend.API) {
+	p.AssertIsOnCurve(api)
+
+	q := scalarMulSeedG2(api, *p)
+	psiP := psi(api, *p)
+	q.AssertIsEqual(api, psiP)
+}
+
+// AssertIsInGT checks that e is in the target group of the pairing, the subgroup of order r of
+// the cyclotomic subgroup of E12: e^(p⁴-p²+1) = 1 and e^p = e^x.
+func AssertIsInGT(api frontend.API, e GT) {
+	// e is invertible: e = 0 would pass the checks below
+	var eInv GT
+	eInv.Inverse(api, e)
+
+	// e^(p⁴+1) = e^(p²), so that the cyclotomic squares below are correct
+	var t0, t1 GT
+	t0.FrobeniusSquare(api, e)
+	t1.FrobeniusSquare(api, t0).Mul(api, t1, e)
+	t1.AssertIsEqual(api, t0)
+
+	// e^x is computed with Granger-Scott squarings, which are correct for any e in the cyclotomic
+	// subgroup, unlike the compressed squarings of Expt
+	t0.Frobenius(api, e)
+	t1 = exptCyclotomic(api, e)
+	t1.AssertIsEqual(api, t0)
+}
@@ -0,0 +1,129 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sw_bls12377
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fp"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+type subGroupCircuit struct {
+	P G1Affine
+	Q G2Affine
+}
+
+func (circuit *subGroupCircuit) Define(api frontend.API) error {
+	circuit.P.AssertIsInSubGroup(api)
+	circuit.Q.AssertIsInSubGroup(api)
+	return nil
+}
+
+func TestSubGroup(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	p, q := randomPointG1(), randomPointG2()
+	var pAff bls12377.G1Affine
+	var qAff bls12377.G2Affine
+	pAff.FromJacobian(&p)
+	qAff.FromJacobian(&q)
+
+	var witness subGroupCircuit
+	witness.P.Assign(&pAff)
+	witness.Q.Assign(&qAff)
+	assert.SolvingSucceeded(&subGroupCircuit{}, &witness, test.WithCurves(ecc.BW6_761))
+
+	// a point on the curve, not in G1
+	var one, x, y fp.Element
+	one.SetOne()
+	for {
+		_, _ = x.SetRandom()
+		y.Square(&x).Mul(&y, &x).Add(&y, &one)
+		if y.Legendre() == 1 {
+			break
+		}
+	}
+	y.Sqrt(&y)
+	notInG1 := bls12377.G1Affine{X: x, Y: y}
+	assert.True(notInG1.IsOnCurve() && !notInG1.IsInSubGroup())
+	witness.P.Assign(&notInG1)
+	assert.SolvingFailed(&subGroupCircuit{}, &witness, test.WithCurves(ecc.BW6_761))
+
+	// a point on the twist, not in G2
+	var notInG2 bls12377.G2Affine
+	var y2 bls12377.E2
+	for {
+		_, _ = notInG2.X.A0.SetRandom()
+		_, _ = notInG2.X.A1.SetRandom()
+		y2.Square(&notInG2.X).Mul(&y2, &notInG2.X).Add(&y2, &bTwist)
+		if y2.Legendre() == 1 {
+			break
+		}
+	}
+	notInG2.Y.Sqrt(&y2)
+	assert.True(notInG2.IsOnCurve() && !notInG2.IsInSubGroup())
+	witness.P.Assign(&pAff)
+	witness.Q.Assign(&notInG2)
+	assert.SolvingFailed(&subGroupCircuit{}, &witness, test.WithCurves(ecc.BW6_761))
+
+	// a point not on the twist
+	qAff.Y.A0.Add(&qAff.Y.A0, &one)
+	witness.Q.Assign(&qAff)
+	assert.SolvingFailed(&subGroupCircuit{}, &witness, test.WithCurves(ecc.BW6_761))
+}
+
+type gtCircuit struct {
+	E GT
+}
+
+func (circuit *gtCircuit) Define(api frontend.API) error {
+	AssertIsInGT(api, circuit.E)
+	return nil
+}
+
+func TestAssertIsInGT(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	_, _, g1, g2 := bls12377.Generators()
+	e, err := bls12377.Pair([]bls12377.G1Affine{g1}, []bls12377.G2Affine{g2})
+	assert.NoError(err)
+	var witness gtCircuit
+	witness.E.Assign(&e)
+	assert.SolvingSucceeded(&gtCircuit{}, &witness, test.WithCurves(ecc.BW6_761))
+
+	// f^((p⁶-1)(p²+1)) is in the cyclotomic subgroup, not in GT
+	var f, c, t0 bls12377.E12
+	_, _ = f.SetRandom()
+	t0.Inverse(&f)
+	c.Conjugate(&f).Mul(&c, &t0)
+	t0.FrobeniusSquare(&c)
+	c.Mul(&c, &t0)
+	witness.E.Assign(&c)
+	assert.SolvingFailed(&gtCircuit{}, &witness, test.WithCurves(ecc.BW6_761))
+
+	// not in the cyclotomic subgroup
+	witness.E.Assign(&f)
+	assert.SolvingFailed(&gtCircuit{}, &witness, test.WithCurves(ecc.BW6_761))
+
+	// 0 satisfies e^(p⁴+1) = e^(p²), and its compressed squares are unconstrained
+	witness.E.Assign(new(bls12377.E12))
+	assert.SolvingFailed(&gtCircuit{}, &witness, test.WithCurves(ecc.BW6_761))
+}
@@ -0,0 +1,330 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sw_bls24315
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bls24315 "github.com/consensys/gnark-crypto/ecc/bls24-315"
+	"github.com/consensys/gnark-crypto/ecc/bls24-315/fp"
+	"github.com/consensys/gnark/backend/hint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/math/bits"
+)
+
+// hashToFpLen is the number of bytes L hashed to an element of Fp, as in gnark-crypto
+// (L = ceil((ceil(log2(p)) + k) / 8) with the security parameter k = 128)
+const hashToFpLen = 56
+
+// Hashing to G1 follows RFC 9380, with the Shallue-van de Woestijne map (section 6.6.1) and the
+// constants of gnark-crypto: MapToG1 is exactly gnark-crypto's MapToCurveG1Svdw.
+//
+// As in sw_bls12377, the sign of the ordinate is gnark-crypto's: the square root y it computes is
+// negated unless sign0(u) and sign0(y), where sign0(v) holds iff v ≤ -v. For q - 1 = 2ˢ⋅t with t
+// odd, its Tonelli-Shanks algorithm returns y = a^((t+1)/2) ⋅ gᵉ where g = nonResidue^t and
+// e < 2ˢ⁻¹, which the circuit checks.
+//
+// The quadratic residuosity of the candidate abscissas, the square root and its exponent e are
+// provided by hints and checked in the circuit.
+//
+// EncodeToG1 and HashToG1 hash bytes to field elements with hash_to_field (RFC 9380, section 5) and
+// expand_message_xmd with SHA-256 (hash.ExpandMsgXmd), as gnark-crypto's EncodeToCurveG1Svdw and
+// HashToCurveG1Svdw. Those panic in the version of gnark-crypto used by gnark, since its
+// expand_message_xmd supports only multiples of 32 bytes, and hashToFpLen isn't: NativeHashToG1
+// computes the hash out of a circuit.
+
+// constants of the Shallue-van de Woestijne map to G1, with Z = 1
+// (c1 = g(Z), c2 = -Z/2, c3 = sqrt(-g(Z)⋅3Z²), c4 = -4g(Z)/3Z²)
+var (
+	svdwC1 = big.NewInt(2)
+	svdwC2 = newInt("19852571354756719167512844945204484872466751208457374667532142752818942046563171173536808566784")
+	svdwC3 = newInt("942554356140016085057871637611969541784731087552448464187171556034108136583561605511220865766")
+	svdwC4 = newInt("26470095139675625556683793260272646496622334944609832890042857003758589395417561564715744755710")
+)
+
+var (
+	// nonResidue is the smallest quadratic non-residue of Fp
+	nonResidue *big.Int
+
+	// q - 1 = 2^sqrtS ⋅ sqrtT with sqrtT odd; sqrtExp = (sqrtT+1)/2 and sqrtRoots[j] = g^(2ʲ) for
+	// g = nonResidue^sqrtT, the parameters of the Tonelli-Shanks square root of gnark-crypto
+	sqrtS     int
+	sqrtExp   *big.Int
+	sqrtRoots []*big.Int
+)
+
+func init() {
+	q := fp.Modulus()
+	nonResidue = big.NewInt(2)
+	for big.Jacobi(nonResidue, q) != -1 {
+		nonResidue.Add(nonResidue, big.NewInt(1))
+	}
+
+	t := new(big.Int).Sub(q, big.NewInt(1))
+	for t.Bit(0) == 0 {
+		t.Rsh(t, 1)
+		sqrtS++
+	}
+	sqrtExp = new(big.Int).Add(t, big.NewInt(1))
+	sqrtExp.Rsh(sqrtExp, 1)
+	g := new(big.Int).Exp(nonResidue, t, q)
+	sqrtRoots = make([]*big.Int, sqrtS-1)
+	for j := range sqrtRoots {
+		sqrtRoots[j] = new(big.Int).Set(g)
+		g.Mul(g, g).Mod(g, q)
+	}
+}
+
+// MapToCurve sets p to the image of u by the Shallue-van de Woestijne map, a point of the curve
+// which is not necessarily in G1, and returns p.
+//
+// The map fails on the (negligible) set of u such that 1 - 4u⁴ = 0.
+func (p *G1Affine) MapToCurve(api frontend.API, u frontend.Variable) *G1Affine {
+	tv1 := api.Mul(u, u, svdwC1)
+	tv2 := api.Add(1, tv1)
+	tv1 = api.Sub(1, tv1)
+	tv3 := api.Inverse(api.Mul(tv1, tv2))
+	tv4 := api.Mul(u, tv1, tv3, svdwC3)
+	x1 := api.Sub(svdwC2, tv4)
+	x2 := api.Add(svdwC2, tv4)
+	x3 := api.Mul(tv2, tv2, tv3)
+	x3 = api.Add(api.Mul(x3, x3, svdwC4), 1)
+
+	// x = x1 if g(x1) is a square, else x2 if g(x2) is a square, else x3
+	e1 := isSquare(api, g1(api, x1))
+	e2 := isSquare(api, g1(api, x2))
+	x := api.Select(e1, x1, api.Select(e2, x2, x3))
+
+	// y = ±sqrt(g(x)), negated unless sign0(u) and sign0(y)
+	y := sqrt(api, g1(api, x))
+	y = api.Select(api.And(sign0(api, u), sign0(api, y)), y, api.Neg(y))
+
+	p.X, p.Y = x, y
+	return p
+}
+
+// ClearCofactor sets p to [1+x]q, which is in G1 for q on the curve, and returns p
+func (p *G1Affine) ClearCofactor(api frontend.API, q G1Affine) *G1Affine {
+	*p = addG1(api, q, scalarMulSeedG1(api, q))
+	return p
+}
+
+// MapToG1 maps the field element u to G1 (the encoding of RFC 9380 with the field element already
+// hashed), as gnark-crypto's MapToCurveG1Svdw.
+func MapToG1(api frontend.API, u frontend.Variable) G1Affine {
+	var res G1Affine
+	res.MapToCurve(api, u).ClearCofactor(api, res)
+	return res
+}
+
+// EncodeToG1 hashes the bytes msg to G1, with a non-uniform distribution (encode_to_curve, RFC 9380
+// section 3) and the domain separation tag dst, as gnark-crypto's EncodeToCurveG1Svdw.
+func EncodeToG1(api frontend.API, dst []byte, msg ...frontend.Variable) (G1Affine, error) {
+	u, err := hashToFp(api, msg, dst, 1)
+	if err != nil {
+		return G1Affine{}, err
+	}
+	return MapToG1(api, u[0]), nil
+}
+
+// HashToG1 hashes the bytes msg to G1, with a uniform distribution (hash_to_curve, RFC 9380
+// section 3) and the domain separation tag dst, as gnark-crypto's HashToCurveG1Svdw.
+func HashToG1(api frontend.API, dst []byte, msg ...frontend.Variable) (G1Affine, error) {
+	u, err := hashToFp(api, msg, dst, 2)
+	if err != nil {
+		return G1Affine{}, err
+	}
+	var q0, q1 G1Affine
+	q0.MapToCurve(api, u[0])
+	q1.MapToCurve(api, u[1])
+	var res G1Affine
+	res.ClearCofactor(api, addG1(api, q0, q1))
+	return res, nil
+}
+
+// NativeHashToG1 is HashToG1 out of a circuit, that is gnark-crypto's HashToCurveG1Svdw with
+// hash.NativeExpandMsgXmd
+func NativeHashToG1(msg, dst []byte) (bls24315.G1Affine, error) {
+	b, err := hash.NativeExpandMsgXmd(msg, dst, 2*hashToFpLen)
+	if err != nil {
+		return bls24315.G1Affine{}, err
+	}
+	var u0, u1 fp.Element
+	q0 := bls24315.MapToCurveG1Svdw(*u0.SetBytes(b[:hashToFpLen]))
+	q1 := bls24315.MapToCurveG1Svdw(*u1.SetBytes(b[hashToFpLen:]))
+
+	var q bls24315.G1Jac
+	q.FromAffine(&q0).AddMixed(&q1)
+	var res bls24315.G1Affine
+	res.FromJacobian(&q)
+	return res, nil
+}
+
+// hashToFp hashes the bytes msg to count elements of Fp (hash_to_field, RFC 9380 section 5), which
+// is the scalar field of the circuit: the big-endian integers of hashToFpLen bytes are reduced
+// modulo p by the linear combinations.
+func hashToFp(api frontend.API, msg []frontend.Variable, dst []byte, count int) ([]frontend.Variable, error) {
+	b, err := hash.ExpandMsgXmd(api, msg, dst, count*hashToFpLen)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]frontend.Variable, count)
+	for i := range res {
+		res[i] = 0
+		for _, c := range b[i*hashToFpLen : (i+1)*hashToFpLen] {
+			res[i] = api.Add(api.Mul(res[i], 256), c)
+		}
+	}
+	return res, nil
+}
+
+// g1 returns x³ + 1
+func g1(api frontend.API, x frontend.Variable) frontend.Variable {
+	return api.Add(api.Mul(x, x, x), 1)
+}
+
+// isSquare returns 1 if a is a square, 0 otherwise: a (resp. nonResidue⋅a) is a square, whose
+// square root is provided by a hint.
+func isSquare(api frontend.API, a frontend.Variable) frontend.Variable {
+	res, err := api.Compiler().NewHint(IsSquareHint, 2, a)
+	if err != nil {
+		// err is non-nil only for invalid number of inputs
+		panic(err)
+	}
+	e, s := res[0], res[1]
+	api.AssertIsBoolean(e)
+	api.AssertIsEqual(api.Mul(s, s), api.Select(e, a, api.Mul(a, nonResidue)))
+	return e
+}
+
+// sign0 returns 1 if v ≤ -v as integers, 0 otherwise, as gnark-crypto's sign0: v = w if
+// w ≤ (q-1)/2, else v = -w with w ≠ 0.
+func sign0(api frontend.API, v frontend.Variable) frontend.Variable {
+	res, err := api.Compiler().NewHint(Sign0Hint, 2, v)
+	if err != nil {
+		// err is non-nil only for invalid number of inputs
+		panic(err)
+	}
+	b, w := res[0], res[1]
+	api.AssertIsBoolean(b)
+	api.AssertIsEqual(w, api.Select(b, v, api.Neg(v)))
+	api.AssertIsLessOrEqual(w, new(big.Int).Rsh(fp.Modulus(), 1))
+	api.AssertIsEqual(api.Mul(api.Sub(1, b), api.IsZero(w)), 0)
+	return b
+}
+
+// sqrt returns the square root of the square a computed by gnark-crypto's fp.Element.Sqrt:
+// y = a^sqrtExp ⋅ gᵉ with y² = a and e < 2^(sqrtS-1), where gᵉ = Π sqrtRoots[j]^eⱼ.
+func sqrt(api frontend.API, a frontend.Variable) frontend.Variable {
+	res, err := api.Compiler().NewHint(SqrtHint, 2, a)
+	if err != nil {
+		// err is non-nil only for invalid number of inputs
+		panic(err)
+	}
+	y, e := res[0], res[1]
+	api.AssertIsEqual(api.Mul(y, y), a)
+
+	ge := frontend.Variable(1)
+	for j, b := range bits.ToBinary(api, e, bits.WithNbDigits(sqrtS-1)) {
+		ge = api.Mul(ge, api.Select(b, sqrtRoots[j], 1))
+	}
+	api.AssertIsEqual(y, api.Mul(exp(api, a, sqrtExp), ge))
+	return y
+}
+
+// exp returns a^k, for a constant k > 0
+func exp(api frontend.API, a frontend.Variable, k *big.Int) frontend.Variable {
+	res := a
+	for i := k.BitLen() - 2; i >= 0; i-- {
+		res = api.Mul(res, res)
+		if k.Bit(i) == 1 {
+			res = api.Mul(res, a)
+		}
+	}
+	return res
+}
+
+// IsSquareHint returns (1, sqrt(a)) if a is a square in Fp, and (0, sqrt(nonResidue⋅a)) otherwise
+var IsSquareHint = func(_ ecc.ID, inputs []*big.Int, results []*big.Int) error {
+	q := fp.Modulus()
+	a := new(big.Int).Mod(inputs[0], q)
+	if big.Jacobi(a, q) >= 0 {
+		results[0].SetUint64(1)
+	} else {
+		results[0].SetUint64(0)
+		a.Mul(a, nonResidue).Mod(a, q)
+	}
+	results[1].ModSqrt(a, q)
+	return nil
+}
+
+// SqrtHint returns the square root y of inputs[0] in Fp computed by gnark-crypto, and the exponent
+// e < 2^(sqrtS-1) such that y = inputs[0]^sqrtExp ⋅ gᵉ, see sqrt
+var SqrtHint = func(_ ecc.ID, inputs []*big.Int, results []*big.Int) error {
+	q := fp.Modulus()
+	var a fp.Element
+	a.SetBigInt(inputs[0])
+	if a.Sqrt(&a) == nil {
+		return errors.New("no square root")
+	}
+	y := a.ToBigIntRegular(results[0])
+	results[1].SetUint64(0)
+	if y.Sign() == 0 {
+		return nil
+	}
+
+	// gᵉ = y / inputs[0]^sqrtExp, whose discrete logarithm is computed bit by bit in the subgroup
+	// of order 2^sqrtS
+	ge := new(big.Int).Exp(inputs[0], sqrtExp, q)
+	ge.ModInverse(ge, q).Mul(ge, y).Mod(ge, q)
+	t := new(big.Int)
+	for j := 0; j < sqrtS; j++ {
+		t.Exp(ge, new(big.Int).Lsh(big.NewInt(1), uint(sqrtS-1-j)), q)
+		if t.Cmp(big.NewInt(1)) == 0 {
+			continue
+		}
+		if j == sqrtS-1 {
+			return errors.New("unexpected square root")
+		}
+		results[1].SetBit(results[1], j, 1)
+		ge.Mul(ge, new(big.Int).ModInverse(sqrtRoots[j], q)).Mod(ge, q)
+	}
+	return nil
+}
+
+// Sign0Hint returns (1, v) if v = inputs[0] ≤ -v as integers, (0, -v) otherwise
+var Sign0Hint = func(_ ecc.ID, inputs []*big.Int, results []*big.Int) error {
+	q := fp.Modulus()
+	v := new(big.Int).Mod(inputs[0], q)
+	if v.Cmp(new(big.Int).Rsh(q, 1)) <= 0 {
+		results[0].SetUint64(1)
+		results[1].Set(v)
+	} else {
+		results[0].SetUint64(0)
+		results[1].Sub(q, v)
+	}
+	return nil
+}
+
+func init() {
+	hint.Register(IsSquareHint)
+	hint.Register(SqrtHint)
+	hint.Register(Sign0Hint)
+}
@@ -0,0 +1,96 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sw_bls24315
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bls24315 "github.com/consensys/gnark-crypto/ecc/bls24-315"
+	"github.com/consensys/gnark-crypto/ecc/bls24-315/fp"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+var testDST = []byte("gnark-sw_bls24315-hash-to-curve-test")
+
+const testMsg = "abc"
+
+type mapToG1Circuit struct {
+	U        frontend.Variable
+	Expected G1Affine
+}
+
+func (circuit *mapToG1Circuit) Define(api frontend.API) error {
+	p := MapToG1(api, circuit.U)
+	api.AssertIsEqual(p.X, circuit.Expected.X)
+	api.AssertIsEqual(p.Y, circuit.Expected.Y)
+	return nil
+}
+
+type hashToG1Circuit struct {
+	Msg      [len(testMsg)]frontend.Variable
+	Expected G1Affine
+}
+
+func (circuit *hashToG1Circuit) Define(api frontend.API) error {
+	p, err := HashToG1(api, testDST, circuit.Msg[:]...)
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(p.X, circuit.Expected.X)
+	api.AssertIsEqual(p.Y, circuit.Expected.Y)
+	return nil
+}
+
+func TestMapToG1(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	// u and -u cover both cases of the sign rule
+	var u fp.Element
+	_, _ = u.SetRandom()
+	for _, u := range []fp.Element{u, *new(fp.Element).Neg(&u)} {
+		q := bls24315.MapToCurveG1Svdw(u)
+
+		var witness mapToG1Circuit
+		witness.U = u.ToBigIntRegular(new(big.Int))
+		witness.Expected.Assign(&q)
+		assert.SolvingSucceeded(&mapToG1Circuit{}, &witness, test.WithCurves(ecc.BW6_633))
+
+		q.Neg(&q)
+		witness.Expected.Assign(&q)
+		assert.SolvingFailed(&mapToG1Circuit{}, &witness, test.WithCurves(ecc.BW6_633))
+	}
+}
+
+func TestHashToG1(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	q, err := NativeHashToG1([]byte(testMsg), testDST)
+	assert.NoError(err)
+
+	var witness hashToG1Circuit
+	for i := range testMsg {
+		witness.Msg[i] = testMsg[i]
+	}
+	witness.Expected.Assign(&q)
+	assert.SolvingSucceeded(&hashToG1Circuit{}, &witness, test.WithCurves(ecc.BW6_633))
+
+	witness.Msg[0] = testMsg[0] ^ 1
+	assert.SolvingFailed(&hashToG1Circuit{}, &witness, test.WithCurves(ecc.BW6_633))
+}
@@ -0,0 +1,280 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sw_bls24315
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bls24315 "github.com/consensys/gnark-crypto/ecc/bls24-315"
+	"github.com/consensys/gnark-crypto/ecc/bls24-315/fp"
+	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr"
+	"github.com/consensys/gnark/backend/hint"
+	"github.com/consensys/gnark/frontend"
+)
+
+// The pairing check follows https://eprint.iacr.org/2024/640.pdf (Novakovic, Eagen): instead of
+// computing the final exponentiation of the Miller loop output f, the prover shows that f is an
+// r-th power, up to a factor which the final exponentiation would cancel.
+//
+// λ = p - x is a multiple of r (x is the negative seed of the curve), and the prover provides c and
+// s such that f⋅s = c^λ. Then f^((p²⁴-1)/r) = s^(-(p²⁴-1)/r), which is 1 for s in Fp12 (of order
+// p¹²-1, which divides (p²⁴-1)/r) or in the subgroup of order 2^23 of Fp24. Conversely,
+// gcd(λ, p²⁴-1) = 2^23⋅3^3⋅11⋅31⋅r, and the components of f of order 3ᵏ, 11 or 31 are in Fp12, as
+// is the square of its component of order 2ᵏ: s = s'⋅wᵇ, where s' is in Fp12 and w is a fixed
+// element of order 2^23, makes f⋅s a λ-th power.
+//
+// There is no easy part: the check costs an exponentiation by |x| with full squarings, instead of
+// the easy part and nine exponentiations by x with cyclotomic squarings of FinalExponentiation.
+
+var (
+	// residueComponentExponent raises an element of order dividing (p²⁴-1)/r to its component of
+	// order dividing residueSmooth, the part of (p²⁴-1)/r made of the primes of gcd(λ, p²⁴-1)/r
+	residueComponentExponent *big.Int
+
+	// residueExponent is λ⁻¹ mod residueCofactor, where residueCofactor = (p²⁴-1)/(r⋅residueSmooth)
+	residueExponent *big.Int
+
+	// residueScaling is an element of order 2^23, the 2-adic valuation of p²⁴-1
+	residueScaling bls24315.E24
+
+	// p¹²-1
+	fp12Order *big.Int
+)
+
+func init() {
+	p, r := fp.Modulus(), fr.Modulus()
+
+	p12 := new(big.Int).Exp(p, big.NewInt(12), nil)
+	fp12Order = new(big.Int).Sub(p12, big.NewInt(1))
+	n := new(big.Int).Mul(p12, p12)
+	n.Sub(n, big.NewInt(1))
+	order := new(big.Int).Set(n)
+	n.Div(n, r)
+
+	// xGen = -x
+	lambda := new(big.Int).Add(p, xGen)
+	d := new(big.Int).GCD(nil, nil, lambda, order)
+	d.Div(d, r)
+
+	// (p²⁴-1)/r = residueSmooth ⋅ residueCofactor, with gcd(residueCofactor, λ) = 1
+	smooth, cofactor := big.NewInt(1), new(big.Int).Set(n)
+	for a := new(big.Int); a.GCD(nil, nil, cofactor, d).Cmp(big.NewInt(1)) != 0; {
+		smooth.Mul(smooth, a)
+		cofactor.Div(cofactor, a)
+	}
+	residueComponentExponent = new(big.Int).ModInverse(cofactor, smooth)
+	residueComponentExponent.Mul(residueComponentExponent, cofactor)
+	residueExponent = new(big.Int).ModInverse(lambda, cofactor)
+
+	// w = z^((p²⁴-1)/2^23), for z a non-square
+	v := order.TrailingZeroBits()
+	half := new(big.Int).Rsh(order, 1)
+	var z, t, one bls24315.E24
+	one.SetOne()
+	z.D1.C0.B0.A0.SetOne()
+	for {
+		z.D0.C0.B0.A0.Add(&z.D0.C0.B0.A0, &one.D0.C0.B0.A0)
+		if !t.Exp(&z, *half).Equal(&one) {
+			break
+		}
+	}
+	residueScaling.Exp(&z, *new(big.Int).Rsh(order, v))
+
+	hint.Register(ResidueWitnessHint)
+}
+
+// AssertPairingCheck checks that the product of pairings ∏e(P[i], Q[i]) is 1.
+//
+// The Miller loops are shared as in Pair, and the final exponentiation is replaced by a check
+// that the Miller loop output f is an r-th power up to a factor in Fp12: the residue witness c and
+// the scaling factor s = s'⋅wᵇ are provided by a hint, and c^p⋅c^|x| = f⋅s.
+func AssertPairingCheck(api frontend.API, P []G1Affine, Q []G2Affine) error {
+	f, err := MillerLoop(api, P, Q)
+	if err != nil {
+		return err
+	}
+
+	res, err := api.Compiler().NewHint(ResidueWitnessHint, 37, f.D0.C0.B0.A0, f.D0.C0.B0.A1, f.D0.C0.B1.A0, f.D0.C0.B1.A1, f.D0.C1.B0.A0, f.D0.C1.B0.A1, f.D0.C1.B1.A0, f.D0.C1.B1.A1, f.D0.C2.B0.A0, f.D0.C2.B0.A1, f.D0.C2.B1.A0, f.D0.C2.B1.A1, f.D1.C0.B0.A0, f.D1.C0.B0.A1, f.D1.C0.B1.A0, f.D1.C0.B1.A1, f.D1.C1.B0.A0, f.D1.C1.B0.A1, f.D1.C1.B1.A0, f.D1.C1.B1.A1, f.D1.C2.B0.A0, f.D1.C2.B0.A1, f.D1.C2.B1.A0, f.D1.C2.B1.A1)
+	if err != nil {
+		return err
+	}
+	var c, s, sw, w GT
+	c.D0.C0.B0.A0, c.D0.C0.B0.A1 = res[0], res[1]
+	c.D0.C0.B1.A0, c.D0.C0.B1.A1 = res[2], res[3]
+	c.D0.C1.B0.A0, c.D0.C1.B0.A1 = res[4], res[5]
+	c.D0.C1.B1.A0, c.D0.C1.B1.A1 = res[6], res[7]
+	c.D0.C2.B0.A0, c.D0.C2.B0.A1 = res[8], res[9]
+	c.D0.C2.B1.A0, c.D0.C2.B1.A1 = res[10], res[11]
+	c.D1.C0.B0.A0, c.D1.C0.B0.A1 = res[12], res[13]
+	c.D1.C0.B1.A0, c.D1.C0.B1.A1 = res[14], res[15]
+	c.D1.C1.B0.A0, c.D1.C1.B0.A1 = res[16], res[17]
+	c.D1.C1.B1.A0, c.D1.C1.B1.A1 = res[18], res[19]
+	c.D1.C2.B0.A0, c.D1.C2.B0.A1 = res[20], res[21]
+	c.D1.C2.B1.A0, c.D1.C2.B1.A1 = res[22], res[23]
+
+	// s = s'⋅wᵇ, with s' in Fp12
+	s.D0.C0.B0.A0, s.D0.C0.B0.A1 = res[24], res[25]
+	s.D0.C0.B1.A0, s.D0.C0.B1.A1 = res[26], res[27]
+	s.D0.C1.B0.A0, s.D0.C1.B0.A1 = res[28], res[29]
+	s.D0.C1.B1.A0, s.D0.C1.B1.A1 = res[30], res[31]
+	s.D0.C2.B0.A0, s.D0.C2.B0.A1 = res[32], res[33]
+	s.D0.C2.B1.A0, s.D0.C2.B1.A1 = res[34], res[35]
+	s.D1.C0.B0.A0, s.D1.C0.B0.A1 = 0, 0
+	s.D1.C0.B1.A0, s.D1.C0.B1.A1 = 0, 0
+	s.D1.C1.B0.A0, s.D1.C1.B0.A1 = 0, 0
+	s.D1.C1.B1.A0, s.D1.C1.B1.A1 = 0, 0
+	s.D1.C2.B0.A0, s.D1.C2.B0.A1 = 0, 0
+	s.D1.C2.B1.A0, s.D1.C2.B1.A1 = 0, 0
+	api.AssertIsBoolean(res[36])
+	w.Assign(&residueScaling)
+	sw.Mul(api, s, w)
+	s.Select(api, res[36], sw, s)
+
+	// c is invertible: c = 0 would satisfy the check below, and c⁻¹ is needed for c^|x|
+	var cInv GT
+	cInv.Inverse(api, c)
+
+	var lhs, rhs GT
+	lhs.Frobenius(api, c)
+	t := expt(api, c, cInv, (*GT).Square)
+	lhs.Mul(api, lhs, t)
+	rhs.Mul(api, f, s)
+	lhs.AssertIsEqual(api, rhs)
+
+	return nil
+}
+
+// exptCyclotomic returns e^x for e in the cyclotomic subgroup. It uses Granger-Scott squarings
+// instead of compressed squarings, whose decompression is an unchecked division which a prover
+// choosing e could exploit.
+func exptCyclotomic(api frontend.API, e GT) GT {
+	var eInv GT
+	eInv.Conjugate(api, e)
+	res := expt(api, e, eInv, (*GT).CyclotomicSquare)
+	res.Conjugate(api, res)
+	return res
+}
+
+// expt returns e^|x| given eInv = e⁻¹, with the addition chain of fields_bls24315.E24.Expt and the
+// given squaring
+func expt(api frontend.API, e, eInv GT, square func(z *GT, api frontend.API, x GT) *GT) GT {
+	nSquare := func(res *GT, n int) {
+		for i := 0; i < n; i++ {
+			square(res, api, *res)
+		}
+	}
+
+	res := e
+	nSquare(&res, 2)
+	res.Mul(api, res, eInv)
+	nSquare(&res, 8)
+	res.Mul(api, res, eInv)
+	nSquare(&res, 2)
+	res.Mul(api, res, e)
+	nSquare(&res, 20)
+	res.Mul(api, res, eInv)
+
+	return res
+}
+
+// ResidueWitnessHint returns the residue witness c and the scaling factor s = s'⋅wᵇ of the Miller
+// loop output f, such that f⋅s = c^λ when the product of pairings is 1: s⁻¹ is the component of f
+// of order dividing residueSmooth (times w⁻¹ if it isn't in Fp12), and c = (f⋅s)^(λ⁻¹ mod
+// residueCofactor). The results are c (24 elements), s' (12 elements, in Fp12) and b.
+var ResidueWitnessHint = func(_ ecc.ID, inputs []*big.Int, results []*big.Int) error {
+	var f, c, fs, s, t, one bls24315.E24
+
+	f.D0.C0.B0.A0.SetBigInt(inputs[0])
+	f.D0.C0.B0.A1.SetBigInt(inputs[1])
+	f.D0.C0.B1.A0.SetBigInt(inputs[2])
+	f.D0.C0.B1.A1.SetBigInt(inputs[3])
+	f.D0.C1.B0.A0.SetBigInt(inputs[4])
+	f.D0.C1.B0.A1.SetBigInt(inputs[5])
+	f.D0.C1.B1.A0.SetBigInt(inputs[6])
+	f.D0.C1.B1.A1.SetBigInt(inputs[7])
+	f.D0.C2.B0.A0.SetBigInt(inputs[8])
+	f.D0.C2.B0.A1.SetBigInt(inputs[9])
+	f.D0.C2.B1.A0.SetBigInt(inputs[10])
+	f.D0.C2.B1.A1.SetBigInt(inputs[11])
+	f.D1.C0.B0.A0.SetBigInt(inputs[12])
+	f.D1.C0.B0.A1.SetBigInt(inputs[13])
+	f.D1.C0.B1.A0.SetBigInt(inputs[14])
+	f.D1.C0.B1.A1.SetBigInt(inputs[15])
+	f.D1.C1.B0.A0.SetBigInt(inputs[16])
+	f.D1.C1.B0.A1.SetBigInt(inputs[17])
+	f.D1.C1.B1.A0.SetBigInt(inputs[18])
+	f.D1.C1.B1.A1.SetBigInt(inputs[19])
+	f.D1.C2.B0.A0.SetBigInt(inputs[20])
+	f.D1.C2.B0.A1.SetBigInt(inputs[21])
+	f.D1.C2.B1.A0.SetBigInt(inputs[22])
+	f.D1.C2.B1.A1.SetBigInt(inputs[23])
+
+	one.SetOne()
+	fs.Exp(&f, *residueComponentExponent)
+	results[36].SetUint64(0)
+	if !t.Exp(&fs, *fp12Order).Equal(&one) {
+		fs.Mul(&fs, &residueScaling)
+		results[36].SetUint64(1)
+	}
+	s.Inverse(&fs)
+
+	fs.Mul(&f, &s)
+	if results[36].Sign() != 0 {
+		fs.Mul(&fs, &residueScaling)
+	}
+	c.Exp(&fs, *residueExponent)
+
+	c.D0.C0.B0.A0.ToBigIntRegular(results[0])
+	c.D0.C0.B0.A1.ToBigIntRegular(results[1])
+	c.D0.C0.B1.A0.ToBigIntRegular(results[2])
+	c.D0.C0.B1.A1.ToBigIntRegular(results[3])
+	c.D0.C1.B0.A0.ToBigIntRegular(results[4])
+	c.D0.C1.B0.A1.ToBigIntRegular(results[5])
+	c.D0.C1.B1.A0.ToBigIntRegular(results[6])
+	c.D0.C1.B1.A1.ToBigIntRegular(results[7])
+	c.D0.C2.B0.A0.ToBigIntRegular(results[8])
+	c.D0.C2.B0.A1.ToBigIntRegular(results[9])
+	c.D0.C2.B1.A0.ToBigIntRegular(results[10])
+	c.D0.C2.B1.A1.ToBigIntRegular(results[11])
+	c.D1.C0.B0.A0.ToBigIntRegular(results[12])
+	c.D1.C0.B0.A1.ToBigIntRegular(results[13])
+	c.D1.C0.B1.A0.ToBigIntRegular(results[14])
+	c.D1.C0.B1.A1.ToBigIntRegular(results[15])
+	c.D1.C1.B0.A0.ToBigIntRegular(results[16])
+	c.D1.C1.B0.A1.ToBigIntRegular(results[17])
+	c.D1.C1.B1.A0.ToBigIntRegular(results[18])
+	c.D1.C1.B1.A1.ToBigIntRegular(results[19])
+	c.D1.C2.B0.A0.ToBigIntRegular(results[20])
+	c.D1.C2.B0.A1.ToBigIntRegular(results[21])
+	c.D1.C2.B1.A0.ToBigIntRegular(results[22])
+	c.D1.C2.B1.A1.ToBigIntRegular(results[23])
+	s.D0.C0.B0.A0.ToBigIntRegular(results[24])
+	s.D0.C0.B0.A1.ToBigIntRegular(results[25])
+	s.D0.C0.B1.A0.ToBigIntRegular(results[26])
+	s.D0.C0.B1.A1.ToBigIntRegular(results[27])
+	s.D0.C1.B0.A0.ToBigIntRegular(results[28])
+	s.D0.C1.B0.A1.ToBigIntRegular(results[29])
+	s.D0.C1.B1.A0.ToBigIntRegular(results[30])
+	s.D0.C1.B1.A1.ToBigIntRegular(results[31])
+	s.D0.C2.B0.A0.ToBigIntRegular(results[32])
+	s.D0.C2.B0.A1.ToBigIntRegular(results[33])
+	s.D0.C2.B1.A0.ToBigIntRegular(results[34])
+	s.D0.C2.B1.A1.ToBigIntRegular(results[35])
+
+	return nil
+}
@@ -22,6 +22,7 @@ import (
 
 	"github.com/consensys/gnark-crypto/ecc"
 	bls24315 "github.com/consensys/gnark-crypto/ecc/bls24-315"
+	"github.com/consensys/gnark-crypto/ecc/bls24-315/fp"
 	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr"
 	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/frontend"
@@ -128,7 +129,130 @@ func TestTriplePairingBLS24315(t *testing.T) {
 
 }
 
+type pairingCheckBLS24315 struct {
+	P1, P2 G1Affine `gnark:",public"`
+	Q1, Q2 G2Affine
+}
+
+func (circuit *pairingCheckBLS24315) Define(api frontend.API) error {
+	return AssertPairingCheck(api, []G1Affine{circuit.P1, circuit.P2}, []G2Affine{circuit.Q1, circuit.Q2})
+}
+
+func TestPairingCheckBLS24315(t *testing.T) {
+
+	// e(P, Q)⋅e(-[u]P, [1/u]Q) = 1
+	P, Q, _, _ := pairingData()
+	var u, uInv fr.Element
+	var _u, _uInv big.Int
+	u.SetRandom()
+	uInv.Inverse(&u)
+	u.ToBigIntRegular(&_u)
+	uInv.ToBigIntRegular(&_uInv)
+	var P2 bls24315.G1Affine
+	var Q2 bls24315.G2Affine
+	P2.ScalarMultiplication(&P, &_u)
+	P2.Neg(&P2)
+	Q2.ScalarMultiplication(&Q, &_uInv)
+
+	var witness pairingCheckBLS24315
+	witness.P1.Assign(&P)
+	witness.P2.Assign(&P2)
+	witness.Q1.Assign(&Q)
+	witness.Q2.Assign(&Q2)
+
+	assert := test.NewAssert(t)
+	assert.SolvingSucceeded(&pairingCheckBLS24315{}, &witness, test.WithCurves(ecc.BW6_633), test.WithBackends(backend.GROTH16))
+
+	// e(P, Q)⋅e([u]P, [1/u]Q) = e(P, Q)² ≠ 1
+	P2.Neg(&P2)
+	witness.P2.Assign(&P2)
+	assert.SolvingFailed(&pairingCheckBLS24315{}, &witness, test.WithCurves(ecc.BW6_633), test.WithBackends(backend.GROTH16))
+}
+
 // utils
+func TestResidueWitnessHint(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	// e(P, Q)⋅e(-P, Q) = 1; f⋅w isn't in Fp12 up to a λ-th power and needs the scaling by w
+	P, Q, _, _ := pairingData()
+	var mP bls24315.G1Affine
+	mP.Neg(&P)
+	f, err := bls24315.MillerLoop([]bls24315.G1Affine{P, mP}, []bls24315.G2Affine{Q, Q})
+	assert.NoError(err)
+	var fw bls24315.E24
+	fw.Mul(&f, &residueScaling)
+
+	// xGen = -x
+	lambda := new(big.Int).Add(fp.Modulus(), xGen)
+	for i, f := range []bls24315.E24{f, fw} {
+		inputs := []*big.Int{
+			f.D0.C0.B0.A0.ToBigIntRegular(new(big.Int)), f.D0.C0.B0.A1.ToBigIntRegular(new(big.Int)),
+			f.D0.C0.B1.A0.ToBigIntRegular(new(big.Int)), f.D0.C0.B1.A1.ToBigIntRegular(new(big.Int)),
+			f.D0.C1.B0.A0.ToBigIntRegular(new(big.Int)), f.D0.C1.B0.A1.ToBigIntRegular(new(big.Int)),
+			f.D0.C1.B1.A0.ToBigIntRegular(new(big.Int)), f.D0.C1.B1.A1.ToBigIntRegular(new(big.Int)),
+			f.D0.C2.B0.A0.ToBigIntRegular(new(big.Int)), f.D0.C2.B0.A1.ToBigIntRegular(new(big.Int)),
+			f.D0.C2.B1.A0.ToBigIntRegular(new(big.Int)), f.D0.C2.B1.A1.ToBigIntRegular(new(big.Int)),
+			f.D1.C0.B0.A0.ToBigIntRegular(new(big.Int)), f.D1.C0.B0.A1.ToBigIntRegular(new(big.Int)),
+			f.D1.C0.B1.A0.ToBigIntRegular(new(big.Int)), f.D1.C0.B1.A1.ToBigIntRegular(new(big.Int)),
+			f.D1.C1.B0.A0.ToBigIntRegular(new(big.Int)), f.D1.C1.B0.A1.ToBigIntRegular(new(big.Int)),
+			f.D1.C1.B1.A0.ToBigIntRegular(new(big.Int)), f.D1.C1.B1.A1.ToBigIntRegular(new(big.Int)),
+			f.D1.C2.B0.A0.ToBigIntRegular(new(big.Int)), f.D1.C2.B0.A1.ToBigIntRegular(new(big.Int)),
+			f.D1.C2.B1.A0.ToBigIntRegular(new(big.Int)), f.D1.C2.B1.A1.ToBigIntRegular(new(big.Int)),
+		}
+		results := make([]*big.Int, 37)
+		for j := range results {
+			results[j] = new(big.Int)
+		}
+		assert.NoError(ResidueWitnessHint(ecc.BW6_633, inputs, results))
+		assert.Equal(uint64(i), results[36].Uint64(), "scaling by w")
+
+		var c, s, lhs, rhs bls24315.E24
+		c.D0.C0.B0.A0.SetBigInt(results[0])
+		c.D0.C0.B0.A1.SetBigInt(results[1])
+		c.D0.C0.B1.A0.SetBigInt(results[2])
+		c.D0.C0.B1.A1.SetBigInt(results[3])
+		c.D0.C1.B0.A0.SetBigInt(results[4])
+		c.D0.C1.B0.A1.SetBigInt(results[5])
+		c.D0.C1.B1.A0.SetBigInt(results[6])
+		c.D0.C1.B1.A1.SetBigInt(results[7])
+		c.D0.C2.B0.A0.SetBigInt(results[8])
+		c.D0.C2.B0.A1.SetBigInt(results[9])
+		c.D0.C2.B1.A0.SetBigInt(results[10])
+		c.D0.C2.B1.A1.SetBigInt(results[11])
+		c.D1.C0.B0.A0.SetBigInt(results[12])
+		c.D1.C0.B0.A1.SetBigInt(results[13])
+		c.D1.C0.B1.A0.SetBigInt(results[14])
+		c.D1.C0.B1.A1.SetBigInt(results[15])
+		c.D1.C1.B0.A0.SetBigInt(results[16])
+		c.D1.C1.B0.A1.SetBigInt(results[17])
+		c.D1.C1.B1.A0.SetBigInt(results[18])
+		c.D1.C1.B1.A1.SetBigInt(results[19])
+		c.D1.C2.B0.A0.SetBigInt(results[20])
+		c.D1.C2.B0.A1.SetBigInt(results[21])
+		c.D1.C2.B1.A0.SetBigInt(results[22])
+		c.D1.C2.B1.A1.SetBigInt(results[23])
+		s.D0.C0.B0.A0.SetBigInt(results[24])
+		s.D0.C0.B0.A1.SetBigInt(results[25])
+		s.D0.C0.B1.A0.SetBigInt(results[26])
+		s.D0.C0.B1.A1.SetBigInt(results[27])
+		s.D0.C1.B0.A0.SetBigInt(results[28])
+		s.D0.C1.B0.A1.SetBigInt(results[29])
+		s.D0.C1.B1.A0.SetBigInt(results[30])
+		s.D0.C1.B1.A1.SetBigInt(results[31])
+		s.D0.C2.B0.A0.SetBigInt(results[32])
+		s.D0.C2.B0.A1.SetBigInt(results[33])
+		s.D0.C2.B1.A0.SetBigInt(results[34])
+		s.D0.C2.B1.A1.SetBigInt(results[35])
+		if i == 1 {
+			s.Mul(&s, &residueScaling)
+		}
+
+		lhs.Exp(&c, *lambda)
+		rhs.Mul(&f, &s)
+		assert.True(lhs.Equal(&rhs), "f⋅s = c^λ")
+	}
+}
+
 func pairingData() (P bls24315.G1Affine, Q bls24315.G2Affine, milRes bls24315.E24, pairingRes bls24315.GT) {
 	_, _, P, Q = bls24315.Generators()
 	milRes, _ = bls24315.MillerLoop([]bls24315.G1Affine{P}, []bls24315.G2Affine{Q})
@@ -193,3 +317,9 @@ func BenchmarkTriplePairing(b *testing.B) {
 	ccsBench, _ = frontend.Compile(ecc.BW6_633, r1cs.NewBuilder, &c)
 	b.Log("groth16", ccsBench.GetNbConstraints())
 }
+
+func BenchmarkPairingCheck(b *testing.B) {
+	var c pairingCheckBLS24315
+	ccsBench, _ = frontend.Compile(ecc.BW6_633, r1cs.NewBuilder, &c)
+	b.Log("groth16", ccsBench.GetNbConstraints())
+}
@@ -0,0 +1,131 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sw_bls24315
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bls24315 "github.com/consensys/gnark-crypto/ecc/bls24-315"
+	"github.com/consensys/gnark-crypto/ecc/bls24-315/fp"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+type subGroupCircuit struct {
+	P G1Affine
+	Q G2Affine
+}
+
+func (circuit *subGroupCircuit) Define(api frontend.API) error {
+	circuit.P.AssertIsInSubGroup(api)
+	circuit.Q.AssertIsInSubGroup(api)
+	return nil
+}
+
+func TestSubGroup(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	p, q := randomPointG1(), randomPointG2()
+	var pAff bls24315.G1Affine
+	var qAff bls24315.G2Affine
+	pAff.FromJacobian(&p)
+	qAff.FromJacobian(&q)
+
+	var witness subGroupCircuit
+	witness.P.Assign(&pAff)
+	witness.Q.Assign(&qAff)
+	assert.SolvingSucceeded(&subGroupCircuit{}, &witness, test.WithCurves(ecc.BW6_633))
+
+	// a point on the curve, not in G1
+	var one, x, y fp.Element
+	one.SetOne()
+	for {
+		_, _ = x.SetRandom()
+		y.Square(&x).Mul(&y, &x).Add(&y, &one)
+		if y.Legendre() == 1 {
+			break
+		}
+	}
+	y.Sqrt(&y)
+	notInG1 := bls24315.G1Affine{X: x, Y: y}
+	assert.True(notInG1.IsOnCurve() && !notInG1.IsInSubGroup())
+	witness.P.Assign(&notInG1)
+	assert.SolvingFailed(&subGroupCircuit{}, &witness, test.WithCurves(ecc.BW6_633))
+
+	// a point on the twist, not in G2
+	var b, y2 bls24315.E4
+	_, _, _, g2 := bls24315.Generators()
+	y2.Square(&g2.X).Mul(&y2, &g2.X)
+	b.Square(&g2.Y).Sub(&b, &y2)
+	var notInG2 bls24315.G2Affine
+	for {
+		_, _ = notInG2.X.SetRandom()
+		y2.Square(&notInG2.X).Mul(&y2, &notInG2.X).Add(&y2, &b)
+		if y2.Legendre() == 1 {
+			break
+		}
+	}
+	notInG2.Y.Sqrt(&y2)
+	assert.True(notInG2.IsOnCurve() && !notInG2.IsInSubGroup())
+	witness.P.Assign(&pAff)
+	witness.Q.Assign(&notInG2)
+	assert.SolvingFailed(&subGroupCircuit{}, &witness, test.WithCurves(ecc.BW6_633))
+
+	// a point not on the twist
+	qAff.Y.B0.A0.Add(&qAff.Y.B0.A0, &one)
+	witness.Q.Assign(&qAff)
+	assert.SolvingFailed(&subGroupCircuit{}, &witness, test.WithCurves(ecc.BW6_633))
+}
+
+type gtCircuit struct {
+	E GT
+}
+
+func (circuit *gtCircuit) Define(api frontend.API) error {
+	AssertIsInGT(api, circuit.E)
+	return nil
+}
+
+func TestAssertIsInGT(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	_, _, g1, g2 := bls24315.Generators()
+	e, err := bls24315.Pair([]bls24315.G1Affine{g1}, []bls24315.G2Affine{g2})
+	assert.NoError(err)
+	var witness gtCircuit
+	witness.E.Assign(&e)
+	assert.SolvingSucceeded(&gtCircuit{}, &witness, test.WithCurves(ecc.BW6_633))
+
+	// f^((p¹²-1)(p⁴+1)) is in the cyclotomic subgroup, not in GT
+	var f, c, t0 bls24315.E24
+	_, _ = f.SetRandom()
+	t0.Inverse(&f)
+	c.Conjugate(&f).Mul(&c, &t0)
+	t0.FrobeniusQuad(&c)
+	c.Mul(&c, &t0)
+	witness.E.Assign(&c)
+	assert.SolvingFailed(&gtCircuit{}, &witness, test.WithCurves(ecc.BW6_633))
+
+	// not in the cyclotomic subgroup
+	witness.E.Assign(&f)
+	assert.SolvingFailed(&gtCircuit{}, &witness, test.WithCurves(ecc.BW6_633))
+
+	// 0 satisfies e^(p⁸+1) = e^(p⁴), and its compressed squares are unconstrained
+	witness.E.Assign(new(bls24315.E24))
+	assert.SolvingFailed(&gtCircuit{}, &witness, test.WithCurves(ecc.BW6_633))
+}
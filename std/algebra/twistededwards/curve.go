@@ -3,6 +3,7 @@ package twistededwards
 import (
 	"github.com/consensys/gnark-crypto/ecc/twistededwards"
 	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
 )
 
 // curve curve is the default twisted edwards companion curve (defined on api.Curve().Fr)
@@ -61,3 +62,41 @@ func (c *curve) DoubleBaseScalarMul(p1, p2 Point, s1, s2 frontend.Variable) Poin
 	p.doubleBaseScalarMul(c.api, &p1, &p2, s1, s2, c.params)
 	return p
 }
+func (c *curve) FixedBaseScalarMul(base Point, scalar frontend.Variable) Point {
+	var p Point
+	p.fixedBaseScalarMul(c.api, &base, scalar, c.params)
+	return p
+}
+func (c *curve) MultiScalarMul(points []Point, scalars []frontend.Variable) Point {
+	var p Point
+	p.multiScalarMul(c.api, points, scalars, c.params)
+	return p
+}
+func (c *curve) MapToCurve(u frontend.Variable) Point {
+	var p Point
+	p.mapToCurve(c.api, u, c.elligator2())
+	return p
+}
+func (c *curve) ClearCofactor(p1 Point) Point {
+	var p Point
+	p.clearCofactor(c.api, &p1, c.params)
+	return p
+}
+func (c *curve) EncodeToCurve(h hash.Hash, dst []byte, msg ...frontend.Variable) (Point, error) {
+	u, err := hash.ToField(h, dst, 1, msg...)
+	if err != nil {
+		return Point{}, err
+	}
+	return c.ClearCofactor(c.MapToCurve(u[0])), nil
+}
+func (c *curve) HashToCurve(h hash.Hash, dst []byte, msg ...frontend.Variable) (Point, error) {
+	u, err := hash.ToField(h, dst, 2, msg...)
+	if err != nil {
+		return Point{}, err
+	}
+	e2 := c.elligator2()
+	var q0, q1 Point
+	q0.mapToCurve(c.api, u[0], e2)
+	q1.mapToCurve(c.api, u[1], e2)
+	return c.ClearCofactor(c.Add(q0, q1)), nil
+}
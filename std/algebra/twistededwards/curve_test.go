@@ -145,6 +145,13 @@ func (circuit *addCircuit) Define(api frontend.API) error {
 		api.AssertIsEqual(res.Y, circuit.ScalarMulResult.Y)
 	}
 
+	{
+		// fixed base scalar mul
+		res := curve.FixedBaseScalarMul(circuit.fixedPoint, circuit.S2)
+		api.AssertIsEqual(res.X, circuit.ScalarMulResult.X)
+		api.AssertIsEqual(res.Y, circuit.ScalarMulResult.Y)
+	}
+
 	{
 		// double scalar mul
 		res := curve.DoubleBaseScalarMul(circuit.P1, circuit.P2, circuit.S1, circuit.S2)
@@ -152,6 +159,17 @@ func (circuit *addCircuit) Define(api frontend.API) error {
 		api.AssertIsEqual(res.Y, circuit.DoubleScalarMulResult.Y)
 	}
 
+	{
+		// multi scalar mul
+		res := curve.MultiScalarMul([]Point{circuit.P1, circuit.P2, circuit.P1}, []frontend.Variable{circuit.S1, circuit.S2, 0})
+		api.AssertIsEqual(res.X, circuit.DoubleScalarMulResult.X)
+		api.AssertIsEqual(res.Y, circuit.DoubleScalarMulResult.Y)
+
+		res = curve.MultiScalarMul([]Point{circuit.P2}, []frontend.Variable{circuit.S2})
+		api.AssertIsEqual(res.X, circuit.ScalarMulResult.X)
+		api.AssertIsEqual(res.Y, circuit.ScalarMulResult.Y)
+	}
+
 	return nil
 }
 
@@ -0,0 +1,190 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package twistededwards
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/hint"
+	"github.com/consensys/gnark/frontend"
+)
+
+// Hashing to the twisted Edwards curves follows RFC 9380: the Elligator 2 map to the Montgomery
+// curve K⋅t² = s³ + J⋅s² + s (section 6.7.1), with J = 2(a+d)/(a-d) and K = 4/(a-d), is followed by
+// the rational map to the twisted Edwards curve (section 6.8.2, appendix D.1) and the
+// multiplication by the cofactor.
+//
+// The Elligator 2 map is computed on the equivalent curve y² = x³ + (J/K)⋅x² + x/K², with s = K⋅x and
+// t = K⋅y, so that the Edwards coordinates are (s/t, (s-1)/(s+1)) = (x/y, (Kx-1)/(Kx+1)).
+//
+// Z is the first non-square of 2, -2, 3, -3, ... in the SNARK field. The quadratic residuosity and the
+// square root are provided by hints and checked in the circuit.
+//
+// EncodeToCurve and HashToCurve hash the message with hash.ToField, which is not a standard suite of
+// RFC 9380.
+
+// elligator2Params are the constants of the Elligator 2 map to a twisted Edwards curve:
+// A = J/K, B = 1/K², K and Z
+type elligator2Params struct {
+	a, b, k, z *big.Int
+}
+
+// elligator2 returns the constants of the Elligator 2 map to the curve, on the SNARK field
+func (c *curve) elligator2() elligator2Params {
+	q := c.api.Curve().Info().Fr.Modulus()
+
+	// K = 4/(a-d), J/K = (a+d)/2
+	var res elligator2Params
+	res.k = new(big.Int).Sub(c.params.A, c.params.D)
+	res.k.ModInverse(res.k.Mod(res.k, q), q).Lsh(res.k, 2).Mod(res.k, q)
+	res.a = new(big.Int).Add(c.params.A, c.params.D)
+	res.a.Mul(res.a, new(big.Int).ModInverse(big.NewInt(2), q)).Mod(res.a, q)
+	res.b = new(big.Int).Mul(res.k, res.k)
+	res.b.ModInverse(res.b.Mod(res.b, q), q)
+
+	res.z = big.NewInt(2)
+	for big.Jacobi(res.z, q) != -1 {
+		res.z.Neg(res.z)
+		if res.z.Sign() > 0 {
+			res.z.Add(res.z, big.NewInt(1))
+		}
+	}
+	res.z.Mod(res.z, q)
+	return res
+}
+
+// mapToCurve returns the image of u by the Elligator 2 map to the twisted Edwards curve, which is on
+// the curve but not necessarily in the prime order subgroup
+func (p *Point) mapToCurve(api frontend.API, u frontend.Variable, e2 elligator2Params) *Point {
+	a, b := e2.a, e2.b
+	g := func(x frontend.Variable) frontend.Variable {
+		return api.Add(api.Mul(x, x, x), api.Mul(x, x, a), api.Mul(x, b))
+	}
+
+	// x1 = -A / (1 + Z⋅u²), and -A if 1 + Z⋅u² = 0
+	den := api.Add(1, api.Mul(u, u, e2.z))
+	den = api.Add(den, api.IsZero(den))
+	x1 := api.Div(api.Neg(a), den)
+	x2 := api.Sub(api.Neg(x1), a)
+
+	// x = x1 and sgn0(y) = 1 if g(x1) is a square, else x = x2 and sgn0(y) = 0
+	e := isSquare(api, g(x1), e2.z)
+	x := api.Select(e, x1, x2)
+	gx := g(x)
+	res, err := api.Compiler().NewHint(SqrtHint, 1, gx, e)
+	if err != nil {
+		// err is non-nil only for invalid number of inputs
+		panic(err)
+	}
+	y := res[0]
+	api.AssertIsEqual(api.Mul(y, y), gx)
+	api.AssertIsEqual(sgn0(api, y), e)
+
+	// (v, w) = (x/y, (Kx-1)/(Kx+1)), and (0, 1) if y = 0 or Kx = -1
+	s := api.Mul(x, e2.k)
+	exc := api.Or(api.IsZero(y), api.IsZero(api.Add(s, 1)))
+	p.X = api.Select(exc, 0, api.Div(x, api.Select(exc, 1, y)))
+	p.Y = api.Select(exc, 1, api.Div(api.Sub(s, 1), api.Select(exc, 1, api.Add(s, 1))))
+	return p
+}
+
+// clearCofactor multiplies p1 by the cofactor of the curve
+func (p *Point) clearCofactor(api frontend.API, p1 *Point, curve *CurveParams) *Point {
+	h := curve.Cofactor
+	if h.Cmp(new(big.Int).Lsh(big.NewInt(1), uint(h.BitLen()-1))) != 0 {
+		return p.scalarMul(api, p1, h, curve)
+	}
+	*p = *p1
+	for i := 1; i < h.BitLen(); i++ {
+		p.double(api, p, curve)
+	}
+	return p
+}
+
+// isSquare returns 1 if a is a square, 0 otherwise: a (resp. z⋅a, for the non-square z) is a square,
+// whose square root is provided by a hint.
+func isSquare(api frontend.API, a frontend.Variable, z *big.Int) frontend.Variable {
+	res, err := api.Compiler().NewHint(IsSquareHint, 2, a, z)
+	if err != nil {
+		// err is non-nil only for invalid number of inputs
+		panic(err)
+	}
+	e, s := res[0], res[1]
+	api.AssertIsBoolean(e)
+	api.AssertIsEqual(api.Mul(s, s), api.Select(e, a, api.Mul(a, z)))
+	return e
+}
+
+// sgn0 returns the parity of v (RFC 9380, section 4.1): v = 2k + b with 2k + b ≤ q, and b = 0 if v = 0
+func sgn0(api frontend.API, v frontend.Variable) frontend.Variable {
+	res, err := api.Compiler().NewHint(Sgn0Hint, 2, v)
+	if err != nil {
+		// err is non-nil only for invalid number of inputs
+		panic(err)
+	}
+	b, k := res[0], res[1]
+	api.AssertIsBoolean(b)
+	api.AssertIsEqual(v, api.Add(api.Mul(k, 2), b))
+	api.AssertIsLessOrEqual(k, new(big.Int).Rsh(api.Curve().Info().Fr.Modulus(), 1))
+	api.AssertIsEqual(api.Mul(b, api.IsZero(v)), 0)
+	return b
+}
+
+// IsSquareHint returns (1, sqrt(a)) if a = inputs[0] is a square in the SNARK field, and
+// (0, sqrt(z⋅a)) otherwise, where z = inputs[1] is a non-square
+var IsSquareHint = func(curve ecc.ID, inputs []*big.Int, results []*big.Int) error {
+	q := curve.Info().Fr.Modulus()
+	a := new(big.Int).Mod(inputs[0], q)
+	if big.Jacobi(a, q) >= 0 {
+		results[0].SetUint64(1)
+	} else {
+		results[0].SetUint64(0)
+		a.Mul(a, inputs[1]).Mod(a, q)
+	}
+	if results[1].ModSqrt(a, q) == nil {
+		return errors.New("z is a square")
+	}
+	return nil
+}
+
+// SqrtHint returns the square root y of inputs[0] in the SNARK field with sgn0(y) = inputs[1]
+var SqrtHint = func(curve ecc.ID, inputs []*big.Int, results []*big.Int) error {
+	q := curve.Info().Fr.Modulus()
+	if results[0].ModSqrt(inputs[0], q) == nil {
+		return errors.New("no square root")
+	}
+	if results[0].Bit(0) != inputs[1].Bit(0) {
+		results[0].Sub(q, results[0]).Mod(results[0], q)
+	}
+	return nil
+}
+
+// Sgn0Hint returns (b, k) such that inputs[0] = 2k + b in the SNARK field, with b ∈ {0, 1}
+var Sgn0Hint = func(curve ecc.ID, inputs []*big.Int, results []*big.Int) error {
+	v := new(big.Int).Mod(inputs[0], curve.Info().Fr.Modulus())
+	results[0].SetUint64(uint64(v.Bit(0)))
+	results[1].Rsh(v, 1)
+	return nil
+}
+
+func init() {
+	hint.Register(IsSquareHint)
+	hint.Register(SqrtHint)
+	hint.Register(Sgn0Hint)
+}
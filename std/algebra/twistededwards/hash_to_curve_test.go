@@ -0,0 +1,229 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package twistededwards
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/twistededwards"
+	gohash "github.com/consensys/gnark-crypto/hash"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/test"
+)
+
+var testDST = []byte("gnark-twistededwards-hash-to-curve-test")
+
+// nativeMiMC is the native counterpart of std/hash/mimc on each SNARK field
+var nativeMiMC = map[ecc.ID]gohash.Hash{
+	ecc.BN254:     gohash.MIMC_BN254,
+	ecc.BLS12_377: gohash.MIMC_BLS12_377,
+	ecc.BLS12_381: gohash.MIMC_BLS12_381,
+	ecc.BW6_761:   gohash.MIMC_BW6_761,
+	ecc.BW6_633:   gohash.MIMC_BW6_633,
+	ecc.BLS24_315: gohash.MIMC_BLS24_315,
+}
+
+type hashToCurveCircuit struct {
+	curveID                 twistededwards.ID
+	U, Msg                  frontend.Variable
+	Mapped, Encoded, Hashed Point
+}
+
+func (circuit *hashToCurveCircuit) Define(api frontend.API) error {
+	curve, err := NewEdCurve(api, circuit.curveID)
+	if err != nil {
+		return err
+	}
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+
+	p := curve.MapToCurve(circuit.U)
+	api.AssertIsEqual(p.X, circuit.Mapped.X)
+	api.AssertIsEqual(p.Y, circuit.Mapped.Y)
+
+	p, err = curve.EncodeToCurve(&h, testDST, circuit.Msg)
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(p.X, circuit.Encoded.X)
+	api.AssertIsEqual(p.Y, circuit.Encoded.Y)
+
+	p, err = curve.HashToCurve(&h, testDST, circuit.Msg)
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(p.X, circuit.Hashed.X)
+	api.AssertIsEqual(p.Y, circuit.Hashed.Y)
+	return nil
+}
+
+func TestHashToCurve(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, curve := range curves {
+		var circuit, witness hashToCurveCircuit
+		circuit.curveID = curve
+
+		snarkCurve, err := GetSnarkCurve(curve)
+		assert.NoError(err)
+		params, err := GetCurveParams(curve)
+		assert.NoError(err)
+		ed := newNativeCurve(params, snarkCurve.Info().Fr.Modulus())
+
+		u, err := rand.Int(rand.Reader, ed.q)
+		assert.NoError(err)
+		msg, err := rand.Int(rand.Reader, ed.q)
+		assert.NoError(err)
+		u1, err := hash.NativeToField(nativeMiMC[snarkCurve].New(), testDST, 1, msg)
+		assert.NoError(err)
+		u2, err := hash.NativeToField(nativeMiMC[snarkCurve].New(), testDST, 2, msg)
+		assert.NoError(err)
+
+		mapped := ed.mapToCurve(u)
+		assert.True(ed.isOnCurve(mapped))
+		encoded := ed.clearCofactor(ed.mapToCurve(u1[0]))
+		assert.True(ed.isOnCurve(encoded) && ed.isInSubGroup(encoded))
+		hashed := ed.clearCofactor(ed.add(ed.mapToCurve(u2[0]), ed.mapToCurve(u2[1])))
+		assert.True(ed.isOnCurve(hashed) && ed.isInSubGroup(hashed))
+
+		witness.U = u
+		witness.Msg = msg
+		witness.Mapped = Point{X: mapped[0], Y: mapped[1]}
+		witness.Encoded = Point{X: encoded[0], Y: encoded[1]}
+		witness.Hashed = Point{X: hashed[0], Y: hashed[1]}
+		assert.SolvingSucceeded(&circuit, &witness, test.WithCurves(snarkCurve))
+
+		witness.Msg = new(big.Int).Add(msg, big.NewInt(1))
+		assert.SolvingFailed(&circuit, &witness, test.WithCurves(snarkCurve))
+	}
+}
+
+// nativeCurve implements the twisted Edwards arithmetic and the Elligator 2 map of RFC 9380 with big.Int
+type nativeCurve struct {
+	params *CurveParams
+	q      *big.Int
+}
+
+func newNativeCurve(params *CurveParams, q *big.Int) nativeCurve {
+	return nativeCurve{params: params, q: q}
+}
+
+func (c nativeCurve) mod(x *big.Int) *big.Int {
+	return x.Mod(x, c.q)
+}
+
+func (c nativeCurve) inv(x *big.Int) *big.Int {
+	return new(big.Int).ModInverse(c.mod(new(big.Int).Set(x)), c.q)
+}
+
+func (c nativeCurve) mul(xs ...*big.Int) *big.Int {
+	res := big.NewInt(1)
+	for _, x := range xs {
+		c.mod(res.Mul(res, x))
+	}
+	return res
+}
+
+func (c nativeCurve) isOnCurve(p [2]*big.Int) bool {
+	xx, yy := c.mul(p[0], p[0]), c.mul(p[1], p[1])
+	lhs := c.mod(new(big.Int).Add(c.mul(c.params.A, xx), yy))
+	rhs := c.mod(new(big.Int).Add(c.mul(c.params.D, xx, yy), big.NewInt(1)))
+	return lhs.Cmp(rhs) == 0
+}
+
+func (c nativeCurve) add(p1, p2 [2]*big.Int) [2]*big.Int {
+	dxy := c.mul(c.params.D, p1[0], p2[0], p1[1], p2[1])
+	x := c.mod(new(big.Int).Add(c.mul(p1[0], p2[1]), c.mul(p1[1], p2[0])))
+	y := c.mod(new(big.Int).Sub(c.mul(p1[1], p2[1]), c.mul(c.params.A, p1[0], p2[0])))
+	x = c.mul(x, c.inv(new(big.Int).Add(big.NewInt(1), dxy)))
+	y = c.mul(y, c.inv(new(big.Int).Sub(big.NewInt(1), dxy)))
+	return [2]*big.Int{x, y}
+}
+
+func (c nativeCurve) scalarMul(p [2]*big.Int, s *big.Int) [2]*big.Int {
+	res := [2]*big.Int{big.NewInt(0), big.NewInt(1)}
+	for i := s.BitLen() - 1; i >= 0; i-- {
+		res = c.add(res, res)
+		if s.Bit(i) == 1 {
+			res = c.add(res, p)
+		}
+	}
+	return res
+}
+
+func (c nativeCurve) clearCofactor(p [2]*big.Int) [2]*big.Int {
+	return c.scalarMul(p, c.params.Cofactor)
+}
+
+func (c nativeCurve) isInSubGroup(p [2]*big.Int) bool {
+	r := c.scalarMul(p, c.params.Order)
+	return r[0].Sign() == 0 && r[1].Cmp(big.NewInt(1)) == 0
+}
+
+// mapToCurve is the Elligator 2 map to the Montgomery curve K⋅t² = s³ + J⋅s² + s, followed by
+// the rational map to the twisted Edwards curve (RFC 9380, sections 6.7.1 and 6.8.2)
+func (c nativeCurve) mapToCurve(u *big.Int) [2]*big.Int {
+	amd := c.mod(new(big.Int).Sub(c.params.A, c.params.D))
+	j := c.mul(big.NewInt(2), new(big.Int).Add(c.params.A, c.params.D), c.inv(amd))
+	k := c.mul(big.NewInt(4), c.inv(amd))
+	z := big.NewInt(2)
+	for big.Jacobi(z, c.q) != -1 {
+		if z.Sign() > 0 {
+			z.Neg(z)
+		} else {
+			z.Neg(z).Add(z, big.NewInt(1))
+		}
+	}
+
+	// Montgomery curve t² = s³ + A⋅s² + B⋅s with A = J/K, B = 1/K²
+	a := c.mul(j, c.inv(k))
+	b := c.inv(c.mul(k, k))
+	g := func(x *big.Int) *big.Int {
+		return c.mod(new(big.Int).Add(new(big.Int).Add(c.mul(x, x, x), c.mul(a, x, x)), c.mul(b, x)))
+	}
+
+	tv1 := c.mod(new(big.Int).Add(big.NewInt(1), c.mul(z, u, u)))
+	var x1 *big.Int
+	if tv1.Sign() == 0 {
+		x1 = c.mod(new(big.Int).Neg(a))
+	} else {
+		x1 = c.mul(new(big.Int).Neg(a), c.inv(tv1))
+	}
+	x2 := c.mod(new(big.Int).Sub(new(big.Int).Neg(x1), a))
+	x, sign := x2, uint(0)
+	if big.Jacobi(g(x1), c.q) >= 0 {
+		x, sign = x1, 1
+	}
+	y := new(big.Int).ModSqrt(g(x), c.q)
+	if y.Bit(0) != sign {
+		c.mod(y.Neg(y))
+	}
+
+	// rational map to the twisted Edwards curve
+	s, t := c.mul(x, k), c.mul(y, k)
+	sp1 := c.mod(new(big.Int).Add(s, big.NewInt(1)))
+	if t.Sign() == 0 || sp1.Sign() == 0 {
+		return [2]*big.Int{big.NewInt(0), big.NewInt(1)}
+	}
+	return [2]*big.Int{c.mul(s, c.inv(t)), c.mul(new(big.Int).Sub(s, big.NewInt(1)), c.inv(sp1))}
+}
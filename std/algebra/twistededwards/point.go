@@ -18,6 +18,7 @@ package twistededwards
 
 import (
 	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/bits"
 )
 
 // neg computes the negative of a point in SNARK coordinates
@@ -172,3 +173,110 @@ func (p *Point) doubleBaseScalarMul(api frontend.API, p1, p2 *Point, s1, s2 fron
 
 	return p
 }
+
+// fixedBaseScalarMul computes the scalar multiplication of a fixed point on a twisted Edwards curve
+// base: base point, whose coordinates are constants of the circuit
+// curve: parameters of the Edwards curve
+// scal: scalar as a SNARK constraint
+// The scalar is processed in windows of 2 bits: the k-th window selects [c*4^k]base (c in {0,1,2,3})
+// in a table, which is made of constants since it is computed from the base only. Hence there
+// are no doublings, and the lookups are linear combinations of constants.
+func (p *Point) fixedBaseScalarMul(api frontend.API, base *Point, scalar frontend.Variable, curve *CurveParams) *Point {
+
+	// first unpack the scalar; the decomposition must be unique, since [scalar+p]base != [scalar]base where p is the modulus of the field
+	b := bits.ToBinary(api, scalar, bits.WithModulusCheck())
+	if len(b)%2 == 1 {
+		b = append(b, 0)
+	}
+
+	res := Point{}
+	tmp := Point{}
+	q1 := *base
+	q2 := Point{}
+	q3 := Point{}
+
+	for k := 0; k < len(b); k += 2 {
+		q2.double(api, &q1, curve)
+		q3.add(api, &q2, &q1, curve)
+
+		// the lookups are c0 + b0*(c1-c0) + b1*(c2-c0) + b0*b1*(c3-c2-c1+c0), sharing b0*b1
+		b0b1 := api.Mul(b[k], b[k+1])
+		tmp.X = lookup2Constants(api, b[k], b[k+1], b0b1, 0, q1.X, q2.X, q3.X)
+		tmp.Y = lookup2Constants(api, b[k], b[k+1], b0b1, 1, q1.Y, q2.Y, q3.Y)
+		if k == 0 {
+			res = tmp
+		} else {
+			res.add(api, &res, &tmp, curve)
+		}
+
+		q1.double(api, &q2, curve)
+	}
+
+	p.X = res.X
+	p.Y = res.Y
+
+	return p
+}
+
+// lookup2Constants returns i0, i1, i2 or i3 depending on the bits b0, b1 (b0 being the least
+// significant one), as api.Lookup2, given b0b1 = b0*b1
+func lookup2Constants(api frontend.API, b0, b1, b0b1 frontend.Variable, i0, i1, i2, i3 frontend.Variable) frontend.Variable {
+	res := api.Add(i0, api.Mul(b0, api.Sub(i1, i0)), api.Mul(b1, api.Sub(i2, i0)))
+	return api.Add(res, api.Mul(b0b1, api.Sub(api.Add(i3, i0), i1, i2)))
+}
+
+// multiScalarMul computes s1*P1+...+sn*Pn
+// where P1...Pn are points on a twisted Edwards curve
+// and s1...sn scalars.
+// The scalars are processed in windows of 2 bits, from the most significant one: the doublings
+// are shared by the n points, and each window of each point costs one lookup and one addition.
+func (p *Point) multiScalarMul(api frontend.API, points []Point, scalars []frontend.Variable, curve *CurveParams) *Point {
+	if len(points) != len(scalars) {
+		panic("number of points and number of scalars mismatch")
+	}
+	if len(points) == 0 {
+		p.X = 0
+		p.Y = 1
+		return p
+	}
+
+	// first unpack the scalars and compute the tables [0, P, 2P, 3P]
+	bits := make([][]frontend.Variable, len(points))
+	tables := make([][4]Point, len(points))
+	for i := range points {
+		bits[i] = api.ToBinary(scalars[i])
+		if len(bits[i])%2 == 1 {
+			bits[i] = append(bits[i], 0)
+		}
+		tables[i][0] = Point{X: 0, Y: 1}
+		tables[i][1] = points[i]
+		tables[i][2].double(api, &points[i], curve)
+		tables[i][3].add(api, &tables[i][2], &points[i], curve)
+	}
+
+	res := Point{}
+	tmp := Point{}
+
+	n := len(bits[0])
+	for k := n - 2; k >= 0; k -= 2 {
+		if k != n-2 {
+			res.double(api, &res, curve).
+				double(api, &res, curve)
+		}
+		for i := range points {
+			t := &tables[i]
+			tmp.X = api.Lookup2(bits[i][k], bits[i][k+1], t[0].X, t[1].X, t[2].X, t[3].X)
+			tmp.Y = api.Lookup2(bits[i][k], bits[i][k+1], t[0].Y, t[1].Y, t[2].Y, t[3].Y)
+			if k == n-2 && i == 0 {
+				res = tmp
+			} else {
+				res.add(api, &res, &tmp, curve)
+			}
+		}
+	}
+
+	p.X = res.X
+	p.Y = res.Y
+
+	return p
+}
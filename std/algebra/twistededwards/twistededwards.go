@@ -30,6 +30,7 @@ import (
 	edbw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761/twistededwards"
 	"github.com/consensys/gnark-crypto/ecc/twistededwards"
 	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
 )
 
 // Curve methods implemented by a twisted edwards curve inside a circuit
@@ -42,6 +43,22 @@ type Curve interface {
 	AssertIsOnCurve(p1 Point)
 	ScalarMul(p1 Point, scalar frontend.Variable) Point
 	DoubleBaseScalarMul(p1, p2 Point, s1, s2 frontend.Variable) Point
+	// FixedBaseScalarMul computes [scalar]base, where base is a constant point
+	// (for instance the base point of the curve), using precomputed tables of constants
+	FixedBaseScalarMul(base Point, scalar frontend.Variable) Point
+	// MultiScalarMul computes Σ [scalars[i]]points[i]
+	MultiScalarMul(points []Point, scalars []frontend.Variable) Point
+	// MapToCurve maps the field element u to the curve with the Elligator 2 map (RFC 9380),
+	// the result is not necessarily in the prime order subgroup
+	MapToCurve(u frontend.Variable) Point
+	// ClearCofactor multiplies p1 by the cofactor of the curve
+	ClearCofactor(p1 Point) Point
+	// EncodeToCurve hashes msg to the prime order subgroup, with a non-uniform distribution
+	// (encode_to_curve, RFC 9380), using hash.ToField and the domain separation tag dst
+	EncodeToCurve(h hash.Hash, dst []byte, msg ...frontend.Variable) (Point, error)
+	// HashToCurve hashes msg to the prime order subgroup, with a uniform distribution
+	// (hash_to_curve, RFC 9380), using hash.ToField and the domain separation tag dst
+	HashToCurve(h hash.Hash, dst []byte, msg ...frontend.Variable) (Point, error)
 	API() frontend.API
 }
 
@@ -0,0 +1,134 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pedersen
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	edwards "github.com/consensys/gnark/std/algebra/twistededwards"
+	"github.com/consensys/gnark/std/math/bits"
+)
+
+// Pedersen computes Pedersen hashes and commitments in a circuit
+type Pedersen struct {
+	api    frontend.API
+	curve  edwards.Curve
+	params *Params
+}
+
+// New returns a Pedersen gadget with given parameters. The twisted Edwards curve of the parameters
+// must be defined on the SNARK field.
+func New(api frontend.API, params *Params) (*Pedersen, error) {
+	curve, err := edwards.NewEdCurve(api, params.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &Pedersen{
+		api:    api,
+		curve:  curve,
+		params: params,
+	}, nil
+}
+
+// Hash returns the Pedersen hash of the field elements data.
+//
+// The field elements are decomposed in bits (little-endian), which are concatenated and split in
+// segments of size Params.SegmentSize(). The hash is Σ s_i⋅Generators[i], where s_i is the i-th segment.
+// The abscissa of the hash is a collision resistant hash of data.
+func (p *Pedersen) Hash(data ...frontend.Variable) (edwards.Point, error) {
+	if err := p.params.checkHash(len(data)); err != nil {
+		return edwards.Point{}, err
+	}
+	// the decomposition must be canonical, so that the hash of data is unique
+	b := make([]frontend.Variable, 0, len(data)*p.params.nbBits)
+	for _, d := range data {
+		b = append(b, bits.ToBinary(p.api, d, bits.WithModulusCheck())...)
+	}
+
+	// s_i⋅G_i is computed by windows of 2 bits of the segment, as FixedBaseScalarMul does with the bits
+	// of its scalar: the k-th window selects a point of the constant table [0, 4ᵏ⋅G_i, 2⋅4ᵏ⋅G_i, 3⋅4ᵏ⋅G_i],
+	// which is added to the hash
+	res := edwards.Point{X: 0, Y: 1}
+	first := true
+	segmentSize := p.params.SegmentSize()
+	for i := 0; i*segmentSize < len(b); i++ {
+		end := (i + 1) * segmentSize
+		if end > len(b) {
+			end = len(b)
+		}
+		segment := b[i*segmentSize : end]
+
+		q1 := point(p.params.Generators[i])
+		for k := 0; k < len(segment); k += 2 {
+			b0, b1 := segment[k], frontend.Variable(0)
+			if k+1 < len(segment) {
+				b1 = segment[k+1]
+			}
+			q2 := p.curve.Double(q1)
+			q3 := p.curve.Add(q2, q1)
+
+			b0b1 := p.api.Mul(b0, b1)
+			tmp := edwards.Point{
+				X: lookup2Constants(p.api, b0, b1, b0b1, 0, q1.X, q2.X, q3.X),
+				Y: lookup2Constants(p.api, b0, b1, b0b1, 1, q1.Y, q2.Y, q3.Y),
+			}
+			if first {
+				res = tmp
+				first = false
+			} else {
+				res = p.curve.Add(res, tmp)
+			}
+
+			q1 = p.curve.Double(q2)
+		}
+	}
+	return res, nil
+}
+
+// lookup2Constants returns i0, i1, i2 or i3 depending on the bits b0, b1 (b0 being the least
+// significant one), as api.Lookup2, given b0b1 = b0*b1. With constant i0...i3, it costs no
+// constraint besides b0b1.
+func lookup2Constants(api frontend.API, b0, b1, b0b1 frontend.Variable, i0, i1, i2, i3 frontend.Variable) frontend.Variable {
+	res := api.Add(i0, api.Mul(b0, api.Sub(i1, i0)), api.Mul(b1, api.Sub(i2, i0)))
+	return api.Add(res, api.Mul(b0b1, api.Sub(api.Add(i3, i0), i1, i2)))
+}
+
+// Commit returns the Pedersen commitment Σ values[i]⋅Generators[i] + randomness⋅Blinding
+//
+// The values and the randomness are scalars modulo the order of the prime subgroup.
+func (p *Pedersen) Commit(values []frontend.Variable, randomness frontend.Variable) (edwards.Point, error) {
+	if err := p.params.checkCommit(len(values)); err != nil {
+		return edwards.Point{}, err
+	}
+	res := p.curve.FixedBaseScalarMul(point(p.params.Blinding), randomness)
+	for i, v := range values {
+		res = p.curve.Add(res, p.curve.FixedBaseScalarMul(point(p.params.Generators[i]), v))
+	}
+	return res, nil
+}
+
+// CommitValue returns the value commitment value⋅Generators[0] + randomness⋅Blinding
+func (p *Pedersen) CommitValue(value, randomness frontend.Variable) edwards.Point {
+	res, _ := p.Commit([]frontend.Variable{value}, randomness) // there is at least one generator
+	return res
+}
+
+// point returns the generator g as a constant point of the circuit
+func point(g [2]*big.Int) edwards.Point {
+	return edwards.Point{X: g[0], Y: g[1]}
+}
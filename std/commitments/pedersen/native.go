@@ -0,0 +1,228 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pedersen
+
+import (
+	"math/big"
+
+	edbls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377/twistededwards"
+	edbls12381_bandersnatch "github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch"
+	edbls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/twistededwards"
+	edbls24315 "github.com/consensys/gnark-crypto/ecc/bls24-315/twistededwards"
+	edbn254 "github.com/consensys/gnark-crypto/ecc/bn254/twistededwards"
+	edbw6633 "github.com/consensys/gnark-crypto/ecc/bw6-633/twistededwards"
+	edbw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761/twistededwards"
+	"github.com/consensys/gnark-crypto/ecc/twistededwards"
+)
+
+// Hash returns the Pedersen hash of the field elements data, as computed by Pedersen.Hash in a circuit.
+func (p *Params) Hash(data ...*big.Int) ([2]*big.Int, error) {
+	if err := p.checkHash(len(data)); err != nil {
+		return [2]*big.Int{}, err
+	}
+	bits := make([]uint, 0, len(data)*p.nbBits)
+	for _, d := range data {
+		v := new(big.Int).Mod(d, p.modulus)
+		for i := 0; i < p.nbBits; i++ {
+			bits = append(bits, v.Bit(i))
+		}
+	}
+
+	res := identity()
+	segmentSize := p.SegmentSize()
+	for i := 0; i*segmentSize < len(bits); i++ {
+		end := (i + 1) * segmentSize
+		if end > len(bits) {
+			end = len(bits)
+		}
+		s := new(big.Int)
+		for j := end - 1; j >= i*segmentSize; j-- {
+			s.Lsh(s, 1).SetBit(s, 0, bits[j])
+		}
+		res = p.ops.add(res, p.ops.scalarMul(p.Generators[i], s))
+	}
+	return res, nil
+}
+
+// Commit returns the Pedersen commitment Σ values[i]⋅Generators[i] + randomness⋅Blinding,
+// as computed by Pedersen.Commit in a circuit.
+func (p *Params) Commit(values []*big.Int, randomness *big.Int) ([2]*big.Int, error) {
+	if err := p.checkCommit(len(values)); err != nil {
+		return [2]*big.Int{}, err
+	}
+	res := p.ops.scalarMul(p.Blinding, new(big.Int).Mod(randomness, p.modulus))
+	for i, v := range values {
+		res = p.ops.add(res, p.ops.scalarMul(p.Generators[i], new(big.Int).Mod(v, p.modulus)))
+	}
+	return res, nil
+}
+
+// CommitValue returns the value commitment value⋅Generators[0] + randomness⋅Blinding
+func (p *Params) CommitValue(value, randomness *big.Int) [2]*big.Int {
+	res, _ := p.Commit([]*big.Int{value}, randomness) // there is at least one generator
+	return res
+}
+
+func identity() [2]*big.Int {
+	return [2]*big.Int{big.NewInt(0), big.NewInt(1)}
+}
+
+// nativeOps are the operations of a twisted Edwards curve on affine points with big.Int coordinates
+type nativeOps struct {
+	add       func(p1, p2 [2]*big.Int) [2]*big.Int
+	scalarMul func(p1 [2]*big.Int, scalar *big.Int) [2]*big.Int
+}
+
+func newNativeOps(id twistededwards.ID) nativeOps {
+	switch id {
+	case twistededwards.BN254:
+		from := func(p [2]*big.Int) (r edbn254.PointAffine) {
+			r.X.SetBigInt(p[0])
+			r.Y.SetBigInt(p[1])
+			return
+		}
+		to := func(p *edbn254.PointAffine) [2]*big.Int {
+			return [2]*big.Int{p.X.ToBigIntRegular(new(big.Int)), p.Y.ToBigIntRegular(new(big.Int))}
+		}
+		return nativeOps{
+			add: func(p1, p2 [2]*big.Int) [2]*big.Int {
+				a, b := from(p1), from(p2)
+				return to(a.Add(&a, &b))
+			},
+			scalarMul: func(p1 [2]*big.Int, scalar *big.Int) [2]*big.Int {
+				a := from(p1)
+				return to(a.ScalarMul(&a, scalar))
+			},
+		}
+	case twistededwards.BLS12_377:
+		from := func(p [2]*big.Int) (r edbls12377.PointAffine) {
+			r.X.SetBigInt(p[0])
+			r.Y.SetBigInt(p[1])
+			return
+		}
+		to := func(p *edbls12377.PointAffine) [2]*big.Int {
+			return [2]*big.Int{p.X.ToBigIntRegular(new(big.Int)), p.Y.ToBigIntRegular(new(big.Int))}
+		}
+		return nativeOps{
+			add: func(p1, p2 [2]*big.Int) [2]*big.Int {
+				a, b := from(p1), from(p2)
+				return to(a.Add(&a, &b))
+			},
+			scalarMul: func(p1 [2]*big.Int, scalar *big.Int) [2]*big.Int {
+				a := from(p1)
+				return to(a.ScalarMul(&a, scalar))
+			},
+		}
+	case twistededwards.BLS12_381:
+		from := func(p [2]*big.Int) (r edbls12381.PointAffine) {
+			r.X.SetBigInt(p[0])
+			r.Y.SetBigInt(p[1])
+			return
+		}
+		to := func(p *edbls12381.PointAffine) [2]*big.Int {
+			return [2]*big.Int{p.X.ToBigIntRegular(new(big.Int)), p.Y.ToBigIntRegular(new(big.Int))}
+		}
+		return nativeOps{
+			add: func(p1, p2 [2]*big.Int) [2]*big.Int {
+				a, b := from(p1), from(p2)
+				return to(a.Add(&a, &b))
+			},
+			scalarMul: func(p1 [2]*big.Int, scalar *big.Int) [2]*big.Int {
+				a := from(p1)
+				return to(a.ScalarMul(&a, scalar))
+			},
+		}
+	case twistededwards.BLS12_381_BANDERSNATCH:
+		from := func(p [2]*big.Int) (r edbls12381_bandersnatch.PointAffine) {
+			r.X.SetBigInt(p[0])
+			r.Y.SetBigInt(p[1])
+			return
+		}
+		to := func(p *edbls12381_bandersnatch.PointAffine) [2]*big.Int {
+			return [2]*big.Int{p.X.ToBigIntRegular(new(big.Int)), p.Y.ToBigIntRegular(new(big.Int))}
+		}
+		return nativeOps{
+			add: func(p1, p2 [2]*big.Int) [2]*big.Int {
+				a, b := from(p1), from(p2)
+				return to(a.Add(&a, &b))
+			},
+			scalarMul: func(p1 [2]*big.Int, scalar *big.Int) [2]*big.Int {
+				a := from(p1)
+				return to(a.ScalarMul(&a, scalar))
+			},
+		}
+	case twistededwards.BLS24_315:
+		from := func(p [2]*big.Int) (r edbls24315.PointAffine) {
+			r.X.SetBigInt(p[0])
+			r.Y.SetBigInt(p[1])
+			return
+		}
+		to := func(p *edbls24315.PointAffine) [2]*big.Int {
+			return [2]*big.Int{p.X.ToBigIntRegular(new(big.Int)), p.Y.ToBigIntRegular(new(big.Int))}
+		}
+		return nativeOps{
+			add: func(p1, p2 [2]*big.Int) [2]*big.Int {
+				a, b := from(p1), from(p2)
+				return to(a.Add(&a, &b))
+			},
+			scalarMul: func(p1 [2]*big.Int, scalar *big.Int) [2]*big.Int {
+				a := from(p1)
+				return to(a.ScalarMul(&a, scalar))
+			},
+		}
+	case twistededwards.BW6_761:
+		from := func(p [2]*big.Int) (r edbw6761.PointAffine) {
+			r.X.SetBigInt(p[0])
+			r.Y.SetBigInt(p[1])
+			return
+		}
+		to := func(p *edbw6761.PointAffine) [2]*big.Int {
+			return [2]*big.Int{p.X.ToBigIntRegular(new(big.Int)), p.Y.ToBigIntRegular(new(big.Int))}
+		}
+		return nativeOps{
+			add: func(p1, p2 [2]*big.Int) [2]*big.Int {
+				a, b := from(p1), from(p2)
+				return to(a.Add(&a, &b))
+			},
+			scalarMul: func(p1 [2]*big.Int, scalar *big.Int) [2]*big.Int {
+				a := from(p1)
+				return to(a.ScalarMul(&a, scalar))
+			},
+		}
+	case twistededwards.BW6_633:
+		from := func(p [2]*big.Int) (r edbw6633.PointAffine) {
+			r.X.SetBigInt(p[0])
+			r.Y.SetBigInt(p[1])
+			return
+		}
+		to := func(p *edbw6633.PointAffine) [2]*big.Int {
+			return [2]*big.Int{p.X.ToBigIntRegular(new(big.Int)), p.Y.ToBigIntRegular(new(big.Int))}
+		}
+		return nativeOps{
+			add: func(p1, p2 [2]*big.Int) [2]*big.Int {
+				a, b := from(p1), from(p2)
+				return to(a.Add(&a, &b))
+			},
+			scalarMul: func(p1 [2]*big.Int, scalar *big.Int) [2]*big.Int {
+				a := from(p1)
+				return to(a.ScalarMul(&a, scalar))
+			},
+		}
+	default:
+		panic("unknown twisted edwards curve id")
+	}
+}
@@ -0,0 +1,182 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package pedersen provides ZKP-circuit functions to compute Pedersen hashes and commitments
+// on the twisted Edwards curves embedded in the SNARK fields (see std/algebra/twistededwards).
+//
+// The hash of a message m splits the bits of m in segments s_0, s_1, ... shorter than the order
+// of the prime subgroup, and returns Σ s_i⋅G_i. The commitment to values v_0, v_1, ... with
+// randomness r is Σ v_i⋅G_i + r⋅H. The value commitment v⋅G + r⋅H is the commitment to a single value.
+//
+// The generators are derived deterministically from the curve, so that nobody knows their discrete
+// logarithms relative to each other: see NewParams. Since they are constants of the circuit, the
+// scalar multiplications use precomputed window tables and cost about one point addition per 2 bits.
+//
+// The native methods of Params compute the same hashes and commitments, with gnark-crypto.
+package pedersen
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/twistededwards"
+	edwards "github.com/consensys/gnark/std/algebra/twistededwards"
+)
+
+// domain separation tag of the derivation of the generators
+const domain = "gnark/std/commitments/pedersen"
+
+// Params are the public parameters of the Pedersen hashes and commitments on a twisted Edwards curve.
+type Params struct {
+	ID twistededwards.ID
+
+	// Generators[i] multiplies the i-th segment of a hashed message, or the i-th committed value
+	Generators [][2]*big.Int
+
+	// Blinding multiplies the randomness of a commitment
+	Blinding [2]*big.Int
+
+	curve   *edwards.CurveParams
+	ops     nativeOps
+	modulus *big.Int // modulus of the SNARK field, on which the curve is defined
+	nbBits  int      // number of bits of the field elements
+}
+
+// NewParams returns the parameters of the twisted Edwards curve id, with nbGenerators generators.
+//
+// The generators (and the blinding generator) are derived by try-and-increment:
+// y = SHA256(domain ∥ curve ∥ label ∥ index ∥ counter) mod p, for the first counter such that y is
+// the ordinate of a point P = (x, y) on the curve (taking the smallest x), and cofactor⋅P isn't the
+// identity. The generator is cofactor⋅P, which belongs to the prime subgroup.
+func NewParams(id twistededwards.ID, nbGenerators int) (*Params, error) {
+	name, ok := curveNames[id]
+	if !ok {
+		return nil, errors.New("unknown twisted edwards curve id")
+	}
+	if nbGenerators < 1 {
+		return nil, errors.New("at least one generator is required")
+	}
+	curve, err := edwards.GetCurveParams(id)
+	if err != nil {
+		return nil, err
+	}
+	snarkCurve, err := edwards.GetSnarkCurve(id)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Params{
+		ID:         id,
+		Generators: make([][2]*big.Int, nbGenerators),
+		curve:      curve,
+		ops:        newNativeOps(id),
+		modulus:    snarkCurve.Info().Fr.Modulus(),
+		nbBits:     snarkCurve.Info().Fr.Bits,
+	}
+	for i := range p.Generators {
+		p.Generators[i] = p.deriveGenerator(name, "G", uint32(i))
+	}
+	p.Blinding = p.deriveGenerator(name, "H", 0)
+
+	return p, nil
+}
+
+// SegmentSize returns the number of bits of the segments of a hashed message, which is less than
+// the number of bits of the order of the prime subgroup, so that the segments are canonical scalars.
+func (p *Params) SegmentSize() int {
+	return p.curve.Order.BitLen() - 1
+}
+
+// NbGenerators returns the number of generators needed to hash nbElements field elements
+func (p *Params) NbGenerators(nbElements int) int {
+	return (nbElements*p.nbBits + p.SegmentSize() - 1) / p.SegmentSize()
+}
+
+// checkHash returns an error if there aren't enough generators to hash nbElements field elements
+func (p *Params) checkHash(nbElements int) error {
+	if n := p.NbGenerators(nbElements); n > len(p.Generators) {
+		return fmt.Errorf("hashing %d elements requires %d generators, got %d", nbElements, n, len(p.Generators))
+	}
+	return nil
+}
+
+// checkCommit returns an error if there aren't enough generators to commit to nbValues values
+func (p *Params) checkCommit(nbValues int) error {
+	if nbValues > len(p.Generators) {
+		return fmt.Errorf("committing to %d values requires %d generators, got %d", nbValues, nbValues, len(p.Generators))
+	}
+	return nil
+}
+
+// deriveGenerator returns the generator of given label and index, see NewParams
+func (p *Params) deriveGenerator(name, label string, index uint32) [2]*big.Int {
+	var buf [4]byte
+	one := big.NewInt(1)
+	for counter := uint32(0); ; counter++ {
+		h := sha256.New()
+		h.Write([]byte(domain))
+		h.Write([]byte(name))
+		h.Write([]byte(label))
+		binary.BigEndian.PutUint32(buf[:], index)
+		h.Write(buf[:])
+		binary.BigEndian.PutUint32(buf[:], counter)
+		h.Write(buf[:])
+
+		y := new(big.Int).SetBytes(h.Sum(nil))
+		y.Mod(y, p.modulus)
+
+		// a⋅x² + y² = 1 + d⋅x²⋅y² ⇔ x² = (1 - y²) / (a - d⋅y²)
+		y2 := new(big.Int).Mul(y, y)
+		num := new(big.Int).Sub(one, y2)
+		den := new(big.Int).Mul(p.curve.D, y2)
+		den.Sub(p.curve.A, den).Mod(den, p.modulus)
+		if den.Sign() == 0 {
+			continue
+		}
+		x2 := num.Mul(num, den.ModInverse(den, p.modulus))
+		x2.Mod(x2, p.modulus)
+		x := new(big.Int).ModSqrt(x2, p.modulus)
+		if x == nil {
+			continue
+		}
+		if negX := new(big.Int).Sub(p.modulus, x); negX.Cmp(x) < 0 {
+			x = negX
+		}
+
+		g := p.ops.scalarMul([2]*big.Int{x, y}, p.curve.Cofactor)
+		if isIdentity(g) {
+			continue
+		}
+		return g
+	}
+}
+
+func isIdentity(p [2]*big.Int) bool {
+	return p[0].Sign() == 0 && p[1].Cmp(big.NewInt(1)) == 0
+}
+
+var curveNames = map[twistededwards.ID]string{
+	twistededwards.BN254:                  "bn254",
+	twistededwards.BLS12_377:              "bls12_377",
+	twistededwards.BLS12_381:              "bls12_381",
+	twistededwards.BLS12_381_BANDERSNATCH: "bls12_381_bandersnatch",
+	twistededwards.BLS24_315:              "bls24_315",
+	twistededwards.BW6_761:                "bw6_761",
+	twistededwards.BW6_633:                "bw6_633",
+}
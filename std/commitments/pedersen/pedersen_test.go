@@ -0,0 +1,163 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pedersen
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	edwards "github.com/consensys/gnark/std/algebra/twistededwards"
+	"github.com/consensys/gnark/test"
+)
+
+type pedersenCircuit struct {
+	id     twistededwards.ID
+	Data   [2]frontend.Variable
+	Value  frontend.Variable
+	Values [3]frontend.Variable
+	R      frontend.Variable
+
+	Hash            edwards.Point `gnark:",public"`
+	ValueCommitment edwards.Point `gnark:",public"`
+	Commitment      edwards.Point `gnark:",public"`
+}
+
+func (circuit *pedersenCircuit) Define(api frontend.API) error {
+	params, err := NewParams(circuit.id, 3)
+	if err != nil {
+		return err
+	}
+	ped, err := New(api, params)
+	if err != nil {
+		return err
+	}
+
+	h, err := ped.Hash(circuit.Data[:]...)
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(h.X, circuit.Hash.X)
+	api.AssertIsEqual(h.Y, circuit.Hash.Y)
+
+	vc := ped.CommitValue(circuit.Value, circuit.R)
+	api.AssertIsEqual(vc.X, circuit.ValueCommitment.X)
+	api.AssertIsEqual(vc.Y, circuit.ValueCommitment.Y)
+
+	c, err := ped.Commit(circuit.Values[:], circuit.R)
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(c.X, circuit.Commitment.X)
+	api.AssertIsEqual(c.Y, circuit.Commitment.Y)
+	return nil
+}
+
+func randomScalar(t *testing.T, max *big.Int) *big.Int {
+	s, err := rand.Int(rand.Reader, max)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestPedersen(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	ids := []twistededwards.ID{
+		twistededwards.BN254,
+		twistededwards.BLS12_381_BANDERSNATCH,
+	}
+	if !testing.Short() {
+		ids = append(ids,
+			twistededwards.BLS12_377,
+			twistededwards.BLS12_381,
+			twistededwards.BLS24_315,
+			twistededwards.BW6_761,
+			twistededwards.BW6_633,
+		)
+	}
+
+	for _, id := range ids {
+		snarkCurve, err := edwards.GetSnarkCurve(id)
+		assert.NoError(err)
+		params, err := NewParams(id, 3)
+		assert.NoError(err)
+		assert.Equal(3, params.NbGenerators(2), "2 field elements are hashed with 3 segments")
+
+		modulus := snarkCurve.Info().Fr.Modulus()
+		data := []*big.Int{randomScalar(t, modulus), randomScalar(t, modulus)}
+		value, r := randomScalar(t, modulus), randomScalar(t, modulus)
+		values := []*big.Int{randomScalar(t, modulus), randomScalar(t, modulus), randomScalar(t, modulus)}
+
+		h, err := params.Hash(data...)
+		assert.NoError(err)
+		vc := params.CommitValue(value, r)
+		c, err := params.Commit(values, r)
+		assert.NoError(err)
+
+		var witness pedersenCircuit
+		witness.Data = [2]frontend.Variable{data[0], data[1]}
+		witness.Value = value
+		witness.Values = [3]frontend.Variable{values[0], values[1], values[2]}
+		witness.R = r
+		witness.Hash = edwards.Point{X: h[0], Y: h[1]}
+		witness.ValueCommitment = edwards.Point{X: vc[0], Y: vc[1]}
+		witness.Commitment = edwards.Point{X: c[0], Y: c[1]}
+
+		circuit := pedersenCircuit{id: id}
+		assert.ProverSucceeded(&circuit, &witness, test.WithCurves(snarkCurve), test.WithBackends(backend.GROTH16))
+
+		wrong := witness
+		wrong.Value = new(big.Int).Add(value, big.NewInt(1))
+		assert.ProverFailed(&circuit, &wrong, test.WithCurves(snarkCurve), test.WithBackends(backend.GROTH16))
+	}
+}
+
+func TestParams(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	// the generators are deterministic, distinct, and in the prime subgroup
+	p1, err := NewParams(twistededwards.BLS12_381_BANDERSNATCH, 4)
+	assert.NoError(err)
+	p2, err := NewParams(twistededwards.BLS12_381_BANDERSNATCH, 4)
+	assert.NoError(err)
+	other, err := NewParams(twistededwards.BLS12_381, 4)
+	assert.NoError(err)
+
+	generators := append(p1.Generators, p1.Blinding)
+	for i, g := range generators {
+		assert.True(isIdentity(p1.ops.scalarMul(g, p1.curve.Order)), "generator %d is not in the prime subgroup", i)
+		for j := 0; j < i; j++ {
+			assert.False(g[0].Cmp(generators[j][0]) == 0 && g[1].Cmp(generators[j][1]) == 0, "generators %d and %d are equal", i, j)
+		}
+		if i < len(p2.Generators) {
+			assert.Equal(0, g[0].Cmp(p2.Generators[i][0]))
+			assert.Equal(0, g[1].Cmp(p2.Generators[i][1]))
+			assert.NotEqual(0, g[1].Cmp(other.Generators[i][1]), "curves sharing a field have distinct generators")
+		}
+	}
+
+	// not enough generators
+	_, err = p1.Hash(big.NewInt(1), big.NewInt(2), big.NewInt(3), big.NewInt(4))
+	assert.Error(err)
+	_, err = p1.Commit(make([]*big.Int, 5), big.NewInt(1))
+	assert.Error(err)
+}
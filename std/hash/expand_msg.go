@@ -0,0 +1,129 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hash
+
+import (
+	gosha256 "crypto/sha256"
+	"errors"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/sha256"
+)
+
+// ExpandMsgXmd returns lenInBytes pseudo-random bytes from the bytes of msg, with the domain
+// separation tag dst. It is expand_message_xmd of RFC 9380 (section 5.3.1) with SHA-256, as
+// gnark-crypto's ecc.ExpandMsgXmd.
+//
+// The elements of msg are bytes, which is constrained. The first block of b₀ (the zero pad) is
+// constant, so it is compressed for free; each of the other blocks costs about 26000 constraints
+// in R1CS. Each bᵢ is 1 block for a domain separation tag of at most 21 bytes, 2 blocks otherwise.
+func ExpandMsgXmd(api frontend.API, msg []frontend.Variable, dst []byte, lenInBytes int) ([]frontend.Variable, error) {
+	if err := checkDST(dst); err != nil {
+		return nil, err
+	}
+	ell := (lenInBytes + sha256.Size - 1) / sha256.Size
+	if lenInBytes <= 0 || ell > 255 {
+		return nil, errors.New("invalid lenInBytes")
+	}
+
+	// DST_prime = DST || I2OSP(len(DST), 1)
+	dstPrime := make([]frontend.Variable, 0, len(dst)+1)
+	for _, b := range dst {
+		dstPrime = append(dstPrime, b)
+	}
+	dstPrime = append(dstPrime, len(dst))
+
+	// b₀ = H(Z_pad || msg || I2OSP(len_in_bytes, 2) || I2OSP(0, 1) || DST_prime)
+	data := make([]frontend.Variable, sha256.BlockSize, sha256.BlockSize+len(msg)+3+len(dstPrime))
+	for i := range data {
+		data[i] = 0
+	}
+	data = append(data, msg...)
+	data = append(data, lenInBytes>>8, lenInBytes&0xff, 0)
+	data = append(data, dstPrime...)
+	b0 := sha256.Sum(api, data...)
+
+	// b₁ = H(b₀ || I2OSP(1, 1) || DST_prime)
+	// bᵢ = H(strxor(b₀, bᵢ₋₁) || I2OSP(i, 1) || DST_prime)
+	res := make([]frontend.Variable, 0, ell*sha256.Size)
+	b := b0
+	for i := 1; i <= ell; i++ {
+		data = append(data[:0], b...)
+		data = append(data, i)
+		data = append(data, dstPrime...)
+		b = sha256.Sum(api, data...)
+		res = append(res, b...)
+		if i < ell {
+			b = xorBytes(api, b0, b)
+		}
+	}
+	return res[:lenInBytes], nil
+}
+
+// xorBytes returns the bytes a[i] ⊕ b[i]
+func xorBytes(api frontend.API, a, b []frontend.Variable) []frontend.Variable {
+	res := make([]frontend.Variable, len(a))
+	for i := range a {
+		aBits := api.ToBinary(a[i], 8)
+		bBits := api.ToBinary(b[i], 8)
+		for j := range aBits {
+			aBits[j] = api.Xor(aBits[j], bBits[j])
+		}
+		res[i] = api.FromBinary(aBits...)
+	}
+	return res
+}
+
+// NativeExpandMsgXmd is ExpandMsgXmd out of a circuit. It is gnark-crypto's ecc.ExpandMsgXmd, which
+// also accepts lengths which are not multiples of the size of SHA-256 digests: the last block is
+// truncated.
+func NativeExpandMsgXmd(msg, dst []byte, lenInBytes int) ([]byte, error) {
+	if err := checkDST(dst); err != nil {
+		return nil, err
+	}
+	ell := (lenInBytes + gosha256.Size - 1) / gosha256.Size
+	if lenInBytes <= 0 || ell > 255 {
+		return nil, errors.New("invalid lenInBytes")
+	}
+	dstPrime := append(append([]byte{}, dst...), byte(len(dst)))
+
+	h := gosha256.New()
+	h.Write(make([]byte, gosha256.BlockSize))
+	h.Write(msg)
+	h.Write([]byte{byte(lenInBytes >> 8), byte(lenInBytes), 0})
+	h.Write(dstPrime)
+	b0 := h.Sum(nil)
+
+	res := make([]byte, 0, ell*gosha256.Size)
+	b := b0
+	for i := 1; i <= ell; i++ {
+		h.Reset()
+		h.Write(b)
+		h.Write([]byte{byte(i)})
+		h.Write(dstPrime)
+		b = h.Sum(nil)
+		res = append(res, b...)
+		if i < ell {
+			x := make([]byte, len(b))
+			for j := range x {
+				x[j] = b0[j] ^ b[j]
+			}
+			b = x
+		}
+	}
+	return res[:lenInBytes], nil
+}
@@ -0,0 +1,73 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mimc
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/hash"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/frontend"
+)
+
+// nativeHashes are the MiMC implementations (gnark-crypto) matching the gadget, by curve
+var nativeHashes = map[ecc.ID]hash.Hash{
+	ecc.BN254:     hash.MIMC_BN254,
+	ecc.BLS12_381: hash.MIMC_BLS12_381,
+	ecc.BLS12_377: hash.MIMC_BLS12_377,
+	ecc.BW6_761:   hash.MIMC_BW6_761,
+	ecc.BW6_633:   hash.MIMC_BW6_633,
+	ecc.BLS24_315: hash.MIMC_BLS24_315,
+}
+
+// NewPublicInputsHasher returns a MiMC gadget to compress the public inputs of a circuit.
+//
+// It is meant to be used with frontend.WithPublicInputsHash:
+//
+// 		ccs, err := frontend.Compile(ecc.BN254, r1cs.NewBuilder, &circuit, frontend.WithPublicInputsHash(mimc.NewPublicInputsHasher))
+//
+// The matching native hash of a public witness is computed by HashPublicWitness.
+func NewPublicInputsHasher(api frontend.API) (frontend.PublicInputsHasher, error) {
+	h, err := NewMiMC(api)
+	if err != nil {
+		return nil, err
+	}
+	return &h, nil
+}
+
+// HashPublicWitness returns the MiMC hash of the public witness of the original circuit,
+// as constrained in a circuit compiled with frontend.WithPublicInputsHash(NewPublicInputsHasher).
+func HashPublicWitness(publicWitness *witness.Witness) (*big.Int, error) {
+	h, ok := nativeHashes[publicWitness.CurveID]
+	if !ok {
+		return nil, errors.New("unknown curve id")
+	}
+	data, err := publicWitness.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	// the binary witness is [uint32(nbElements) | elements], each element being
+	// encoded as a big-endian byte array of size fr.Bytes, which is the block size of MiMC
+	hasher := h.New()
+	if _, err := hasher.Write(data[4:]); err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(hasher.Sum(nil)), nil
+}
@@ -0,0 +1,149 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mimc
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/test"
+	"github.com/stretchr/testify/require"
+)
+
+type publicInputsCircuit struct {
+	C frontend.Variable
+	A frontend.Variable `gnark:",public"`
+	D struct {
+		E frontend.Variable
+		F frontend.Variable `gnark:",public"`
+	}
+}
+
+func (circuit *publicInputsCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Mul(circuit.A, circuit.C), circuit.D.F)
+	api.AssertIsEqual(api.Add(circuit.C, circuit.D.E), 10)
+	return nil
+}
+
+func TestPublicInputsHash(t *testing.T) {
+	assert := require.New(t)
+
+	var assignment publicInputsCircuit
+	assignment.A = 3
+	assignment.C = 4
+	assignment.D.E = 6
+	assignment.D.F = 12
+
+	fullWitness, err := frontend.NewWitness(&assignment, ecc.BN254)
+	assert.NoError(err)
+	publicWitness, err := fullWitness.Public()
+	assert.NoError(err)
+
+	h, err := HashPublicWitness(publicWitness)
+	assert.NoError(err)
+
+	full, public, err := frontend.PublicInputsHashWitness(fullWitness, h)
+	assert.NoError(err)
+	assert.Equal(1, public.Vector.Len())
+
+	wrongFull, _, err := frontend.PublicInputsHashWitness(fullWitness, new(big.Int).Add(h, big.NewInt(1)))
+	assert.NoError(err)
+
+	// groth16
+	{
+		ccs, err := frontend.Compile(ecc.BN254, r1cs.NewBuilder, &publicInputsCircuit{}, frontend.WithPublicInputsHash(NewPublicInputsHasher))
+		assert.NoError(err)
+		_, _, nbPublic := ccs.GetNbVariables()
+		assert.Equal(2, nbPublic, "one wire and public inputs hash")
+
+		assert.NoError(ccs.IsSolved(full))
+		assert.Error(ccs.IsSolved(wrongFull))
+
+		pk, vk, err := groth16.Setup(ccs)
+		assert.NoError(err)
+		proof, err := groth16.Prove(ccs, pk, full)
+		assert.NoError(err)
+		assert.NoError(groth16.Verify(proof, vk, public))
+	}
+
+	// plonk
+	{
+		ccs, err := frontend.Compile(ecc.BN254, scs.NewBuilder, &publicInputsCircuit{}, frontend.WithPublicInputsHash(NewPublicInputsHasher))
+		assert.NoError(err)
+		_, _, nbPublic := ccs.GetNbVariables()
+		assert.Equal(1, nbPublic)
+
+		assert.NoError(ccs.IsSolved(full))
+		assert.Error(ccs.IsSolved(wrongFull))
+
+		srs, err := test.NewKZGSRS(ccs)
+		assert.NoError(err)
+		pk, vk, err := plonk.Setup(ccs, srs)
+		assert.NoError(err)
+		proof, err := plonk.Prove(ccs, pk, full)
+		assert.NoError(err)
+		assert.NoError(plonk.Verify(proof, vk, public))
+	}
+}
+
+type publicInputsOutputCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",output"`
+}
+
+func (circuit *publicInputsOutputCircuit) Define(api frontend.API) error {
+	circuit.Y = api.Mul(circuit.X, circuit.X)
+	return nil
+}
+
+func TestPublicInputsHashOutputs(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254, r1cs.NewBuilder, &publicInputsOutputCircuit{}, frontend.WithPublicInputsHash(NewPublicInputsHasher))
+	assert.NoError(err)
+
+	// the solver can't compute the outputs, since the public inputs hash depends on them
+	_, _, err = frontend.CompleteWitness(ccs, &publicInputsOutputCircuit{X: 3})
+	assert.Error(err)
+
+	// assigned outputs are hashed as any public input
+	fullWitness, err := frontend.NewWitness(&publicInputsOutputCircuit{X: 3, Y: 9}, ecc.BN254)
+	assert.NoError(err)
+	publicWitness, err := fullWitness.Public()
+	assert.NoError(err)
+	h, err := HashPublicWitness(publicWitness)
+	assert.NoError(err)
+	full, _, err := frontend.PublicInputsHashWitness(fullWitness, h)
+	assert.NoError(err)
+	assert.NoError(ccs.IsSolved(full))
+
+	wrongWitness, err := frontend.NewWitness(&publicInputsOutputCircuit{X: 3, Y: 10}, ecc.BN254)
+	assert.NoError(err)
+	wrongPublic, err := wrongWitness.Public()
+	assert.NoError(err)
+	h, err = HashPublicWitness(wrongPublic)
+	assert.NoError(err)
+	wrongFull, _, err := frontend.PublicInputsHashWitness(wrongWitness, h)
+	assert.NoError(err)
+	assert.Error(ccs.IsSolved(wrongFull))
+}
@@ -0,0 +1,225 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package sha256 provides a ZKP-circuit function to compute SHA-256 digests (FIPS 180-4) of byte
+// strings, as crypto/sha256.
+//
+// The bytes are decomposed in bits, and the compression function works on 32-bit words of bits:
+// the boolean functions are computed bit by bit, and the additions modulo 2³² on packed words, which
+// are decomposed again. Constant bytes (e.g. the padding, or a domain separation tag) cost no
+// constraint until they are mixed with variables: a block made of constants is compressed for free.
+package sha256
+
+import (
+	mbits "math/bits"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/bits"
+)
+
+// Size is the size of a SHA-256 digest, in bytes
+const Size = 32
+
+// BlockSize is the size of the blocks of SHA-256, in bytes
+const BlockSize = 64
+
+// word is a 32-bit word, as little-endian bits
+type word [32]frontend.Variable
+
+var iv = [8]uint32{
+	0x6a09e667, 0xbb67ae85, 0x3c6ef372, 0xa54ff53a, 0x510e527f, 0x9b05688c, 0x1f83d9ab, 0x5be0cd19,
+}
+
+var k = [64]uint32{
+	0x428a2f98, 0x71374491, 0xb5c0fbcf, 0xe9b5dba5, 0x3956c25b, 0x59f111f1, 0x923f82a4, 0xab1c5ed5,
+	0xd807aa98, 0x12835b01, 0x243185be, 0x550c7dc3, 0x72be5d74, 0x80deb1fe, 0x9bdc06a7, 0xc19bf174,
+	0xe49b69c1, 0xefbe4786, 0x0fc19dc6, 0x240ca1cc, 0x2de92c6f, 0x4a7484aa, 0x5cb0a9dc, 0x76f988da,
+	0x983e5152, 0xa831c66d, 0xb00327c8, 0xbf597fc7, 0xc6e00bf3, 0xd5a79147, 0x06ca6351, 0x14292967,
+	0x27b70a85, 0x2e1b2138, 0x4d2c6dfc, 0x53380d13, 0x650a7354, 0x766a0abb, 0x81c2c92e, 0x92722c85,
+	0xa2bfe8a1, 0xa81a664b, 0xc24b8b70, 0xc76c51a3, 0xd192e819, 0xd6990624, 0xf40e3585, 0x106aa070,
+	0x19a4c116, 0x1e376c08, 0x2748774c, 0x34b0bcb5, 0x391c0cb3, 0x4ed8aa4a, 0x5b9cca4f, 0x682e6ff3,
+	0x748f82ee, 0x78a5636f, 0x84c87814, 0x8cc70208, 0x90befffa, 0xa4506ceb, 0xbef9a3f7, 0xc67178f2,
+}
+
+// Sum returns the SHA-256 digest of data, as Size bytes.
+//
+// The elements of data are bytes: each of them is decomposed in 8 bits, which constrains it to be
+// less than 256. The length of data is fixed when the circuit is compiled.
+func Sum(api frontend.API, data ...frontend.Variable) []frontend.Variable {
+	// padding: 0x80, zeros, and the length in bits on 8 bytes
+	padded := make([]frontend.Variable, len(data), len(data)+BlockSize+9)
+	copy(padded, data)
+	padded = append(padded, 0x80)
+	for len(padded)%BlockSize != BlockSize-8 {
+		padded = append(padded, 0)
+	}
+	length := uint64(len(data)) * 8
+	for i := 7; i >= 0; i-- {
+		padded = append(padded, (length>>(8*uint(i)))&0xff)
+	}
+
+	var state [8]word
+	for i := range state {
+		state[i] = constantWord(iv[i])
+	}
+	for i := 0; i < len(padded); i += BlockSize {
+		state = compress(api, state, padded[i:i+BlockSize])
+	}
+
+	res := make([]frontend.Variable, 0, Size)
+	for i := range state {
+		for j := 3; j >= 0; j-- {
+			res = append(res, bits.FromBinary(api, state[i][8*j:8*j+8], bits.WithUnconstrainedInputs()))
+		}
+	}
+	return res
+}
+
+// compress returns the state after the compression of the 64 bytes of block
+func compress(api frontend.API, state [8]word, block []frontend.Variable) [8]word {
+	// message schedule: the bytes of the words are big-endian
+	var w [64]word
+	for i := 0; i < 16; i++ {
+		for j := 0; j < 4; j++ {
+			b := api.ToBinary(block[4*i+j], 8)
+			copy(w[i][8*(3-j):8*(4-j)], b)
+		}
+	}
+	for i := 16; i < 64; i++ {
+		s0 := xor3(api, rotr(w[i-15], 7), rotr(w[i-15], 18), shr(w[i-15], 3))
+		s1 := xor3(api, rotr(w[i-2], 17), rotr(w[i-2], 19), shr(w[i-2], 10))
+		w[i] = add(api, 4, pack(api, w[i-16]), pack(api, s0), pack(api, w[i-7]), pack(api, s1))
+	}
+
+	a, b, c, d, e, f, g, h := state[0], state[1], state[2], state[3], state[4], state[5], state[6], state[7]
+	for i := 0; i < 64; i++ {
+		s1 := xor3(api, rotr(e, 6), rotr(e, 11), rotr(e, 25))
+		s0 := xor3(api, rotr(a, 2), rotr(a, 13), rotr(a, 22))
+
+		// T1 = h + Σ1(e) + ch(e, f, g) + K[i] + W[i], T2 = Σ0(a) + maj(a, b, c)
+		t1 := api.Add(pack(api, h), pack(api, s1), pack(api, ch(api, e, f, g)), k[i], pack(api, w[i]))
+		t2 := api.Add(pack(api, s0), pack(api, maj(api, a, b, c)))
+
+		h, g, f = g, f, e
+		e = add(api, 6, pack(api, d), t1)
+		d, c, b = c, b, a
+		a = add(api, 7, t1, t2)
+	}
+
+	for i, v := range []word{a, b, c, d, e, f, g, h} {
+		state[i] = add(api, 2, pack(api, state[i]), pack(api, v))
+	}
+	return state
+}
+
+// add returns modulo 2³² the sum of nbWords packed words, given as sums of words
+func add(api frontend.API, nbWords int, sums ...frontend.Variable) word {
+	sum := frontend.Variable(0)
+	for _, s := range sums {
+		sum = api.Add(sum, s)
+	}
+	// the sum is less than nbWords⋅2³²
+	b := api.ToBinary(sum, 32+mbits.Len(uint(nbWords-1)))
+	var res word
+	copy(res[:], b[:32])
+	return res
+}
+
+// pack returns the integer of the bits of w
+func pack(api frontend.API, w word) frontend.Variable {
+	return bits.FromBinary(api, w[:], bits.WithUnconstrainedInputs())
+}
+
+// ch returns (e ∧ f) ⊕ (¬e ∧ g), that is g + e⋅(f - g) bit by bit
+func ch(api frontend.API, e, f, g word) word {
+	var res word
+	for i := range res {
+		res[i] = api.Add(g[i], api.Mul(e[i], api.Sub(f[i], g[i])))
+	}
+	return res
+}
+
+// maj returns (a ∧ b) ⊕ (a ∧ c) ⊕ (b ∧ c), that is a⋅b + c⋅(a + b - 2⋅a⋅b) bit by bit
+func maj(api frontend.API, a, b, c word) word {
+	var res word
+	for i := range res {
+		ab := api.Mul(a[i], b[i])
+		res[i] = api.Add(ab, api.Mul(c[i], api.Sub(api.Add(a[i], b[i]), api.Mul(ab, 2))))
+	}
+	return res
+}
+
+// xor3 returns a ⊕ b ⊕ c. The constant bits are folded, since api.Xor adds a constraint even for
+// constant inputs: the variables are xored together, and negated at the end if the constants
+// xor to 1. The result is only used in linear combinations, since the negations are linear
+// expressions, which api.Xor doesn't expect as inputs.
+func xor3(api frontend.API, a, b, c word) word {
+	var res word
+	for i := range res {
+		var k uint64
+		var vars []frontend.Variable
+		for _, v := range []frontend.Variable{a[i], b[i], c[i]} {
+			if cv, ok := api.Compiler().ConstantValue(v); ok {
+				k ^= cv.Uint64()
+			} else {
+				vars = append(vars, v)
+			}
+		}
+		if len(vars) == 0 {
+			res[i] = k
+			continue
+		}
+		res[i] = vars[0]
+		for _, v := range vars[1:] {
+			res[i] = api.Xor(res[i], v)
+		}
+		if k == 1 {
+			res[i] = api.Sub(1, res[i])
+		}
+	}
+	return res
+}
+
+// rotr returns the rotation of w by n bits to the right
+func rotr(w word, n int) word {
+	var res word
+	for i := range res {
+		res[i] = w[(i+n)%32]
+	}
+	return res
+}
+
+// shr returns the shift of w by n bits to the right
+func shr(w word, n int) word {
+	var res word
+	for i := range res {
+		if i+n < 32 {
+			res[i] = w[i+n]
+		} else {
+			res[i] = 0
+		}
+	}
+	return res
+}
+
+// constantWord returns the bits of v
+func constantWord(v uint32) word {
+	var res word
+	for i := range res {
+		res[i] = (v >> uint(i)) & 1
+	}
+	return res
+}
@@ -0,0 +1,66 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sha256
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+type sha256Circuit struct {
+	Data   []frontend.Variable
+	Digest [Size]frontend.Variable `gnark:",public"`
+}
+
+func (circuit *sha256Circuit) Define(api frontend.API) error {
+	digest := Sum(api, circuit.Data...)
+	for i := range digest {
+		api.AssertIsEqual(digest[i], circuit.Digest[i])
+	}
+	return nil
+}
+
+func TestSum(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	// the padding fits in the last block of the data (55), or needs another block (56, 64)
+	for _, n := range []int{0, 3, 55, 56, 64} {
+		data := make([]byte, n)
+		if _, err := rand.Read(data); err != nil {
+			t.Fatal(err)
+		}
+		digest := sha256.Sum256(data)
+
+		circuit := sha256Circuit{Data: make([]frontend.Variable, n)}
+		witness := sha256Circuit{Data: make([]frontend.Variable, n)}
+		for i := range data {
+			witness.Data[i] = data[i]
+		}
+		for i := range digest {
+			witness.Digest[i] = digest[i]
+		}
+		assert.SolvingSucceeded(&circuit, &witness, test.WithCurves(ecc.BN254))
+
+		witness.Digest[0] = digest[0] ^ 1
+		assert.SolvingFailed(&circuit, &witness, test.WithCurves(ecc.BN254))
+	}
+}
@@ -0,0 +1,125 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hash
+
+import (
+	"errors"
+	gohash "hash"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+)
+
+// dstChunkSize is the size in bytes of the chunks of the domain separation tag absorbed by the
+// hash function, small enough for a chunk to be an element of any of the supported fields.
+const dstChunkSize = 31
+
+// ToField hashes msg to count field elements, with the domain separation tag dst.
+//
+// It is the hash_to_field function of RFC 9380 (section 5), with a custom expand_message
+// (section 5.3.4) built on the field hash h instead of a bit-oriented hash function:
+//
+//	b₀ = h(dst₀, ..., dstₖ, len(dst), len(msg), count, msg...)
+//	uᵢ = h(b₀, i+1)
+//
+// where dst₀, ..., dstₖ are the big-endian 31 bytes chunks of dst. The hash function is reset
+// before each use. NativeToField is the same function out of a circuit.
+//
+// This is not a standard suite of RFC 9380: the field elements differ from the ones of
+// expand_message_xmd or expand_message_xof, hence from the ones of other hash-to-curve
+// implementations. It suits the curves without a standard counterpart, e.g. the twisted Edwards
+// curves; ExpandMsgXmd is the standard expand_message, which is much more expensive in a circuit.
+func ToField(h Hash, dst []byte, count int, msg ...frontend.Variable) ([]frontend.Variable, error) {
+	if err := checkDST(dst); err != nil {
+		return nil, err
+	}
+	h.Reset()
+	for _, c := range dstChunks(dst) {
+		h.Write(c)
+	}
+	h.Write(len(dst), len(msg), count)
+	h.Write(msg...)
+	b0 := h.Sum()
+
+	res := make([]frontend.Variable, count)
+	for i := range res {
+		h.Reset()
+		h.Write(b0, i+1)
+		res[i] = h.Sum()
+	}
+	return res, nil
+}
+
+// NativeToField is ToField out of a circuit: h is the native counterpart of the hash function of
+// the circuit, e.g. gnark-crypto's MiMC, to which the field elements are written as big-endian
+// slices of h.BlockSize() bytes. The elements of msg must be non-negative and fit in this size.
+func NativeToField(h gohash.Hash, dst []byte, count int, msg ...*big.Int) ([]*big.Int, error) {
+	if err := checkDST(dst); err != nil {
+		return nil, err
+	}
+	write := func(v *big.Int) error {
+		if v.Sign() < 0 || v.BitLen() > 8*h.BlockSize() {
+			return errors.New("message element out of range")
+		}
+		_, err := h.Write(v.FillBytes(make([]byte, h.BlockSize())))
+		return err
+	}
+
+	h.Reset()
+	header := dstChunks(dst)
+	header = append(header, big.NewInt(int64(len(dst))), big.NewInt(int64(len(msg))), big.NewInt(int64(count)))
+	for _, v := range append(header, msg...) {
+		if err := write(v); err != nil {
+			return nil, err
+		}
+	}
+	b0 := new(big.Int).SetBytes(h.Sum(nil))
+
+	res := make([]*big.Int, count)
+	for i := range res {
+		h.Reset()
+		if err := write(b0); err != nil {
+			return nil, err
+		}
+		if err := write(big.NewInt(int64(i + 1))); err != nil {
+			return nil, err
+		}
+		res[i] = new(big.Int).SetBytes(h.Sum(nil))
+	}
+	return res, nil
+}
+
+// checkDST checks the length of the domain separation tag (RFC 9380, section 5.3.3)
+func checkDST(dst []byte) error {
+	if len(dst) == 0 || len(dst) > 255 {
+		return errors.New("invalid domain separation tag length")
+	}
+	return nil
+}
+
+// dstChunks splits dst into big-endian integers of dstChunkSize bytes
+func dstChunks(dst []byte) []*big.Int {
+	var res []*big.Int
+	for i := 0; i < len(dst); i += dstChunkSize {
+		end := i + dstChunkSize
+		if end > len(dst) {
+			end = len(dst)
+		}
+		res = append(res, new(big.Int).SetBytes(dst[i:end]))
+	}
+	return res
+}
@@ -0,0 +1,123 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hash_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	gohash "github.com/consensys/gnark-crypto/hash"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/test"
+)
+
+// a tag longer than a chunk
+var testDST = []byte("gnark-std-hash-to-field-test-with-a-long-domain-separation-tag")
+
+type toFieldCircuit struct {
+	Msg [2]frontend.Variable
+	U   [3]frontend.Variable `gnark:",public"`
+}
+
+func (circuit *toFieldCircuit) Define(api frontend.API) error {
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	u, err := hash.ToField(&h, testDST, len(circuit.U), circuit.Msg[:]...)
+	if err != nil {
+		return err
+	}
+	for i := range u {
+		api.AssertIsEqual(u[i], circuit.U[i])
+	}
+	return nil
+}
+
+func TestToField(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	msg := []*big.Int{big.NewInt(42), big.NewInt(43)}
+	u, err := hash.NativeToField(gohash.MIMC_BN254.New(), testDST, 3, msg...)
+	assert.NoError(err)
+
+	var witness toFieldCircuit
+	witness.Msg = [2]frontend.Variable{msg[0], msg[1]}
+	witness.U = [3]frontend.Variable{u[0], u[1], u[2]}
+	assert.SolvingSucceeded(&toFieldCircuit{}, &witness, test.WithCurves(ecc.BN254))
+
+	witness.Msg[1] = big.NewInt(44)
+	assert.SolvingFailed(&toFieldCircuit{}, &witness, test.WithCurves(ecc.BN254))
+
+	_, err = hash.NativeToField(gohash.MIMC_BN254.New(), nil, 1, msg...)
+	assert.Error(err)
+
+	// the elements must be written without loss
+	_, err = hash.NativeToField(gohash.MIMC_BN254.New(), testDST, 1, big.NewInt(-1))
+	assert.Error(err)
+	_, err = hash.NativeToField(gohash.MIMC_BN254.New(), testDST, 1, new(big.Int).Lsh(big.NewInt(1), 256))
+	assert.Error(err)
+}
+
+type expandMsgXmdCircuit struct {
+	Msg    []frontend.Variable
+	Output []frontend.Variable `gnark:",public"`
+}
+
+func (circuit *expandMsgXmdCircuit) Define(api frontend.API) error {
+	res, err := hash.ExpandMsgXmd(api, circuit.Msg, testDST, len(circuit.Output))
+	if err != nil {
+		return err
+	}
+	for i := range res {
+		api.AssertIsEqual(res[i], circuit.Output[i])
+	}
+	return nil
+}
+
+func TestExpandMsgXmd(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	msg := []byte("abc")
+
+	// gnark-crypto supports only multiples of 32 bytes
+	expected, err := ecc.ExpandMsgXmd(msg, testDST, 96)
+	assert.NoError(err)
+	res, err := hash.NativeExpandMsgXmd(msg, testDST, 96)
+	assert.NoError(err)
+	assert.Equal(expected, res)
+
+	// 2 blocks bᵢ, the last one being truncated
+	res, err = hash.NativeExpandMsgXmd(msg, testDST, 56)
+	assert.NoError(err)
+
+	circuit := expandMsgXmdCircuit{Msg: make([]frontend.Variable, len(msg)), Output: make([]frontend.Variable, len(res))}
+	witness := expandMsgXmdCircuit{Msg: make([]frontend.Variable, len(msg)), Output: make([]frontend.Variable, len(res))}
+	for i := range msg {
+		witness.Msg[i] = msg[i]
+	}
+	for i := range res {
+		witness.Output[i] = res[i]
+	}
+	assert.SolvingSucceeded(&circuit, &witness, test.WithCurves(ecc.BN254))
+
+	witness.Msg[0] = msg[0] ^ 1
+	assert.SolvingFailed(&circuit, &witness, test.WithCurves(ecc.BN254))
+}
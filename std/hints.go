@@ -6,7 +6,9 @@ import (
 	"github.com/consensys/gnark/backend/hint"
 	"github.com/consensys/gnark/std/algebra/sw_bls12377"
 	"github.com/consensys/gnark/std/algebra/sw_bls24315"
+	"github.com/consensys/gnark/std/algebra/twistededwards"
 	"github.com/consensys/gnark/std/math/bits"
+	"github.com/consensys/gnark/std/signature/eddsa"
 )
 
 var registerOnce sync.Once
@@ -27,4 +29,17 @@ func registerHints() {
 	hint.Register(bits.NNAF)
 	hint.Register(bits.IthBit)
 	hint.Register(bits.NBits)
+	hint.Register(eddsa.LinearCombinationModOrder)
+	hint.Register(sw_bls12377.ResidueWitnessHint)
+	hint.Register(sw_bls24315.ResidueWitnessHint)
+	hint.Register(sw_bls12377.IsSquareHint)
+	hint.Register(sw_bls12377.SqrtHint)
+	hint.Register(sw_bls12377.Sign0Hint)
+	hint.Register(sw_bls12377.IsSquareE2Hint)
+	hint.Register(sw_bls24315.IsSquareHint)
+	hint.Register(sw_bls24315.SqrtHint)
+	hint.Register(sw_bls24315.Sign0Hint)
+	hint.Register(twistededwards.IsSquareHint)
+	hint.Register(twistededwards.SqrtHint)
+	hint.Register(twistededwards.Sgn0Hint)
 }
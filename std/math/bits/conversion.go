@@ -51,6 +51,7 @@ type baseConversionConfig struct {
 	NbDigits             int
 	UnconstrainedOutputs bool
 	UnconstrainedInputs  bool
+	ModulusCheck         bool
 }
 
 // BaseConversionOption configures the behaviour of scalar decomposition.
@@ -91,3 +92,14 @@ func WithUnconstrainedInputs() BaseConversionOption {
 		return nil
 	}
 }
+
+// WithModulusCheck sets the binary conversion API to constrain the output bits to represent an
+// integer smaller than the modulus of the field. Without it, v + modulus may be decomposed instead
+// of v when it fits in the number of digits; with it, the decomposition is unique.
+// It is not implemented in base 3.
+func WithModulusCheck() BaseConversionOption {
+	return func(opt *baseConversionConfig) error {
+		opt.ModulusCheck = true
+		return nil
+	}
+}
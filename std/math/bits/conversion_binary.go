@@ -93,9 +93,39 @@ func toBinary(api frontend.API, v frontend.Variable, opts ...BaseConversionOptio
 	// record the constraint Σ (2**i * b[i]) == a
 	api.AssertIsEqual(Σbi, v)
 
+	if cfg.ModulusCheck {
+		bound := new(big.Int).Sub(api.Compiler().Curve().Info().Fr.Modulus(), big.NewInt(1))
+		assertIsLessOrEqualBits(api, bits, bound)
+	}
+
 	return bits
 }
 
+// assertIsLessOrEqualBits asserts that the integer of given bits (little-endian) is less or equal
+// to bound. The bits must be boolean.
+func assertIsLessOrEqualBits(api frontend.API, bits []frontend.Variable, bound *big.Int) {
+	if len(bits) < bound.BitLen() {
+		return
+	}
+
+	// t trailing ones in the bound: they constrain nothing
+	t := 0
+	for bound.Bit(t) == 1 {
+		t++
+	}
+
+	// p == 1 ⇔ bits[j] == bound[j] for all j > i
+	var p frontend.Variable = 1
+	for i := len(bits) - 1; i >= t; i-- {
+		if bound.Bit(i) == 0 {
+			// the bits above are the ones of the bound, so bits[i] must be 0
+			api.AssertIsEqual(api.Mul(p, bits[i]), 0)
+		} else {
+			p = api.Mul(p, bits[i])
+		}
+	}
+}
+
 // IthBit returns the i-tb bit the input. The function expects exactly two
 // integer inputs i and n, takes the little-endian bit representation of n and
 // returns its i-th bit.
@@ -67,6 +67,9 @@ func toTernary(api frontend.API, v frontend.Variable, opts ...BaseConversionOpti
 			panic(err)
 		}
 	}
+	if cfg.ModulusCheck {
+		panic("modulus check not implemented in base 3")
+	}
 
 	// if a is a constant, work with the big int value.
 	if c, ok := api.Compiler().ConstantValue(v); ok {
@@ -1,8 +1,12 @@
 package bits_test
 
 import (
+	"math/big"
 	"testing"
 
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/std/math/bits"
 	"github.com/consensys/gnark/test"
@@ -57,3 +61,49 @@ func TestToTernary(t *testing.T) {
 	assert := test.NewAssert(t)
 	assert.ProverSucceeded(&toTernaryCircuit{}, &toTernaryCircuit{A: 5, T0: 2, T1: 1, T2: 0})
 }
+
+type toBinaryModulusCheckCircuit struct {
+	A frontend.Variable
+}
+
+func (c *toBinaryModulusCheckCircuit) Define(api frontend.API) error {
+	b := bits.ToBinary(api, c.A, bits.WithModulusCheck())
+	api.AssertIsEqual(bits.FromBinary(api, b), c.A)
+	return nil
+}
+
+func TestToBinaryModulusCheck(t *testing.T) {
+	assert := test.NewAssert(t)
+	assert.ProverSucceeded(&toBinaryModulusCheckCircuit{}, &toBinaryModulusCheckCircuit{A: 0})
+	assert.ProverSucceeded(&toBinaryModulusCheckCircuit{}, &toBinaryModulusCheckCircuit{A: -1})
+}
+
+type lessOrEqualBitsCircuit struct {
+	B [5]frontend.Variable
+}
+
+func (c *lessOrEqualBitsCircuit) Define(api frontend.API) error {
+	for i := range c.B {
+		api.AssertIsBoolean(c.B[i])
+	}
+	bits.AssertIsLessOrEqualBits(api, c.B[:], big.NewInt(0b10110))
+	return nil
+}
+
+func TestAssertIsLessOrEqualBits(t *testing.T) {
+	assert := test.NewAssert(t)
+	for v := 0; v < 1<<5; v++ {
+		var witness lessOrEqualBitsCircuit
+		for i := range witness.B {
+			witness.B[i] = (v >> i) & 1
+		}
+		for _, b := range backend.Implemented() {
+			err := test.IsSolved(&lessOrEqualBitsCircuit{}, &witness, ecc.BN254, b)
+			if v <= 0b10110 {
+				assert.NoError(err, "%d", v)
+			} else {
+				assert.Error(err, "%d", v)
+			}
+		}
+	}
+}
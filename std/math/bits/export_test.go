@@ -0,0 +1,4 @@
+package bits
+
+// AssertIsLessOrEqualBits is exported for the tests of the modulus check
+var AssertIsLessOrEqualBits = assertIsLessOrEqualBits
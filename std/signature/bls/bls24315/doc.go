@@ -0,0 +1,35 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package bls24315 provides ZKP-circuit functions to verify BLS signatures over BLS24-315.
+//
+// The circuits are defined over BW6-633, whose scalar field is the base field of BLS24-315,
+// so that the pairing is computed natively (see std/algebra/sw_bls24315).
+//
+// Signatures and hashed messages are in G1 and public keys are in G2 (the "minimal signature size"
+// variant, as used for validator sets). Messages are bytes, hashed to G1 with HashToG1:
+// sw_bls24315.HashToG1, that is hash_to_curve of RFC 9380 with expand_message_xmd and SHA-256, with
+// the domain separation tag DST. NativeHashToG1 computes the same hash out of a circuit. The length
+// of the messages is fixed when the circuit is compiled, and SHA-256 is the main cost of the
+// verification of short messages.
+//
+// The signature with the secret key sk is σ = [sk]H(m), and the public key is [sk]g₂.
+// The verification functions check that the signature and the public keys are in the prime
+// order subgroups, and that e(σ, -g₂)⋅∏e(H(mᵢ), pkᵢ) = 1.
+//
+// The code of the package is generated from internal/generator/backend/template/signature/bls,
+// shared with the other curves.
+package bls24315
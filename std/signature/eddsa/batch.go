@@ -0,0 +1,303 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eddsa
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/hint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/twistededwards"
+	fiatshamir "github.com/consensys/gnark/std/fiat-shamir"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/math/bits"
+)
+
+// nbRandomizerBits is the size of the random coefficients of the batch verification
+const nbRandomizerBits = 64
+
+func init() {
+	hint.Register(LinearCombinationModOrder)
+}
+
+// BatchVerify verifies n eddsa signatures using MiMC hash function, with a random linear combination
+// of the verification equations:
+//
+// 		Σ zᵢ⋅([Sᵢ]G - Rᵢ - [H(Rᵢ,Aᵢ,Mᵢ)]Aᵢ) == 0
+//
+// z₀ = 1 and the other 64-bit coefficients zᵢ are derived from a single Fiat-Shamir challenge c
+// binding the hashes H(Rᵢ,Aᵢ,Mᵢ) and the scalars Sᵢ: they are the 64-bit chunks of c, H(c), H(H(c))...
+// The scalar multiplications of Rᵢ and Aᵢ share their doublings, and the multiplications of G collapse
+// into a single fixed-base scalar multiplication. Hence each signature costs less than a call to Verify,
+// at the price of a constant overhead (see the eddsa snippets in internal/stats). A single signature
+// is checked with Verify.
+//
+// The hash function is reset before computing each H(Rᵢ,Aᵢ,Mᵢ), the challenge and each chunk source.
+func BatchVerify(curve twistededwards.Curve, sigs []Signature, msgs []frontend.Variable, pubKeys []PublicKey, hash hash.Hash) error {
+	n := len(sigs)
+	if len(msgs) != n || len(pubKeys) != n {
+		return errors.New("number of signatures, messages and public keys mismatch")
+	}
+	if n == 0 {
+		return nil
+	}
+	if n == 1 {
+		hash.Reset()
+		return Verify(curve, sigs[0], msgs[0], pubKeys[0], hash)
+	}
+	api := curve.API()
+
+	// compute H(Rᵢ, Aᵢ, Mᵢ)
+	hRAM := make([]frontend.Variable, n)
+	for i := 0; i < n; i++ {
+		hash.Reset()
+		hash.Write(sigs[i].R.X)
+		hash.Write(sigs[i].R.Y)
+		hash.Write(pubKeys[i].A.X)
+		hash.Write(pubKeys[i].A.Y)
+		hash.Write(msgs[i])
+		hRAM[i] = hash.Sum()
+	}
+
+	// derive the coefficients z₁ ... zₙ₋₁ from a single challenge c: each of c, H(c), H(H(c))...
+	// provides several of them
+	nbBits := api.Compiler().Curve().Info().Fr.Bits
+	transcript := fiatshamir.NewTranscript(api, hash, "eddsa-batch")
+	bindings := make([]frontend.Variable, 0, 2*n)
+	for i := 0; i < n; i++ {
+		bindings = append(bindings, hRAM[i], sigs[i].S)
+	}
+	if err := transcript.Bind("eddsa-batch", bindings); err != nil {
+		return err
+	}
+	c, err := transcript.ComputeChallenge("eddsa-batch")
+	if err != nil {
+		return err
+	}
+	perChallenge := (nbBits - 2) / nbRandomizerBits
+	zBits := make([][]frontend.Variable, n)
+	for i := 1; i < n; i += perChallenge {
+		if i > 1 {
+			hash.Reset()
+			hash.Write(c)
+			c = hash.Sum()
+		}
+		cBits := api.ToBinary(c)
+		for k := 0; k < perChallenge && i+k < n; k++ {
+			zBits[i+k] = cBits[k*nbRandomizerBits : (k+1)*nbRandomizerBits]
+		}
+	}
+
+	// [Σ zᵢSᵢ mod l]G - Σ [zᵢ]Rᵢ - Σ [zᵢ⋅H(Rᵢ,Aᵢ,Mᵢ) mod l]Aᵢ: the reductions modulo the order l
+	// of the curve are computed by a hint and checked over the integers. The multiplications of
+	// the points Rᵢ and Aᵢ are computed by a single multi scalar multiplication.
+	zs := make([]frontend.Variable, n)
+	ss := make([]frontend.Variable, n)
+	sLimbs := make([][2]frontend.Variable, n)
+	points := make([]twistededwards.Point, 0, 2*n)
+	scalars := make([][]frontend.Variable, 0, 2*n)
+	for i := 0; i < n; i++ {
+		ss[i] = sigs[i].S
+		if _, sLimbs[i], err = toLimbs(api, sigs[i].S, nbBits); err != nil {
+			return err
+		}
+		hBits, hLimbs, err := toLimbs(api, hRAM[i], nbBits)
+		if err != nil {
+			return err
+		}
+		if i == 0 {
+			zs[i] = 1
+			points = append(points, curve.Neg(pubKeys[i].A))
+			scalars = append(scalars, hBits)
+			continue
+		}
+		zs[i] = api.FromBinary(zBits[i]...)
+		wBits, err := linearCombinationModOrder(api, zs[i:i+1], hRAM[i:i+1], [][2]frontend.Variable{hLimbs}, nbBits, curve.Params().Order)
+		if err != nil {
+			return err
+		}
+		points = append(points, curve.Neg(sigs[i].R), curve.Neg(pubKeys[i].A))
+		scalars = append(scalars, zBits[i], wBits)
+	}
+	s, err := linearCombinationModOrder(api, zs, ss, sLimbs, nbBits, curve.Params().Order)
+	if err != nil {
+		return err
+	}
+	base := twistededwards.Point{
+		X: curve.Params().Base[0],
+		Y: curve.Params().Base[1],
+	}
+	Q := curve.FixedBaseScalarMul(base, api.FromBinary(s...))
+	Q = curve.Add(Q, curve.Neg(sigs[0].R))
+	Q = curve.Add(Q, multiScalarMulBits(curve, points, scalars))
+	curve.AssertIsOnCurve(Q)
+
+	// [cofactor]*(lhs-rhs)
+	Q, err = clearCofactor(curve, Q)
+	if err != nil {
+		return err
+	}
+
+	api.AssertIsEqual(Q.X, 0)
+	api.AssertIsEqual(Q.Y, 1)
+
+	return nil
+}
+
+// toLimbs returns the nbBits bits of x and its limbs x₀, x₁ such that x = x₀ + x₁⋅2^(nbBits/2),
+// packed from the bits: this costs as many constraints as api.ToBinary, without packing the
+// bits again to get the limbs.
+func toLimbs(api frontend.API, x frontend.Variable, nbBits int) ([]frontend.Variable, [2]frontend.Variable, error) {
+	half := nbBits / 2
+	xBits, err := api.Compiler().NewHint(bits.NBits, nbBits, x)
+	if err != nil {
+		return nil, [2]frontend.Variable{}, err
+	}
+	limbs := [2]frontend.Variable{
+		bits.FromBinary(api, xBits[:half]),
+		bits.FromBinary(api, xBits[half:]),
+	}
+	api.AssertIsEqual(api.Add(limbs[0], api.Mul(limbs[1], new(big.Int).Lsh(big.NewInt(1), uint(half)))), x)
+	return xBits, limbs, nil
+}
+
+// linearCombinationModOrder returns the bits of w = Σ zᵢ⋅xᵢ mod order, where zᵢ < 2^nbRandomizerBits
+// and xLimbs[i] are the limbs of the nbBits-bit xᵢ returned by toLimbs. w is computed by the hint
+// LinearCombinationModOrder, and the circuit checks Σ zᵢ⋅xᵢ = w + q⋅order over the integers, on
+// two limbs so that the products and the sums don't overflow the SNARK field.
+func linearCombinationModOrder(api frontend.API, zs, xs []frontend.Variable, xLimbs [][2]frontend.Variable, nbBits int, order *big.Int) ([]frontend.Variable, error) {
+	half := nbBits / 2
+	nbOrderBits := order.BitLen()
+	nbQBits := nbRandomizerBits + big.NewInt(int64(len(zs))).BitLen() + nbBits - nbOrderBits + 1
+	nbCarryBits := nbQBits + 1 // the carry is in ]-2^nbCarryBits, 2^nbQBits[
+
+	inputs := []frontend.Variable{order, half, nbCarryBits}
+	for i := range zs {
+		inputs = append(inputs, zs[i], xs[i])
+	}
+	res, err := api.Compiler().NewHint(LinearCombinationModOrder, 4, inputs...)
+	if err != nil {
+		return nil, err
+	}
+	w0Bits := api.ToBinary(res[0], half)
+	w1Bits := api.ToBinary(res[1], nbOrderBits-half)
+	q := api.FromBinary(api.ToBinary(res[2], nbQBits)...)
+	carry := api.Sub(api.FromBinary(api.ToBinary(res[3], nbCarryBits+1)...), new(big.Int).Lsh(big.NewInt(1), uint(nbCarryBits)))
+
+	// order = l0 + l1⋅2^half
+	var l0, l1 big.Int
+	l1.Rsh(order, uint(half))
+	l0.Lsh(&l1, uint(half)).Sub(order, &l0)
+
+	var low, high frontend.Variable = 0, 0
+	for i := range zs {
+		low = api.Add(low, api.Mul(zs[i], xLimbs[i][0]))
+		high = api.Add(high, api.Mul(zs[i], xLimbs[i][1]))
+	}
+
+	// Σ zᵢ⋅xᵢ₀ = w0 + q⋅l0 + carry⋅2^half
+	api.AssertIsEqual(low, api.Add(res[0], api.Mul(q, &l0), api.Mul(carry, new(big.Int).Lsh(big.NewInt(1), uint(half)))))
+	// Σ zᵢ⋅xᵢ₁ + carry = w1 + q⋅l1
+	api.AssertIsEqual(api.Add(high, carry), api.Add(res[1], api.Mul(q, &l1)))
+
+	return append(w0Bits, w1Bits...), nil
+}
+
+// LinearCombinationModOrder is a hint which, given order, half, nbCarryBits and pairs zᵢ, xᵢ,
+// returns w0, w1, q and carry + 2^nbCarryBits such that w0 + w1⋅2^half = Σ zᵢ⋅xᵢ mod order,
+// q = (Σ zᵢ⋅xᵢ - w) / order and Σ zᵢ⋅xᵢ₀ = w0 + q⋅l0 + carry⋅2^half, where xᵢ₀ and l0 are
+// the lower half bits of xᵢ and order.
+func LinearCombinationModOrder(_ ecc.ID, inputs []*big.Int, results []*big.Int) error {
+	order := inputs[0]
+	half := uint(inputs[1].Uint64())
+	nbCarryBits := uint(inputs[2].Uint64())
+
+	mask := new(big.Int).Lsh(big.NewInt(1), half)
+	mask.Sub(mask, big.NewInt(1))
+
+	sum, low := new(big.Int), new(big.Int)
+	tmp := new(big.Int)
+	for i := 3; i+1 < len(inputs); i += 2 {
+		z, x := inputs[i], inputs[i+1]
+		sum.Add(sum, tmp.Mul(z, x))
+		low.Add(low, tmp.Mul(z, tmp.And(x, mask)))
+	}
+	q, w := new(big.Int).QuoRem(sum, order, new(big.Int))
+
+	results[0].And(w, mask)
+	results[1].Rsh(w, half)
+	results[2].Set(q)
+
+	// carry = (Σ zᵢ⋅xᵢ₀ - w0 - q⋅l0) / 2^half
+	l0 := new(big.Int).And(order, mask)
+	carry := low.Sub(low, results[0])
+	carry.Sub(carry, l0.Mul(l0, q))
+	carry.Rsh(carry, half)
+	results[3].Lsh(big.NewInt(1), nbCarryBits)
+	results[3].Add(results[3], carry)
+
+	return nil
+}
+
+// multiScalarMulBits computes Σ [sᵢ]Pᵢ where bits[i] are the bits of sᵢ (little endian).
+// The scalars may have different sizes: the doublings are shared, and the short scalars
+// only cost lookups and additions in their windows.
+func multiScalarMulBits(curve twistededwards.Curve, points []twistededwards.Point, bits [][]frontend.Variable) twistededwards.Point {
+	api := curve.API()
+
+	// tables [0, P, 2P, 3P], and bits padded to an even size
+	tables := make([][4]twistededwards.Point, len(points))
+	nbWindows := 0
+	for i := range points {
+		if len(bits[i])%2 == 1 {
+			bits[i] = append(bits[i][:len(bits[i]):len(bits[i])], 0)
+		}
+		if len(bits[i])/2 > nbWindows {
+			nbWindows = len(bits[i]) / 2
+		}
+		tables[i][0] = twistededwards.Point{X: 0, Y: 1}
+		tables[i][1] = points[i]
+		tables[i][2] = curve.Double(points[i])
+		tables[i][3] = curve.Add(tables[i][2], points[i])
+	}
+
+	res := twistededwards.Point{X: 0, Y: 1}
+	first := true
+	for k := 2 * (nbWindows - 1); k >= 0; k -= 2 {
+		if !first {
+			res = curve.Double(curve.Double(res))
+		}
+		for i := range points {
+			if k >= len(bits[i]) {
+				continue
+			}
+			t := &tables[i]
+			var tmp twistededwards.Point
+			tmp.X = api.Lookup2(bits[i][k], bits[i][k+1], t[0].X, t[1].X, t[2].X, t[3].X)
+			tmp.Y = api.Lookup2(bits[i][k], bits[i][k+1], t[0].Y, t[1].Y, t[2].Y, t[3].Y)
+			if first {
+				res = tmp
+				first = false
+			} else {
+				res = curve.Add(res, tmp)
+			}
+		}
+	}
+	return res
+}
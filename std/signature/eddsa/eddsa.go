@@ -69,19 +69,33 @@ func Verify(curve twistededwards.Curve, sig Signature, msg frontend.Variable, pu
 	}
 
 	//[S]G-[H(R,A,M)]*A
+	// G is fixed: [S]G uses precomputed tables instead of doublings
 	_A := curve.Neg(pubKey.A)
-	Q := curve.DoubleBaseScalarMul(base, _A, sig.S, hRAM)
+	Q := curve.Add(curve.FixedBaseScalarMul(base, sig.S), curve.MultiScalarMul([]twistededwards.Point{_A}, []frontend.Variable{hRAM}))
 	curve.AssertIsOnCurve(Q)
 
 	//[S]G-[H(R,A,M)]*A-R
 	Q = curve.Add(curve.Neg(Q), sig.R)
 
 	// [cofactor]*(lhs-rhs)
+	Q, err := clearCofactor(curve, Q)
+	if err != nil {
+		return err
+	}
+
+	curve.API().AssertIsEqual(Q.X, 0)
+	curve.API().AssertIsEqual(Q.Y, 1)
+
+	return nil
+}
+
+// clearCofactor returns [cofactor]Q
+func clearCofactor(curve twistededwards.Curve, Q twistededwards.Point) (twistededwards.Point, error) {
 	log := logger.Logger()
 	if !curve.Params().Cofactor.IsUint64() {
 		err := errors.New("invalid cofactor")
 		log.Err(err).Str("cofactor", curve.Params().Cofactor.String()).Send()
-		return err
+		return Q, err
 	}
 	cofactor := curve.Params().Cofactor.Uint64()
 	switch cofactor {
@@ -92,11 +106,7 @@ func Verify(curve twistededwards.Curve, sig Signature, msg frontend.Variable, pu
 	default:
 		log.Warn().Str("cofactor", curve.Params().Cofactor.String()).Msg("curve cofactor is not implemented")
 	}
-
-	curve.API().AssertIsEqual(Q.X, 0)
-	curve.API().AssertIsEqual(Q.Y, 1)
-
-	return nil
+	return Q, nil
 }
 
 // Assign is a helper to assigned a compressed binary public key representation into its uncompressed form
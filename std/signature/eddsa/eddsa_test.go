@@ -138,3 +138,93 @@ func TestEddsa(t *testing.T) {
 	}
 
 }
+
+type batchCircuit struct {
+	curveID    tedwards.ID
+	PublicKeys []PublicKey         `gnark:",public"`
+	Signatures []Signature         `gnark:",public"`
+	Messages   []frontend.Variable `gnark:",public"`
+}
+
+func (circuit *batchCircuit) Define(api frontend.API) error {
+
+	curve, err := twistededwards.NewEdCurve(api, circuit.curveID)
+	if err != nil {
+		return err
+	}
+
+	mimc, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+
+	return BatchVerify(curve, circuit.Signatures, circuit.Messages, circuit.PublicKeys, &mimc)
+}
+
+func TestBatchVerify(t *testing.T) {
+
+	assert := test.NewAssert(t)
+
+	confs := []struct {
+		hash  hash.Hash
+		curve tedwards.ID
+	}{
+		{hash.MIMC_BN254, tedwards.BN254},
+		{hash.MIMC_BLS12_377, tedwards.BLS12_377},
+		{hash.MIMC_BW6_761, tedwards.BW6_761},
+	}
+
+	// the 4 randomizers don't fit in a single challenge
+	const n = 5
+
+	seed := time.Now().Unix()
+	t.Logf("setting seed in rand %d", seed)
+	randomness := rand.New(rand.NewSource(seed))
+
+	for _, conf := range confs {
+
+		snarkCurve, err := twistededwards.GetSnarkCurve(conf.curve)
+		assert.NoError(err)
+
+		circuit := batchCircuit{
+			curveID:    conf.curve,
+			PublicKeys: make([]PublicKey, n),
+			Signatures: make([]Signature, n),
+			Messages:   make([]frontend.Variable, n),
+		}
+		witness := batchCircuit{
+			PublicKeys: make([]PublicKey, n),
+			Signatures: make([]Signature, n),
+			Messages:   make([]frontend.Variable, n),
+		}
+		for i := 0; i < n; i++ {
+			privKey, err := eddsa.New(conf.curve, randomness)
+			assert.NoError(err, "generating eddsa key pair")
+
+			var msg big.Int
+			msg.Rand(randomness, snarkCurve.Info().Fr.Modulus())
+			msgData := msg.Bytes()
+
+			signature, err := privKey.Sign(msgData[:], conf.hash.New())
+			assert.NoError(err, "signing message")
+
+			witness.Messages[i] = msg
+			witness.PublicKeys[i].Assign(snarkCurve, privKey.Public().Bytes())
+			witness.Signatures[i].Assign(snarkCurve, signature)
+		}
+
+		assert.SolvingSucceeded(&circuit, &witness, test.WithCurves(snarkCurve))
+
+		// one incorrect message
+		wrong := witness
+		wrong.Messages = append([]frontend.Variable{}, witness.Messages...)
+		wrong.Messages[n-1] = 42
+		assert.SolvingFailed(&circuit, &wrong, test.WithCurves(snarkCurve))
+
+		// swapped signatures
+		wrong = witness
+		wrong.Signatures = append([]Signature{}, witness.Signatures...)
+		wrong.Signatures[0], wrong.Signatures[1] = wrong.Signatures[1], wrong.Signatures[0]
+		assert.SolvingFailed(&circuit, &wrong, test.WithCurves(snarkCurve))
+	}
+}
@@ -47,6 +47,7 @@ type Assert struct {
 	t *testing.T
 	*require.Assertions
 	compiled map[string]frontend.CompiledConstraintSystem // cache compilation
+	invalid  map[string][]frontend.Circuit                // invalid assignments, replayed by MutationTest
 }
 
 // NewAssert returns an Assert helper embedding a testify/require object for convenience
@@ -56,7 +57,12 @@ type Assert struct {
 // the first call to assert.ProverSucceeded/Failed will compile the circuit for n curves, m backends
 // and subsequent calls will re-use the result of the compilation, if available.
 func NewAssert(t *testing.T) *Assert {
-	return &Assert{t: t, Assertions: require.New(t), compiled: make(map[string]frontend.CompiledConstraintSystem)}
+	return &Assert{
+		t:          t,
+		Assertions: require.New(t),
+		compiled:   make(map[string]frontend.CompiledConstraintSystem),
+		invalid:    make(map[string][]frontend.Circuit),
+	}
 }
 
 // Run runs the test function fn as a subtest. The subtest is parametrized by
@@ -68,7 +74,7 @@ func (assert *Assert) Run(fn func(assert *Assert), descs ...string) {
 		// the tests in parallel will result in undetermined behaviour. A better
 		// approach would be to synchronize compiled and run the tests in
 		// parallel for a potential speedup.
-		assert := &Assert{t, require.New(t), assert.compiled}
+		assert := &Assert{t, require.New(t), assert.compiled, assert.invalid}
 		fn(assert)
 	})
 }
@@ -135,6 +141,11 @@ func (assert *Assert) ProverSucceeded(circuit frontend.Circuit, validAssignment
 				err = IsSolved(circuit, validAssignment, curve, backend.UNKNOWN)
 				checkError(err)
 
+				if opt.traceComparison {
+					err = CompareTraces(circuit, validAssignment, curve, b, opt.proverOpts...)
+					checkError(err)
+				}
+
 				assert.t.Parallel()
 
 				switch b {
@@ -187,6 +198,7 @@ func (assert *Assert) ProverSucceeded(circuit frontend.Circuit, validAssignment
 func (assert *Assert) ProverFailed(circuit frontend.Circuit, invalidAssignment frontend.Circuit, opts ...TestingOption) {
 
 	opt := assert.options(opts...)
+	assert.recordInvalidAssignment(circuit, invalidAssignment)
 
 	popts := append(opt.proverOpts, backend.IgnoreSolverError())
 
@@ -280,10 +292,16 @@ func (assert *Assert) solvingSucceeded(circuit frontend.Circuit, validAssignment
 	err = ccs.IsSolved(validWitness, opt.proverOpts...)
 	checkError(err)
 
+	if opt.traceComparison {
+		err = CompareTraces(circuit, validAssignment, curve, b, opt.proverOpts...)
+		checkError(err)
+	}
+
 }
 
 func (assert *Assert) SolvingFailed(circuit frontend.Circuit, invalidWitness frontend.Circuit, opts ...TestingOption) {
 	opt := assert.options(opts...)
+	assert.recordInvalidAssignment(circuit, invalidWitness)
 
 	for _, curve := range opt.curves {
 		for _, b := range opt.backends {
@@ -342,7 +360,7 @@ func (assert *Assert) GetCounters(circuit frontend.Circuit, opts ...TestingOptio
 // Fuzz fuzzes the given circuit by instantiating "randomized" witnesses and cross checking
 // execution result between constraint system solver and big.Int test execution engine
 //
-// note: this is experimental and will be more tightly integrated with go1.18 built-in fuzzing
+// note: this is experimental; see FuzzTarget for go1.18 built-in fuzzing
 func (assert *Assert) Fuzz(circuit frontend.Circuit, fuzzCount int, opts ...TestingOption) {
 	opt := assert.options(opts...)
 
@@ -55,6 +55,12 @@ type engine struct {
 //
 // This is an experimental feature.
 func IsSolved(circuit, witness frontend.Circuit, curveID ecc.ID, b backend.ID, opts ...backend.ProverOption) (err error) {
+	return isSolved(circuit, witness, curveID, b, nil, opts...)
+}
+
+// isSolved executes the circuit with the test engine; if wrap is set, the engine is wrapped before
+// being passed to circuit.Define
+func isSolved(circuit, witness frontend.Circuit, curveID ecc.ID, b backend.ID, wrap func(frontend.API) frontend.API, opts ...backend.ProverOption) (err error) {
 	// apply options
 	opt, err := backend.NewProverConfig(opts...)
 	if err != nil {
@@ -84,11 +90,68 @@ func IsSolved(circuit, witness frontend.Circuit, curveID ecc.ID, b backend.ID, o
 		}
 	}()
 
-	err = c.Define(e)
+	var api frontend.API = e
+	if wrap != nil {
+		api = wrap(e)
+	}
+
+	// public outputs are assigned in the witness and must match the values computed in Define
+	outputs, err := collectOutputs(c)
+	if err != nil {
+		return err
+	}
+
+	if err = c.Define(api); err != nil {
+		return
+	}
+
+	err = e.checkOutputs(c, outputs)
 
 	return
 }
 
+// collectOutputs returns the values of the public outputs of the circuit (see schema.Tag), by name
+func collectOutputs(c frontend.Circuit) (map[string]frontend.Variable, error) {
+	s, err := schema.Parse(c, tVariable, nil)
+	if err != nil || len(s.Outputs) == 0 {
+		return nil, err
+	}
+	outputs := make(map[string]frontend.Variable, len(s.Outputs))
+	var handler schema.LeafHandler = func(visibility schema.Visibility, name string, tInput reflect.Value) error {
+		if s.IsOutput(name) {
+			outputs[name] = tInput.Interface()
+		}
+		return nil
+	}
+	_, err = schema.Parse(c, tVariable, handler)
+	return outputs, err
+}
+
+// checkOutputs returns an error if the values of the public outputs set by circuit.Define
+// don't match the expected ones
+func (e *engine) checkOutputs(c frontend.Circuit, expected map[string]frontend.Variable) error {
+	if len(expected) == 0 {
+		return nil
+	}
+	var handler schema.LeafHandler = func(visibility schema.Visibility, name string, tInput reflect.Value) error {
+		w, ok := expected[name]
+		if !ok {
+			return nil
+		}
+		v := tInput.Interface()
+		if v == nil {
+			return fmt.Errorf("output %s not assigned in Define", name)
+		}
+		b1, b2 := e.toBigInt(v), e.toBigInt(w)
+		if b1.Cmp(&b2) != 0 {
+			return fmt.Errorf("output %s: computed %s, witness %s", name, b1.String(), b2.String())
+		}
+		return nil
+	}
+	_, err := schema.Parse(c, tVariable, handler)
+	return err
+}
+
 func (e *engine) Add(i1, i2 frontend.Variable, in ...frontend.Variable) frontend.Variable {
 	b1, b2 := e.toBigInt(i1), e.toBigInt(i2)
 	b1.Add(&b1, &b2)
@@ -129,3 +129,44 @@ var tVariable reflect.Type
 func init() {
 	tVariable = reflect.ValueOf(struct{ A frontend.Variable }{}).FieldByName("A").Type()
 }
+
+// bytesFiller returns a filler that decodes the witness values from data.
+//
+// For each secret or public input, in schema order, the first byte is a length (modulo the
+// field element size + 1) and the following bytes are the big-endian encoding of the value,
+// reduced modulo the scalar field. Missing bytes are treated as zeroes; this makes small values
+// (0, 1, ...) easy to reach for a fuzzer mutating data.
+func bytesFiller(data []byte) filler {
+	return func(w frontend.Circuit, curve ecc.ID) {
+		m := curve.Info().Fr.Modulus()
+		frBytes := (curve.Info().Fr.Bits + 7) / 8
+
+		next := func(n int) []byte {
+			r := make([]byte, n)
+			copy(r, data)
+			if len(data) < n {
+				data = data[len(data):]
+			} else {
+				data = data[n:]
+			}
+			return r
+		}
+
+		fill(w, func() interface{} {
+			l := int(next(1)[0]) % (frBytes + 1)
+			r := new(big.Int).SetBytes(next(l))
+			return r.Mod(r, m)
+		})
+	}
+}
+
+// encodeFuzzValues is the inverse of bytesFiller; it is used to build the seed corpus
+func encodeFuzzValues(values []*big.Int) []byte {
+	var data []byte
+	for _, v := range values {
+		b := v.Bytes()
+		data = append(data, byte(len(b)))
+		data = append(data, b...)
+	}
+	return data
+}
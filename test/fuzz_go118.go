@@ -0,0 +1,95 @@
+//go:build go1.18
+// +build go1.18
+
+package test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/schema"
+	"github.com/stretchr/testify/require"
+)
+
+// NewFuzzAssert returns an Assert helper to register circuits as native go fuzz targets on f (see
+// Assert.FuzzTarget).
+//
+// The returned Assert isn't bound to a *testing.T: each fuzz input is checked with an Assert bound to
+// the input's *testing.T, sharing the compiled circuits cache of the returned one. Run, Log and the
+// Prover* / Solving* methods must not be called on it.
+func NewFuzzAssert(f *testing.F) *Assert {
+	return &Assert{
+		Assertions: require.New(f),
+		compiled:   make(map[string]frontend.CompiledConstraintSystem),
+		invalid:    make(map[string][]frontend.Circuit),
+	}
+}
+
+// FuzzTarget registers circuit as a native go fuzz target on f.
+//
+// The fuzzer input is mapped to the circuit witness (see the leaves of its schema); each input is
+// executed with the test engine and with the constraint system solvers of the configured curves
+// and backends, and the results must agree: if the test engine accepts the witness, the solver must
+// solve the constraint system; if it rejects it, the solver must fail.
+//
+// The options are parsed once, as for the other Assert methods, and apply to every fuzz input; the
+// compiled circuits are cached in assert across inputs.
+//
+// The seed corpus contains zero, binary and edge case (see seedCorpus) witnesses. Failing inputs
+// are saved by go test in testdata/fuzz, as for any go fuzz test:
+//
+// 		func FuzzMyCircuit(f *testing.F) {
+// 			assert := test.NewFuzzAssert(f)
+// 			assert.FuzzTarget(f, &myCircuit{})
+// 		}
+//
+// 		go test -fuzz=FuzzMyCircuit
+//
+// note: Fuzz is the experimental fuzzer running a fixed number of random witnesses, which doesn't
+// need go1.18
+func (assert *Assert) FuzzTarget(f *testing.F, circuit frontend.Circuit, opts ...TestingOption) {
+	opt := assert.options(opts...)
+
+	// count the inputs
+	s, err := schema.Parse(circuit, tVariable, nil)
+	if err != nil {
+		f.Fatal(err)
+	}
+	nbInputs := s.NbPublic + s.NbSecret
+
+	// seed corpus
+	seed := func(next func(i int) *big.Int) {
+		values := make([]*big.Int, nbInputs)
+		for i := range values {
+			values[i] = next(i)
+		}
+		f.Add(encodeFuzzValues(values))
+	}
+	seed(func(int) *big.Int { return big.NewInt(0) })
+	seed(func(int) *big.Int { return big.NewInt(1) })
+	seed(func(i int) *big.Int { return big.NewInt(int64(i % 2)) })
+	for j := range seedCorpus {
+		seed(func(i int) *big.Int {
+			v := seedCorpus[(i+j)%len(seedCorpus)]
+			if v.Sign() < 0 {
+				return new(big.Int).Neg(v)
+			}
+			return v
+		})
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		assert := &Assert{t, require.New(t), assert.compiled, assert.invalid}
+
+		w := shallowClone(circuit)
+
+		for _, curve := range opt.curves {
+			for _, b := range opt.backends {
+				_, err := assert.compile(circuit, curve, b, opt.compileOpts)
+				assert.NoError(err)
+				assert.fuzzer(bytesFiller(data), circuit, w, b, curve, &opt)
+			}
+		}
+	})
+}
@@ -0,0 +1,27 @@
+//go:build go1.18
+// +build go1.18
+
+package test
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+)
+
+type fuzzCircuit struct {
+	X, Y frontend.Variable
+	Z    frontend.Variable `gnark:",public"`
+}
+
+func (circuit *fuzzCircuit) Define(api frontend.API) error {
+	api.AssertIsBoolean(circuit.X)
+	api.AssertIsEqual(api.Select(circuit.X, circuit.Y, api.Mul(circuit.Y, circuit.Y)), circuit.Z)
+	return nil
+}
+
+func FuzzFuzzTarget(f *testing.F) {
+	assert := NewFuzzAssert(f)
+	assert.FuzzTarget(f, &fuzzCircuit{}, WithCurves(ecc.BN254))
+}
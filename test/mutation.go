@@ -0,0 +1,328 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/compiled"
+)
+
+// Mutant is a compiled circuit from which exactly one constraint was deleted.
+type Mutant struct {
+	// Constraint is the index of the deleted constraint
+	Constraint int
+	// Formula is the human readable representation of the deleted constraint
+	// (see CompiledConstraintSystem.GetConstraints)
+	Formula string
+	// Killed is true if at least one invalid assignment solves the mutated constraint system,
+	// that is, if the negative tests notice the deleted constraint
+	Killed bool
+	// Skipped is set if the solver could not run on the mutated constraint system
+	// (for example when the deleted constraint was the only one referencing a hint)
+	Skipped bool
+}
+
+// MutationReport holds the result of a mutation test for a given curve and backend.
+type MutationReport struct {
+	Curve   ecc.ID
+	Backend backend.ID
+
+	// NbConstraints is the number of constraints in the original constraint system
+	NbConstraints int
+	// NbDefinitions is the number of constraints that are used by the solver to compute
+	// a wire. These are not mutated: deleting them frees the wire, but the honest solver
+	// still computes the same value, so no assignment can notice the difference.
+	NbDefinitions int
+
+	Mutants []Mutant
+}
+
+// Score returns the ratio of killed mutants among the mutants that were evaluated.
+// It returns 1 if no mutant was evaluated.
+func (r MutationReport) Score() float64 {
+	var killed, total int
+	for _, m := range r.Mutants {
+		if m.Skipped {
+			continue
+		}
+		total++
+		if m.Killed {
+			killed++
+		}
+	}
+	if total == 0 {
+		return 1
+	}
+	return float64(killed) / float64(total)
+}
+
+// Survivors returns the mutants which were not killed by any invalid assignment.
+func (r MutationReport) Survivors() []Mutant {
+	var s []Mutant
+	for _, m := range r.Mutants {
+		if !m.Killed && !m.Skipped {
+			s = append(s, m)
+		}
+	}
+	return s
+}
+
+func (r MutationReport) String() string {
+	var sbb strings.Builder
+	survivors := r.Survivors()
+	sbb.WriteString(fmt.Sprintf("%s(%s): mutation score %.2f (%d mutants, %d surviving, %d definitions not mutated)",
+		r.Backend, r.Curve, r.Score(), len(r.Mutants), len(survivors), r.NbDefinitions))
+	for _, m := range survivors {
+		sbb.WriteString(fmt.Sprintf("\n\tsurviving mutant: constraint #%d %s", m.Constraint, m.Formula))
+	}
+	return sbb.String()
+}
+
+// MutationTest measures how well the negative tests of a circuit exercise its constraints.
+//
+// It compiles the circuit and, for each constraint in turn (or a sample, see WithMutationSample), deletes it
+// from the constraint system. It then replays the invalid assignments previously given to ProverFailed
+// and SolvingFailed for this circuit against the mutated constraint system. A mutant is killed if at
+// least one of the invalid assignments solves it; surviving mutants are constraints whose deletion no
+// negative test notices.
+//
+// Constraints the solver uses to compute a wire (for example the output of api.Mul) are not mutated,
+// only the ones that are checked by the solver (assertions, range checks, hint output checks, ...).
+//
+// This is an experimental feature.
+func (assert *Assert) MutationTest(circuit frontend.Circuit, opts ...TestingOption) []MutationReport {
+	opt := assert.options(opts...)
+
+	invalidAssignments := assert.invalid[assert.circuitKey(circuit)]
+	if len(invalidAssignments) == 0 {
+		assert.FailNow("no invalid assignment recorded for this circuit, call ProverFailed or SolvingFailed first")
+	}
+
+	var reports []MutationReport
+
+	for _, curve := range opt.curves {
+		for _, b := range opt.backends {
+			curve := curve
+			b := b
+			assert.Run(func(assert *Assert) {
+				ccs, err := assert.compile(circuit, curve, b, opt.compileOpts)
+				assert.NoError(err)
+
+				report, err := mutationTest(ccs, invalidAssignments, curve, b, &opt)
+				assert.NoError(err)
+
+				assert.Log(report.String())
+				reports = append(reports, report)
+			}, curve.String(), b.String(), "mutation")
+		}
+	}
+
+	return reports
+}
+
+func mutationTest(ccs frontend.CompiledConstraintSystem, invalidAssignments []frontend.Circuit, curve ecc.ID, b backend.ID, opt *testingConfig) (MutationReport, error) {
+	report := MutationReport{Curve: curve, Backend: b, NbConstraints: ccs.GetNbConstraints()}
+
+	invalidWitnesses := make([]*witness.Witness, 0, len(invalidAssignments))
+	for _, a := range invalidAssignments {
+		w, err := frontend.NewWitness(a, curve)
+		if err != nil {
+			return report, fmt.Errorf("can't parse invalid assignment: %w", err)
+		}
+		invalidWitnesses = append(invalidWitnesses, w)
+	}
+
+	checks := checkedConstraints(ccs)
+	report.NbDefinitions = ccs.GetNbConstraints() - len(checks)
+
+	if opt.mutationSample > 0 && opt.mutationSample < len(checks) {
+		sample := make([]int, opt.mutationSample)
+		for i := range sample {
+			sample[i] = checks[i*len(checks)/opt.mutationSample]
+		}
+		checks = sample
+	}
+
+	formulas := ccs.GetConstraints()
+
+	for _, cID := range checks {
+		mutant := Mutant{Constraint: cID, Formula: formatConstraint(formulas[cID])}
+		mutated := deleteConstraint(ccs, cID)
+		for _, w := range invalidWitnesses {
+			solved, err := isSolvedNoPanic(mutated, w, opt.proverOpts...)
+			if err != nil {
+				mutant.Skipped = true
+				break
+			}
+			if solved {
+				mutant.Killed = true
+				break
+			}
+		}
+		report.Mutants = append(report.Mutants, mutant)
+	}
+
+	return report, nil
+}
+
+// isSolvedNoPanic returns true if the witness solves the constraint system, and an error
+// if the solver panicked.
+func isSolvedNoPanic(ccs frontend.CompiledConstraintSystem, w *witness.Witness, opts ...backend.ProverOption) (solved bool, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	return ccs.IsSolved(w, opts...) == nil, nil
+}
+
+// checkedConstraints returns the indexes of the constraints which the solver checks
+// without using them to compute a wire.
+//
+// It replays the solver order (cs.Levels); a constraint is a definition if it references
+// a wire which is neither an input, a hint output or a wire defined by a previous constraint.
+func checkedConstraints(ccs frontend.CompiledConstraintSystem) []int {
+	v := reflect.ValueOf(ccs).Elem()
+	cs := v.FieldByName("ConstraintSystem").Addr().Interface().(*compiled.ConstraintSystem)
+
+	nbWires := cs.NbPublicVariables + cs.NbSecretVariables + cs.NbInternalVariables
+	solved := make([]bool, nbWires)
+	for i := 0; i < cs.NbPublicVariables+cs.NbSecretVariables; i++ {
+		solved[i] = true
+	}
+
+	// isDefinition marks the unsolved wires in the constraint as solved
+	// and returns true if there was one.
+	isDefinition := func(wires []int) bool {
+		r := false
+		for _, wID := range wires {
+			if solved[wID] {
+				continue
+			}
+			if _, ok := cs.MHints[wID]; ok {
+				continue
+			}
+			solved[wID] = true
+			r = true
+		}
+		return r
+	}
+
+	var wires func(cID int) []int
+	switch constraints := v.FieldByName("Constraints").Interface().(type) {
+	case []compiled.R1C:
+		wires = func(cID int) []int {
+			var r []int
+			c := constraints[cID]
+			for _, l := range []compiled.LinearExpression{c.L, c.R, c.O} {
+				for _, t := range l {
+					r = append(r, t.WireID())
+				}
+			}
+			return r
+		}
+	case []compiled.SparseR1C:
+		wires = func(cID int) []int {
+			var r []int
+			c := constraints[cID]
+			if c.L.CoeffID() != compiled.CoeffIdZero || c.M[0].CoeffID() != compiled.CoeffIdZero {
+				r = append(r, c.L.WireID())
+			}
+			if c.R.CoeffID() != compiled.CoeffIdZero || c.M[1].CoeffID() != compiled.CoeffIdZero {
+				r = append(r, c.R.WireID())
+			}
+			if c.O.CoeffID() != compiled.CoeffIdZero {
+				r = append(r, c.O.WireID())
+			}
+			return r
+		}
+	default:
+		panic("not implemented")
+	}
+
+	var checks []int
+	for _, level := range cs.Levels {
+		for _, cID := range level {
+			if !isDefinition(wires(cID)) {
+				checks = append(checks, cID)
+			}
+		}
+	}
+
+	return checks
+}
+
+// deleteConstraint returns a copy of ccs where the constraint cID is trivially satisfied.
+//
+// ccs is shallow copied; only the constraint slice is duplicated.
+// The wires referenced by the deleted constraint are kept in a R1CS so that hints are still
+// solved in the same order.
+func deleteConstraint(ccs frontend.CompiledConstraintSystem, cID int) frontend.CompiledConstraintSystem {
+	v := reflect.ValueOf(ccs).Elem()
+	mutated := reflect.New(v.Type())
+	mutated.Elem().Set(v)
+
+	f := mutated.Elem().FieldByName("Constraints")
+	constraints := reflect.MakeSlice(f.Type(), f.Len(), f.Len())
+	reflect.Copy(constraints, f)
+
+	switch c := constraints.Index(cID).Addr().Interface().(type) {
+	case *compiled.R1C:
+		// L ⋅ 0 == 0
+		l := make(compiled.LinearExpression, 0, len(c.L)+len(c.R)+len(c.O))
+		l = append(l, c.L...)
+		l = append(l, c.R...)
+		l = append(l, c.O...)
+		*c = compiled.R1C{L: l}
+	case *compiled.SparseR1C:
+		*c = compiled.SparseR1C{}
+	default:
+		panic("not implemented")
+	}
+	f.Set(constraints)
+
+	return mutated.Interface().(frontend.CompiledConstraintSystem)
+}
+
+// formatConstraint formats an entry of CompiledConstraintSystem.GetConstraints
+func formatConstraint(c []string) string {
+	if len(c) == 3 {
+		// R1CS
+		return fmt.Sprintf("%s ⋅ %s == %s", c[0], c[1], c[2])
+	}
+	return strings.Join(c, " + ") + " == 0"
+}
+
+// circuitKey identifies a circuit (independently of the curve and backend)
+func (assert *Assert) circuitKey(circuit frontend.Circuit) string {
+	addr, err := assert.getCircuitAddr(circuit)
+	assert.NoError(err)
+	return fmt.Sprintf("%s%d", reflect.TypeOf(circuit).String(), addr)
+}
+
+// recordInvalidAssignment stores the invalid assignment so that it can be replayed by MutationTest
+func (assert *Assert) recordInvalidAssignment(circuit, invalidAssignment frontend.Circuit) {
+	key := assert.circuitKey(circuit)
+	assert.invalid[key] = append(assert.invalid[key], shallowClone(invalidAssignment))
+}
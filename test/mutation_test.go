@@ -0,0 +1,35 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+)
+
+type mutationCircuit struct {
+	X, Y, Z frontend.Variable
+}
+
+func (circuit *mutationCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Mul(circuit.X, circuit.X), circuit.Y)
+	api.AssertIsBoolean(circuit.Z)
+	return nil
+}
+
+func TestMutation(t *testing.T) {
+	assert := NewAssert(t)
+
+	var circuit mutationCircuit
+
+	// only X*X == Y is exercised by a negative test
+	assert.SolvingFailed(&circuit, &mutationCircuit{X: 2, Y: 5, Z: 1}, WithCurves(ecc.BN254))
+
+	reports := assert.MutationTest(&circuit, WithCurves(ecc.BN254))
+	assert.NotEmpty(reports)
+	for _, r := range reports {
+		assert.Equal(1, len(r.Survivors()), r.String())
+		assert.Less(r.Score(), 1.0)
+		assert.Greater(r.Score(), 0.0)
+	}
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package test
 
 import (
+	"errors"
+
 	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/frontend"
@@ -33,6 +35,8 @@ type testingConfig struct {
 	witnessSerialization bool
 	proverOpts           []backend.ProverOption
 	compileOpts          []frontend.CompileOption
+	mutationSample       int
+	traceComparison      bool
 }
 
 // WithBackends is testing option which restricts the backends the assertions are
@@ -82,3 +86,25 @@ func WithCompileOpts(compileOpts ...frontend.CompileOption) TestingOption {
 		return nil
 	}
 }
+
+// WithMutationSample is a testing option which restricts Assert.MutationTest to
+// at most n mutants, evenly spread over the constraint system. When not given,
+// every constraint that can be deleted is mutated.
+func WithMutationSample(n int) TestingOption {
+	return func(opt *testingConfig) error {
+		if n <= 0 {
+			return errors.New("mutation sample size must be positive")
+		}
+		opt.mutationSample = n
+		return nil
+	}
+}
+
+// WithTraceComparison is a testing option which, for valid assignments, compares the values
+// computed by the test engine with the wires solved by the constraint system solvers (see CompareTraces).
+func WithTraceComparison() TestingOption {
+	return func(opt *testingConfig) error {
+		opt.traceComparison = true
+		return nil
+	}
+}
@@ -0,0 +1,395 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/hint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/frontend/schema"
+	"github.com/consensys/gnark/internal/utils"
+	"github.com/rs/zerolog"
+)
+
+// traceMarker prefixes the log lines used to record the builder trace
+const traceMarker = "gnark-trace"
+
+// TraceDivergenceError is returned by CompareTraces when a value computed by the test engine
+// differs from the value of the corresponding wire computed by the constraint system solver.
+type TraceDivergenceError struct {
+	Backend backend.ID
+	Curve   ecc.ID
+
+	Call   int    // index of the API call in the trace
+	Method string // name of the API method
+	Caller string // file:line of the API call
+	Output int    // index of the diverging output (ToBinary and NewHint have several outputs)
+
+	Engine, Solver string // values computed by the test engine and the solver
+}
+
+func (e *TraceDivergenceError) Error() string {
+	return fmt.Sprintf("%s(%s): call #%d api.%s (%s) output %d diverges: test engine %s, solver %s",
+		e.Backend, e.Curve, e.Call, e.Method, e.Caller, e.Output, e.Engine, e.Solver)
+}
+
+// CompareTraces executes the circuit with the test engine and with the constraint system solver of
+// the given backend (R1CS for groth16, SparseR1CS for plonk). It records every Variable returned
+// by the frontend.API methods called from circuit.Define and returns a *TraceDivergenceError
+// describing the first call where the test engine and the solver disagree.
+//
+// The traces are aligned by call order; if the two executions do not make the same sequence of
+// API calls (for example because a gadget branches on api.Compiler().ConstantValue), the first
+// misaligned call is reported as an error.
+//
+// This is an experimental feature.
+func CompareTraces(circuit, assignment frontend.Circuit, curveID ecc.ID, b backend.ID, opts ...backend.ProverOption) error {
+	// execute with the test engine
+	var engineTrace []traceEntry
+	err := isSolved(circuit, assignment, curveID, b, func(api frontend.API) frontend.API {
+		return newTracer(api, func(e traceEntry) {
+			engineTrace = append(engineTrace, e)
+		})
+	}, opts...)
+	if err != nil {
+		return fmt.Errorf("test engine: %w", err)
+	}
+
+	// compile with a tracing builder
+	var newBuilder frontend.NewBuilder
+	switch b {
+	case backend.GROTH16:
+		newBuilder = r1cs.NewBuilder
+	case backend.PLONK:
+		newBuilder = scs.NewBuilder
+	default:
+		panic("not implemented")
+	}
+	var builderTrace []traceEntry
+	ccs, err := frontend.Compile(curveID, newTracedBuilder(newBuilder, func(e traceEntry) {
+		builderTrace = append(builderTrace, e)
+	}), circuit)
+	if err != nil {
+		return fmt.Errorf("compile: %w", err)
+	}
+
+	// solve, the resolved values are printed in the circuit logger
+	w, err := frontend.NewWitness(assignment, curveID)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	solverErr := ccs.IsSolved(w, append(opts, backend.WithCircuitLogger(zerolog.New(&buf)))...)
+
+	solverValues, err := parseTrace(&buf)
+	if err != nil {
+		return err
+	}
+
+	modulus := curveID.Info().Fr.Modulus()
+	for i := 0; i < len(engineTrace) || i < len(builderTrace); i++ {
+		if i >= len(engineTrace) || i >= len(builderTrace) || engineTrace[i].method != builderTrace[i].method {
+			engineCall, builderCall := "<none>", "<none>"
+			if i < len(engineTrace) {
+				engineCall = fmt.Sprintf("api.%s (%s)", engineTrace[i].method, engineTrace[i].caller)
+			}
+			if i < len(builderTrace) {
+				builderCall = fmt.Sprintf("api.%s (%s)", builderTrace[i].method, builderTrace[i].caller)
+			}
+			return fmt.Errorf("%s(%s): call #%d traces are misaligned: test engine called %s, builder called %s",
+				b, curveID, i, engineCall, builderCall)
+		}
+		values, ok := solverValues[i]
+		if !ok {
+			// the solver didn't reach this call
+			break
+		}
+		for j := range values {
+			if values[j] == unsolvedValue {
+				continue
+			}
+			var expected, got big.Int
+			if _, ok := got.SetString(values[j], 10); !ok {
+				return fmt.Errorf("call #%d: can't parse solver value %q", i, values[j])
+			}
+			expected.Set(&engineTrace[i].values[j])
+			expected.Mod(&expected, modulus)
+			got.Mod(&got, modulus)
+			if expected.Cmp(&got) != 0 {
+				return &TraceDivergenceError{
+					Backend: b,
+					Curve:   curveID,
+					Call:    i,
+					Method:  builderTrace[i].method,
+					Caller:  builderTrace[i].caller,
+					Output:  j,
+					Engine:  expected.String(),
+					Solver:  got.String(),
+				}
+			}
+		}
+	}
+
+	if solverErr != nil {
+		return fmt.Errorf("%s(%s): test engine solved the circuit but the solver failed: %w", b, curveID, solverErr)
+	}
+
+	return nil
+}
+
+// unsolvedValue is printed by the solver in place of the value of an unsolved wire
+const unsolvedValue = "<unsolved>"
+
+// parseTrace reads the log lines written by the solver and returns the values
+// recorded by the tracing builder, indexed by call
+func parseTrace(buf *bytes.Buffer) (map[int][]string, error) {
+	r := make(map[int][]string)
+	scanner := bufio.NewScanner(buf)
+	scanner.Buffer(nil, 1<<24)
+	for scanner.Scan() {
+		var line struct {
+			Message string `json:"message"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			return nil, fmt.Errorf("parse solver trace: %w", err)
+		}
+		fields := strings.Fields(line.Message)
+		if len(fields) < 2 || fields[0] != traceMarker {
+			// not ours
+			continue
+		}
+		i, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("parse solver trace: %w", err)
+		}
+		r[i] = fields[2:]
+	}
+	return r, scanner.Err()
+}
+
+// traceEntry records an API call and its outputs
+type traceEntry struct {
+	method string
+	caller string
+	values []big.Int // set by the test engine only
+}
+
+// tracer wraps a frontend.API and records the outputs of the API calls.
+//
+// When wrapping the test engine, outputs are constants and are recorded as is; when wrapping a
+// builder, the outputs are printed with api.Println so that the solver resolves them.
+type tracer struct {
+	frontend.API
+	nbCalls int
+	record  func(traceEntry)
+}
+
+func newTracer(api frontend.API, record func(traceEntry)) *tracer {
+	return &tracer{API: api, record: record}
+}
+
+func (t *tracer) trace(method string, outputs ...frontend.Variable) {
+	e := traceEntry{method: method}
+	// caller of the API method
+	if _, file, line, ok := runtime.Caller(2); ok {
+		e.caller = fmt.Sprintf("%s:%d", filepath.Base(file), line)
+	}
+
+	if _, isEngine := t.API.(*engine); isEngine {
+		e.values = make([]big.Int, len(outputs))
+		for i := range outputs {
+			e.values[i] = utils.FromInterface(outputs[i])
+		}
+	} else {
+		a := make([]frontend.Variable, 0, len(outputs)+2)
+		a = append(a, traceMarker, t.nbCalls)
+		for _, o := range outputs {
+			// constants are not resolved by the solver, we print them directly
+			if c, ok := t.API.Compiler().ConstantValue(o); ok {
+				a = append(a, c.String())
+			} else {
+				a = append(a, o)
+			}
+		}
+		t.API.Println(a...)
+	}
+
+	t.nbCalls++
+	t.record(e)
+}
+
+func (t *tracer) Add(i1, i2 frontend.Variable, in ...frontend.Variable) frontend.Variable {
+	r := t.API.Add(i1, i2, in...)
+	t.trace("Add", r)
+	return r
+}
+
+func (t *tracer) Neg(i1 frontend.Variable) frontend.Variable {
+	r := t.API.Neg(i1)
+	t.trace("Neg", r)
+	return r
+}
+
+func (t *tracer) Sub(i1, i2 frontend.Variable, in ...frontend.Variable) frontend.Variable {
+	r := t.API.Sub(i1, i2, in...)
+	t.trace("Sub", r)
+	return r
+}
+
+func (t *tracer) Mul(i1, i2 frontend.Variable, in ...frontend.Variable) frontend.Variable {
+	r := t.API.Mul(i1, i2, in...)
+	t.trace("Mul", r)
+	return r
+}
+
+func (t *tracer) DivUnchecked(i1, i2 frontend.Variable) frontend.Variable {
+	r := t.API.DivUnchecked(i1, i2)
+	t.trace("DivUnchecked", r)
+	return r
+}
+
+func (t *tracer) Div(i1, i2 frontend.Variable) frontend.Variable {
+	r := t.API.Div(i1, i2)
+	t.trace("Div", r)
+	return r
+}
+
+func (t *tracer) Inverse(i1 frontend.Variable) frontend.Variable {
+	r := t.API.Inverse(i1)
+	t.trace("Inverse", r)
+	return r
+}
+
+func (t *tracer) ToBinary(i1 frontend.Variable, n ...int) []frontend.Variable {
+	r := t.API.ToBinary(i1, n...)
+	t.trace("ToBinary", r...)
+	return r
+}
+
+func (t *tracer) FromBinary(b ...frontend.Variable) frontend.Variable {
+	r := t.API.FromBinary(b...)
+	t.trace("FromBinary", r)
+	return r
+}
+
+func (t *tracer) Xor(a, b frontend.Variable) frontend.Variable {
+	r := t.API.Xor(a, b)
+	t.trace("Xor", r)
+	return r
+}
+
+func (t *tracer) Or(a, b frontend.Variable) frontend.Variable {
+	r := t.API.Or(a, b)
+	t.trace("Or", r)
+	return r
+}
+
+func (t *tracer) And(a, b frontend.Variable) frontend.Variable {
+	r := t.API.And(a, b)
+	t.trace("And", r)
+	return r
+}
+
+func (t *tracer) Select(b frontend.Variable, i1, i2 frontend.Variable) frontend.Variable {
+	r := t.API.Select(b, i1, i2)
+	t.trace("Select", r)
+	return r
+}
+
+func (t *tracer) Lookup2(b0, b1 frontend.Variable, i0, i1, i2, i3 frontend.Variable) frontend.Variable {
+	r := t.API.Lookup2(b0, b1, i0, i1, i2, i3)
+	t.trace("Lookup2", r)
+	return r
+}
+
+func (t *tracer) IsZero(i1 frontend.Variable) frontend.Variable {
+	r := t.API.IsZero(i1)
+	t.trace("IsZero", r)
+	return r
+}
+
+func (t *tracer) Cmp(i1, i2 frontend.Variable) frontend.Variable {
+	r := t.API.Cmp(i1, i2)
+	t.trace("Cmp", r)
+	return r
+}
+
+func (t *tracer) NewHint(f hint.Function, nbOutputs int, inputs ...frontend.Variable) ([]frontend.Variable, error) {
+	r, err := t.API.Compiler().NewHint(f, nbOutputs, inputs...)
+	if err != nil {
+		return r, err
+	}
+	t.trace("NewHint", r...)
+	return r, nil
+}
+
+func (t *tracer) MarkBoolean(v frontend.Variable) {
+	t.API.Compiler().MarkBoolean(v)
+}
+
+func (t *tracer) IsBoolean(v frontend.Variable) bool {
+	return t.API.Compiler().IsBoolean(v)
+}
+
+func (t *tracer) Compiler() frontend.Compiler {
+	return t
+}
+
+// tracedBuilder is a frontend.Builder recording the API calls made in circuit.Define
+type tracedBuilder struct {
+	*tracer
+	builder frontend.Builder
+}
+
+func newTracedBuilder(newBuilder frontend.NewBuilder, record func(traceEntry)) frontend.NewBuilder {
+	return func(curveID ecc.ID, config frontend.CompileConfig) (frontend.Builder, error) {
+		builder, err := newBuilder(curveID, config)
+		if err != nil {
+			return nil, err
+		}
+		return &tracedBuilder{tracer: newTracer(builder, record), builder: builder}, nil
+	}
+}
+
+func (b *tracedBuilder) Compile() (frontend.CompiledConstraintSystem, error) {
+	return b.builder.Compile()
+}
+
+func (b *tracedBuilder) SetSchema(s *schema.Schema) {
+	b.builder.SetSchema(s)
+}
+
+func (b *tracedBuilder) AddPublicVariable(name string) frontend.Variable {
+	return b.builder.AddPublicVariable(name)
+}
+
+func (b *tracedBuilder) AddSecretVariable(name string) frontend.Variable {
+	return b.builder.AddSecretVariable(name)
+}
@@ -0,0 +1,72 @@
+package test
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+)
+
+type traceCircuit struct {
+	X, Y frontend.Variable
+}
+
+func (circuit *traceCircuit) Define(api frontend.API) error {
+	xBits := api.ToBinary(circuit.X, 8)
+	yBits := api.ToBinary(circuit.Y, 8)
+	a := api.Xor(xBits[0], yBits[0])
+	b := api.Lookup2(xBits[1], yBits[1], circuit.X, circuit.Y, 3, 4)
+	c := api.Select(api.Or(a, xBits[2]), api.Mul(b, circuit.Y), api.Sub(circuit.X, b))
+	d := api.Div(c, api.Add(circuit.Y, 1))
+	api.AssertIsEqual(api.Mul(d, api.Add(circuit.Y, 1)), c)
+	api.AssertIsEqual(api.IsZero(api.Sub(circuit.X, circuit.Y)), 0)
+	return nil
+}
+
+func TestCompareTraces(t *testing.T) {
+	for _, b := range backend.Implemented() {
+		if err := CompareTraces(&traceCircuit{}, &traceCircuit{X: 42, Y: 27}, ecc.BN254, b); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// counterHint returns the number of times it was called, so that the test engine and the solver
+// compute different values
+var nbCounterHintCalls int
+
+func counterHint(_ ecc.ID, inputs []*big.Int, outputs []*big.Int) error {
+	nbCounterHintCalls++
+	outputs[0].SetInt64(int64(nbCounterHintCalls))
+	return nil
+}
+
+type divergingCircuit struct {
+	X frontend.Variable
+}
+
+func (circuit *divergingCircuit) Define(api frontend.API) error {
+	r, err := api.Compiler().NewHint(counterHint, 1, circuit.X)
+	if err != nil {
+		return err
+	}
+	// holds for any hint output, since X == 0
+	api.AssertIsEqual(api.Mul(r[0], circuit.X), 0)
+	return nil
+}
+
+func TestCompareTracesDivergence(t *testing.T) {
+	for _, b := range backend.Implemented() {
+		err := CompareTraces(&divergingCircuit{}, &divergingCircuit{X: 0}, ecc.BN254, b, backend.WithHints(counterHint))
+		var divergence *TraceDivergenceError
+		if !errors.As(err, &divergence) {
+			t.Fatalf("%s: expected a divergence, got %v", b, err)
+		}
+		if divergence.Call != 0 || divergence.Method != "NewHint" {
+			t.Fatalf("%s: unexpected divergence %v", b, divergence)
+		}
+	}
+}